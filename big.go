@@ -0,0 +1,116 @@
+package mandy
+
+import (
+	"math/big"
+)
+
+// DefaultFloatPrec is the precision, in bits, of BigFloat flags whose default has none
+const DefaultFloatPrec = 256
+
+// bigNumber is satisfied by *big.Int, *big.Float, and *big.Rat
+type bigNumber[T any] interface {
+	*T
+	Cmp(*T) int
+	Set(*T) *T
+}
+
+// -- math/big Value
+// values outside of [min, max] are rejected, unless the bound is nil
+type bigValue[T any, P bigNumber[T]] struct {
+	p        P
+	min, max P
+	parse    func(string) (P, bool)
+	format   func(P) string
+}
+
+func newBigValue[T any, P bigNumber[T]](val, p, min, max P, parse func(string) (P, bool), format func(P) string) *bigValue[T, P] {
+	if val != nil {
+		p.Set(val)
+	}
+	return &bigValue[T, P]{p: p, min: min, max: max, parse: parse, format: format}
+}
+
+func (b *bigValue[T, P]) Set(s string) error {
+	v, ok := b.parse(s)
+	if !ok {
+		return errParse
+	}
+	if (b.min != nil && v.Cmp(b.min) < 0) || (b.max != nil && v.Cmp(b.max) > 0) {
+		return errRange
+	}
+	b.p.Set(v)
+	return nil
+}
+
+func (b *bigValue[T, P]) String() string {
+	if b.p == nil {
+		return ""
+	}
+	return b.format(b.p)
+}
+
+func (b *bigValue[T, P]) Get() any     { return P(new(T)).Set(b.p) }
+func (b *bigValue[T, P]) IsBool() bool { return false }
+
+func parseBigInt(s string) (*big.Int, bool) {
+	return new(big.Int).SetString(s, 0)
+}
+
+func parseBigFloat(prec uint) func(string) (*big.Float, bool) {
+	return func(s string) (*big.Float, bool) {
+		v, _, err := big.ParseFloat(s, 0, prec, big.ToNearestEven)
+		return v, err == nil
+	}
+}
+
+func parseRat(s string) (*big.Rat, bool) {
+	return new(big.Rat).SetString(s)
+}
+
+func formatBigFloat(f *big.Float) string { return f.Text('g', -1) }
+func formatRat(r *big.Rat) string        { return r.RatString() }
+
+// BigInt defines a big.Int flag with specified name, default value, and usage string.
+// The argument p points to a big.Int variable in which to store the value of the flag.
+// The flag accepts decimal values as well as those with 0x, 0o, and 0b prefixes.
+func (c *Command) BigInt(p *big.Int, name string, value *big.Int, usage string, short bool) *Flag {
+	return c.BigIntRange(p, name, value, nil, nil, usage, short)
+}
+
+// BigIntRange is like BigInt but rejects values less than min or greater than max.
+// A nil bound is not enforced.
+func (c *Command) BigIntRange(p *big.Int, name string, value, min, max *big.Int, usage string, short bool) *Flag {
+	return c.Var(newBigValue(value, p, min, max, parseBigInt, (*big.Int).String), name, usage, short)
+}
+
+// BigFloat defines a big.Float flag with specified name, default value, and usage string.
+// The argument p points to a big.Float variable in which to store the value of the flag.
+// Values are parsed with the precision of the default value, or DefaultFloatPrec if it has none,
+// and may be given in decimal or as hexadecimal mantissas like 0x1p-2.
+func (c *Command) BigFloat(p *big.Float, name string, value *big.Float, usage string, short bool) *Flag {
+	return c.BigFloatRange(p, name, value, nil, nil, usage, short)
+}
+
+// BigFloatRange is like BigFloat but rejects values less than min or greater than max.
+// A nil bound is not enforced.
+func (c *Command) BigFloatRange(p *big.Float, name string, value, min, max *big.Float, usage string, short bool) *Flag {
+	prec := uint(DefaultFloatPrec)
+	if value != nil && value.Prec() > 0 {
+		prec = value.Prec()
+	}
+	p.SetPrec(prec)
+	return c.Var(newBigValue(value, p, min, max, parseBigFloat(prec), formatBigFloat), name, usage, short)
+}
+
+// Rat defines a big.Rat flag with specified name, default value, and usage string.
+// The argument p points to a big.Rat variable in which to store the value of the flag.
+// The flag accepts fractions like 3/4 as well as decimals like 0.75 or 1e-3.
+func (c *Command) Rat(p *big.Rat, name string, value *big.Rat, usage string, short bool) *Flag {
+	return c.RatRange(p, name, value, nil, nil, usage, short)
+}
+
+// RatRange is like Rat but rejects values less than min or greater than max.
+// A nil bound is not enforced.
+func (c *Command) RatRange(p *big.Rat, name string, value, min, max *big.Rat, usage string, short bool) *Flag {
+	return c.Var(newBigValue(value, p, min, max, parseRat, formatRat), name, usage, short)
+}
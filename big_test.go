@@ -0,0 +1,41 @@
+package mandy
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestBigValues(t *testing.T) {
+	var (
+		i big.Int
+		f big.Float
+		r big.Rat
+	)
+	c := NewCommand("test", ContinueOnError)
+	c.BigIntRange(&i, "int", big.NewInt(1), big.NewInt(0), nil, "an int", false)
+	c.BigFloat(&f, "float", nil, "a float", false)
+	c.Rat(&r, "rat", big.NewRat(1, 2), "a ratio", false)
+
+	if got := c.Lookup("rat").DefValue; got != "1/2" {
+		t.Errorf("rat DefValue = %q, want 1/2", got)
+	}
+	if err := c.Parse("--int=0xffffffffffffffffffff", "--float=1.000000000000000000000001", "--rat=3/4"); err != nil {
+		t.Fatal(err)
+	}
+	if want, _ := new(big.Int).SetString("ffffffffffffffffffff", 16); i.Cmp(want) != 0 {
+		t.Errorf("int = %s, want %s", &i, want)
+	}
+	if got := c.Lookup("float").Value.String(); got != "1.000000000000000000000001" {
+		t.Errorf("float = %s", got)
+	}
+	if r.Cmp(big.NewRat(3, 4)) != 0 {
+		t.Errorf("rat = %s, want 3/4", &r)
+	}
+	if err := c.Set("int", "-1"); !errors.Is(err, errRange) {
+		t.Errorf("expected a range error, got %v", err)
+	}
+	if err := c.Set("rat", "half"); !errors.Is(err, errParse) {
+		t.Errorf("expected a parse error, got %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package mandy
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// -- []byte Value
+// arguments take the forms "base64:...", "hex:...", or "@path"
+// the contents are redacted in usage messages and invocation records
+type bytesValue []byte
+
+func newBytesValue(val []byte, p *[]byte) *bytesValue {
+	*p = val
+	return (*bytesValue)(p)
+}
+
+func (b *bytesValue) Set(s string) error {
+	v, err := parseBytes(s)
+	if err != nil {
+		return err
+	}
+	*b = v
+	return nil
+}
+
+// parseBytes decodes the forms accepted by Bytes flags
+func parseBytes(s string) ([]byte, error) {
+	switch {
+	case strings.HasPrefix(s, "base64:"):
+		s = strings.TrimPrefix(s, "base64:")
+		v, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			v, err = base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid base64: %v", errParse, err)
+		}
+		return v, nil
+	case strings.HasPrefix(s, "hex:"):
+		v, err := hex.DecodeString(strings.TrimPrefix(s, "hex:"))
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid hex: %v", errParse, err)
+		}
+		return v, nil
+	case strings.HasPrefix(s, "@"):
+		return os.ReadFile(s[1:])
+	}
+	return nil, fmt.Errorf("%w: expected base64:..., hex:..., or @file", errParse)
+}
+
+func (b *bytesValue) Get() any { return []byte(*b) }
+func (b *bytesValue) String() string {
+	if b == nil || len(*b) == 0 {
+		return ""
+	}
+	return "base64:" + base64.StdEncoding.EncodeToString(*b)
+}
+func (b *bytesValue) IsBool() bool { return false }
+func (b *bytesValue) redacted()    {}
+
+// Bytes defines a []byte flag with specified name, default value, and usage string.
+// The argument p points to a []byte variable in which to store the value of the flag.
+// The flag accepts "base64:" or "hex:" prefixed encodings, or "@" followed by the path of a file to read.
+// Its value is redacted in usage messages and invocation records.
+func (c *Command) Bytes(p *[]byte, name string, value []byte, usage string, short bool) *Flag {
+	return c.Var(newBytesValue(value, p), name, usage, short)
+}
@@ -0,0 +1,43 @@
+package mandy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBytes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key")
+	if err := os.WriteFile(path, []byte("from file"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]string{
+		"base64:aGVsbG8=": "hello",
+		"base64:aGVsbG8":  "hello",
+		"hex:68656c6c6f":  "hello",
+		"@" + path:        "from file",
+	}
+	for arg, want := range tests {
+		var got []byte
+		c := NewCommand("test", ContinueOnError)
+		c.Bytes(&got, "key", nil, "a key", false)
+		if err := c.Parse("--key=" + arg); err != nil {
+			t.Errorf("%s: %v", arg, err)
+			continue
+		}
+		if string(got) != want {
+			t.Errorf("%s gave %q, want %q", arg, got, want)
+		}
+	}
+
+	var key []byte
+	c := NewCommand("test", ContinueOnError)
+	f := c.Bytes(&key, "key", []byte("default"), "a key", false)
+	if err := f.Value.Set("plain"); err == nil {
+		t.Error("expected an error for an unprefixed value")
+	}
+	if usage := f.usage(DefaultInline); strings.Contains(usage, "ZGVmYXVsdA") || !strings.Contains(usage, Redacted) {
+		t.Errorf("default not redacted: %q", usage)
+	}
+}
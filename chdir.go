@@ -0,0 +1,74 @@
+package mandy
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ChdirName is the name of the flag defined by Command.Chdir
+const ChdirName = "chdir"
+
+// -- chdir Value
+// a directory, validated and made absolute when set, that Execute runs Main in
+type chdirValue string
+
+func (d *chdirValue) Set(s string) error {
+	abs, err := filepath.Abs(s)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errParse, err)
+	}
+	info, err := os.Stat(abs)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errParse, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%w: %s is not a directory", errParse, s)
+	}
+	*d = chdirValue(abs)
+	return nil
+}
+
+func (d *chdirValue) Get() any       { return string(*d) }
+func (d *chdirValue) String() string { return string(*d) }
+func (d *chdirValue) IsBool() bool   { return false }
+
+// Chdir defines a flag, named ChdirName, with the given usage string, or a standard one if it is empty,
+// that makes Execute run Main in the given directory, like make's -C.
+// The directory must exist when the flag is parsed, and the working directory is restored when Main returns.
+// If it is set on several commands on the invoked path, the deepest one wins.
+func (c *Command) Chdir(usage string, short bool) *Flag {
+	if usage == "" {
+		usage = "run as if started in `dir`"
+	}
+	return c.Var(new(chdirValue), ChdirName, usage, short)
+}
+
+// workdir returns the directory requested of the last parse by the deepest Chdir flag set
+// on the path from c to the dispatched child, or "" if there is none
+func (c *Command) workdir() (dir string) {
+	for cmd := c; cmd != nil; cmd = cmd.sub {
+		for _, flag := range cmd.actual {
+			if d, ok := flag.Value.(*chdirValue); ok {
+				dir = string(*d)
+			}
+		}
+	}
+	return dir
+}
+
+// runIn runs fn in dir, unless dir is empty, restoring the working directory afterwards
+func runIn(dir string, fn func() error) error {
+	if dir == "" {
+		return fn()
+	}
+	saved, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	if err := os.Chdir(dir); err != nil {
+		return err
+	}
+	defer os.Chdir(saved)
+	return fn()
+}
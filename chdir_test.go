@@ -0,0 +1,52 @@
+package mandy
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestChdir(t *testing.T) {
+	dir := t.TempDir()
+	start, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewCommand("test", ContinueOnError)
+	c.SetOutput(io.Discard)
+	c.Chdir("", true)
+	child := c.NewChild("child", "")
+	var ran string
+	child.Main = func(self *Command) error {
+		ran, err = os.Getwd()
+		return err
+	}
+
+	if err := c.Execute("-c", dir, "child"); err != nil {
+		t.Fatal(err)
+	}
+	if want, _ := filepath.EvalSymlinks(dir); ran != want && ran != dir {
+		t.Errorf("Main ran in %s, want %s", ran, dir)
+	}
+	if now, _ := os.Getwd(); now != start {
+		t.Errorf("working directory after Execute = %s, want %s", now, start)
+	}
+	if inv := c.Invocation(); inv.Dir != dir {
+		t.Errorf("Invocation().Dir = %q, want %q", inv.Dir, dir)
+	}
+
+	missing := filepath.Join(dir, "missing")
+	if err := c.Execute("--chdir", missing, "child"); !errors.Is(err, errParse) {
+		t.Errorf("Execute(--chdir %s) = %v, want errParse", missing, err)
+	}
+	file := filepath.Join(dir, "file")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Execute("--chdir", file, "child"); !errors.Is(err, errParse) {
+		t.Errorf("Execute(--chdir %s) = %v, want errParse", file, err)
+	}
+}
@@ -0,0 +1,87 @@
+package mandy
+
+import (
+	"errors"
+	"fmt"
+)
+
+// The errors reported by Check wrap one of these
+var (
+	ErrCollision  = errors.New("mandy: a child and a flag share a name")
+	ErrShorthand  = errors.New("mandy: a shorthand is shared with an ancestor's flag")
+	ErrNoUsage    = errors.New("mandy: a flag has no usage string")
+	ErrNoMain     = errors.New("mandy: a command has neither a Main function nor children")
+	ErrShadowed   = errors.New("mandy: a child is shadowed by an earlier sibling")
+	ErrFormat     = errors.New("mandy: a usage format does not include the command's name")
+	ErrNoExamples = errors.New("mandy: a leaf command has no example")
+)
+
+// Check reports structural problems with the command and its descendants:
+// children named like their parent's flags, other than help, shorthands also used by an ancestor's flags,
+// flags without usage strings, commands with neither Main nor children,
+// children unreachable because an earlier sibling has the same name or alias,
+// Formats without a %s (or %v) verb for the command's name, and leaf commands without an Example.
+// It is meant to be run in an application's tests.
+func (c *Command) Check() (errs []error) {
+	name := c.name_()
+	if !isFstr(c.Format) {
+		errs = append(errs, fmt.Errorf("%w: %s has format %q", ErrFormat, name, c.Format))
+	}
+	if c.Main == nil && len(c.children) == 0 {
+		errs = append(errs, fmt.Errorf("%w: %s", ErrNoMain, name))
+	}
+	if len(c.children) == 0 && c.Example == "" {
+		errs = append(errs, fmt.Errorf("%w: %s", ErrNoExamples, name))
+	}
+	for flag := range c.Flags() {
+		if flag.Description == "" {
+			errs = append(errs, fmt.Errorf("%w: %s --%s", ErrNoUsage, name, flag.Name))
+		}
+		if !flag.Short || flag.Name == HelpName {
+			continue
+		}
+		for cmd := c.parent; cmd != nil; cmd = cmd.parent {
+			other := cmd.shortFlag(flag.Name[0])
+			if other != nil {
+				errs = append(errs, fmt.Errorf("%w: -%c means --%s in %s but --%s in %s",
+					ErrShorthand, flag.Name[0], flag.Name, name, other.Name, cmd.name_()))
+				break
+			}
+		}
+	}
+	claimed := make(map[string]string) // names and aliases of the children seen so far
+	for _, child := range c.children {
+		for _, n := range append([]string{child.name}, child.aliases...) {
+			if _, ok := c.formal[n]; ok && n != HelpName {
+				errs = append(errs, fmt.Errorf("%w: %s has both a flag and a child called %q", ErrCollision, name, n))
+			}
+			if first, ok := claimed[n]; ok {
+				errs = append(errs, fmt.Errorf("%w: %q runs %s, not %s", ErrShadowed, n, first, child.name_()))
+			} else {
+				claimed[n] = child.name_()
+			}
+		}
+		errs = append(errs, child.Check()...)
+	}
+	return
+}
+
+// shortFlag returns the flag, other than help, abbreviated by the given initial, if there is one
+func (c *Command) shortFlag(initial byte) *Flag {
+	for _, flag := range c.formal {
+		if flag.Short && flag.Name[0] == initial && flag.Name != HelpName {
+			return flag
+		}
+	}
+	return nil
+}
+
+// warnCollision warns, at definition time, that a flag and a child share the given name,
+// or panics if the command has StrictNames
+func (c *Command) warnCollision(name string) {
+	if c.StrictNames {
+		panic(c.sprintf("%s has both a flag and a child called %q", c.name_(), name))
+	}
+	fmt.Fprintf(c.Output(), "warning: %s has both a flag and a child called %q; --%s sets the flag, %s runs the child\n",
+		c.name_(), name, name, name)
+}
@@ -0,0 +1,80 @@
+package mandy
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCheckCollisions(t *testing.T) {
+	var out strings.Builder
+	var flag bool
+	c := NewCommand("app", ContinueOnError)
+	c.SetOutput(&out)
+	c.Bool(&flag, "status", false, "show status", false)
+	status := c.NewChild("status", "print the status")
+	if !strings.Contains(out.String(), "warning") {
+		t.Errorf("expected a definition-time warning, got %q", out.String())
+	}
+	var ran bool
+	status.Main = func(*Command) error { ran = true; return nil }
+
+	if errs := checked(c, ErrCollision); len(errs) != 1 {
+		t.Errorf("Check: %v", c.Check())
+	}
+
+	if err := c.Execute("--status", "status"); err != nil {
+		t.Fatal(err)
+	}
+	if !flag || !ran {
+		t.Errorf("dashes should set the flag (%v) and the free argument run the child (%v)", flag, ran)
+	}
+}
+
+func TestStrictNames(t *testing.T) {
+	c := NewCommand("app", ContinueOnError)
+	c.SetOutput(new(strings.Builder))
+	c.StrictNames = true
+	c.NewChild("status", "")
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic")
+		}
+	}()
+	c.Bool(new(bool), "status", false, "", false)
+}
+
+// checked returns the problems Check reports of the given kind
+func checked(c *Command, kind error) (out []error) {
+	for _, err := range c.Check() {
+		if errors.Is(err, kind) {
+			out = append(out, err)
+		}
+	}
+	return
+}
+
+func TestCheck(t *testing.T) {
+	c := NewCommand("app", ContinueOnError)
+	c.Format = "app [options]"
+	c.Bool(new(bool), "verbose", false, "chatty output", true)
+	run := c.NewChild("run", "run something")
+	run.Int(new(int), "value", 0, "", true)
+	run.Main = func(*Command) error { return nil }
+	c.NewChild("run", "an unreachable duplicate")
+	c.NewChild("list", "").Example = "app list"
+
+	for kind, want := range map[error]int{
+		ErrFormat:     1, // app
+		ErrShorthand:  1, // run -v
+		ErrNoUsage:    1, // run --value
+		ErrNoMain:     2, // the second run, and list
+		ErrShadowed:   1, // the second run
+		ErrNoExamples: 2, // both runs
+		ErrCollision:  0,
+	} {
+		if got := checked(c, kind); len(got) != want {
+			t.Errorf("%v: got %d problems, want %d: %v", kind, len(got), want, got)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package mandy
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+)
+
+// ChildEnv is a FlagGroup that builds the environment of the processes a command spawns, in the
+// passthrough style of env(1) and docker run: the inherited variables that pass Allow and Deny, then Set,
+// then the variables given to a repeatable --env flag as KEY=VAL, or as KEY to pass on the inherited value.
+// Its zero value passes everything on; set fields before mounting to change that.
+type ChildEnv struct {
+	Allow []string          // path.Match patterns of the inherited variables to pass on; all if empty
+	Deny  []string          // patterns of the inherited variables to withhold, even if allowed
+	Set   map[string]string // variables to set whatever is inherited, such as those derived from flags
+	Vars  []string          // the arguments to --env, which override the rest
+	name  string            // the name under which --env was registered
+}
+
+// Register defines the group's flags on c
+func (e *ChildEnv) Register(c *Command, prefix string) {
+	e.name = Prefixed(prefix, "env")
+	v := newSliceValue(e.Vars, &e.Vars, parseEnvVar, formatString)
+	v.sep = ""
+	c.Var(v, e.name, "`KEY=VAL` variable to set in child processes, or KEY to pass on the inherited one", false)
+}
+
+// parseEnvVar checks that s names a variable, and assigns it if it contains "="
+func parseEnvVar(s string) (string, error) {
+	key, _, _ := strings.Cut(s, "=")
+	if key == "" {
+		return "", fmt.Errorf("%w: %q should look like KEY=VAL or KEY", errParse, s)
+	}
+	return s, nil
+}
+
+// Environ returns the environment, as KEY=VAL pairs sorted by key, for processes spawned by c.
+// Variables are inherited from os.Environ, unless c or an ancestor was given a lookup function by
+// SetEnviron, in which case only those named by Allow patterns without wildcards can be inherited.
+// The --env flag is read through c, or its nearest ancestor defining it, so clones use their own values.
+func (e *ChildEnv) Environ(c *Command) []string {
+	env := make(map[string]string)
+	for _, kv := range FilterEnviron(c.inherited(e.Allow), e.Allow, e.Deny) {
+		key, val, _ := strings.Cut(kv, "=")
+		env[key] = val
+	}
+	for key, val := range e.Set {
+		env[key] = val
+	}
+	for _, arg := range e.vars(c) {
+		key, val, ok := strings.Cut(arg, "=")
+		if !ok {
+			if val, ok = c.lookupEnv(key); !ok {
+				delete(env, key)
+				continue
+			}
+		}
+		env[key] = val
+	}
+	out := make([]string, 0, len(env))
+	for key, val := range env {
+		out = append(out, key+"="+val)
+	}
+	slices.Sort(out)
+	return out
+}
+
+// vars returns the arguments to the --env flag registered by the group
+func (e *ChildEnv) vars(c *Command) []string {
+	for cmd := c; cmd != nil && e.name != ""; cmd = cmd.parent {
+		if flag := cmd.formal[e.name]; flag != nil {
+			if vars, ok := flag.Value.Get().([]string); ok {
+				return vars
+			}
+		}
+	}
+	return e.Vars
+}
+
+// inherited returns the environment the command's spawned processes may inherit as KEY=VAL pairs
+func (c *Command) inherited(allow []string) (out []string) {
+	injected := false
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		injected = injected || cmd.environ != nil
+	}
+	if !injected {
+		return os.Environ()
+	}
+	for _, pattern := range allow {
+		if strings.ContainsAny(pattern, `*?[\`) {
+			continue
+		}
+		if val, ok := c.lookupEnv(pattern); ok {
+			out = append(out, pattern+"="+val)
+		}
+	}
+	return out
+}
+
+// FilterEnviron returns the KEY=VAL pairs of env whose keys match one of the allow patterns, or any if there
+// are none, and none of the deny patterns. Patterns are matched as per path.Match; malformed ones match nothing.
+func FilterEnviron(env, allow, deny []string) (out []string) {
+	matches := func(patterns []string, key string) bool {
+		return slices.ContainsFunc(patterns, func(pattern string) bool {
+			ok, _ := path.Match(pattern, key)
+			return ok
+		})
+	}
+	for _, kv := range env {
+		key, _, _ := strings.Cut(kv, "=")
+		if (len(allow) == 0 || matches(allow, key)) && !matches(deny, key) {
+			out = append(out, kv)
+		}
+	}
+	return out
+}
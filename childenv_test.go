@@ -0,0 +1,52 @@
+package mandy
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFilterEnviron(t *testing.T) {
+	env := []string{"HOME=/root", "LANG=C", "LC_ALL=C", "AWS_SECRET_ACCESS_KEY=x", "PATH=/bin"}
+	got := FilterEnviron(env, []string{"LC_*", "LANG", "PATH", "AWS_*"}, []string{"*SECRET*"})
+	if want := []string{"LANG=C", "LC_ALL=C", "PATH=/bin"}; !slices.Equal(got, want) {
+		t.Errorf("FilterEnviron = %q, want %q", got, want)
+	}
+	if got := FilterEnviron(env, nil, []string{"AWS_*"}); len(got) != 4 {
+		t.Errorf("FilterEnviron without an allow list = %q, want all but AWS_*", got)
+	}
+}
+
+func TestChildEnv(t *testing.T) {
+	e := ChildEnv{
+		Allow: []string{"HOME", "LANG", "TOKEN", "USER"},
+		Deny:  []string{"TOKEN"},
+		Set:   map[string]string{"MODE": "batch"},
+	}
+	c := NewCommand("test", ContinueOnError)
+	c.SetEnviron(MapEnviron(map[string]string{"HOME": "/root", "LANG": "C", "TOKEN": "secret", "USER": "me", "EDITOR": "vi"}))
+	if err := c.Mount("", &e); err != nil {
+		t.Fatal(err)
+	}
+	child := c.NewChild("run", "")
+	if err := c.Parse("--env", "MODE=interactive", "--env=DEBUG=a=b", "--env", "USER", "--env", "MISSING", "run"); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"DEBUG=a=b", "HOME=/root", "LANG=C", "MODE=interactive", "USER=me"}
+	if got := e.Environ(child); !slices.Equal(got, want) {
+		t.Errorf("Environ = %q, want %q", got, want)
+	}
+
+	clone := c.Clone()
+	if err := clone.Parse("--env", "LANG=fr"); err != nil {
+		t.Fatal(err)
+	}
+	if got := e.Environ(clone); !slices.Contains(got, "LANG=fr") {
+		t.Errorf("Environ(clone) = %q, want the clone's --env values", got)
+	}
+	if got := e.Environ(c); slices.Contains(got, "LANG=fr") {
+		t.Errorf("Environ = %q, want the clone's --env values kept to the clone", got)
+	}
+	if err := c.Parse("--env", "=x"); err == nil {
+		t.Error("Parse(--env =x) succeeded")
+	}
+}
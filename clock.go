@@ -0,0 +1,33 @@
+package mandy
+
+import "time"
+
+// A Clock tells the time for a command's time-dependent features, such as timestamps, expiry, and
+// Within's time windows. Profiles, which measure how long the library itself takes, use the system's.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapts a function, such as time.Now, to the Clock interface
+type ClockFunc func() time.Time
+
+func (fn ClockFunc) Now() time.Time { return fn() }
+
+// SystemClock is the Clock used by commands for which SetClock hasn't been called
+var SystemClock Clock = ClockFunc(time.Now)
+
+// SetClock replaces the clock of the command and its children, so that tests and replays are deterministic.
+// If clk is nil, the parent's clock, or SystemClock, is used.
+func (c *Command) SetClock(clk Clock) {
+	c.clock = clk
+}
+
+// Now returns the current time according to the nearest clock set on the command or its ancestors
+func (c *Command) Now() time.Time {
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		if cmd.clock != nil {
+			return cmd.clock.Now()
+		}
+	}
+	return SystemClock.Now()
+}
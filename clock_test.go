@@ -0,0 +1,44 @@
+package mandy
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSetClock(t *testing.T) {
+	fixed := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	root := NewCommand("tool", ContinueOnError)
+	child := root.NewChild("run", "")
+	root.SetClock(ClockFunc(func() time.Time { return fixed }))
+
+	if !child.Now().Equal(fixed) {
+		t.Errorf("child's Now() = %v, want the parent's clock's %v", child.Now(), fixed)
+	}
+	later := fixed.Add(time.Hour)
+	child.SetClock(ClockFunc(func() time.Time { return later }))
+	if !child.Now().Equal(later) || !root.Now().Equal(fixed) {
+		t.Errorf("Now() = %v, %v after setting the child's clock", root.Now(), child.Now())
+	}
+
+	root.SetClock(nil)
+	if since := time.Since(root.Now()); since < 0 || since > time.Minute {
+		t.Errorf("Now() = %v without a clock, want the system time", root.Now())
+	}
+}
+
+func TestWithin(t *testing.T) {
+	w, err := ParseTimeWindow("Mon-Fri 9-17")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewCommand("tool", ContinueOnError)
+	monday := time.Date(2024, time.January, 1, 10, 0, 0, 0, time.UTC)
+	c.SetClock(ClockFunc(func() time.Time { return monday }))
+	if !c.Within(w) {
+		t.Error("Monday at 10 is outside of Mon-Fri 9-17")
+	}
+	c.SetClock(ClockFunc(func() time.Time { return monday.Add(-12 * time.Hour) }))
+	if c.Within(w) {
+		t.Error("Sunday at 22 is within Mon-Fri 9-17")
+	}
+}
@@ -0,0 +1,153 @@
+package mandy
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+)
+
+// cloner is implemented by values whose storage can't be copied by reflection alone;
+// clone returns a copy of the value bound to fresh storage
+type cloner interface {
+	clone() Getter
+}
+
+// cloneValue returns a copy of v that does not share storage with it.
+// Values of types foreign to this package are shared, unless they are pointers to plain data.
+func cloneValue(v Getter) Getter {
+	if c, ok := v.(cloner); ok {
+		return c.clone()
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return v
+	}
+	cp := reflect.New(rv.Elem().Type())
+	cp.Elem().Set(rv.Elem())
+	if g, ok := cp.Interface().(Getter); ok {
+		return g
+	}
+	return v
+}
+
+// Clone returns a deep copy of the command and its children whose flags are bound to fresh storage,
+// holding the flags' current values. Parsing the clone does not update the variables passed to
+// the original's flag constructors, so its Main should read flags through Lookup.
+// Clones may be Executed concurrently with each other and with the original.
+func (c *Command) Clone() *Command {
+	return c.clone(c.parent)
+}
+
+func (c *Command) clone(parent *Command) *Command {
+	cp := *c
+	cp.parent = parent
+	cp.sub = nil
+	cp.invalidate()
+	cp.args = append([]string(nil), c.args...)
+	cp.aliases = append([]string(nil), c.aliases...)
+	cp.configured = maps.Clone(c.configured)
+	cp.profiled = maps.Clone(c.profiled)
+	cp.configFrom = maps.Clone(c.configFrom)
+	cp.configPaths = slices.Clone(c.configPaths)
+	cp.untrusted = maps.Clone(c.untrusted)
+	cp.defaults = maps.Clone(c.defaults)
+	cp.formal = make(map[string]*Flag, len(c.formal))
+	for name, flag := range c.formal {
+		f := *flag
+		f.Value = cloneValue(flag.Value)
+		switch v := f.Value.(type) {
+		case *derivedValue:
+			v.cmd = &cp
+		case *outputValue:
+			v.p.cmd = &cp
+		case *inputValue:
+			v.p.cmd = &cp
+		}
+		cp.formal[name] = &f
+	}
+	if c.profileFlag != nil {
+		cp.profileFlag = cp.formal[c.profileFlag.Name]
+	}
+	if c.actual != nil {
+		cp.actual = make(map[string]*Flag, len(c.actual))
+		for name := range c.actual {
+			cp.actual[name] = cp.formal[name]
+		}
+	}
+	cp.children = make([]*Command, len(c.children))
+	for i, child := range c.children {
+		cp.children[i] = child.clone(&cp)
+	}
+	return &cp
+}
+
+// forget clears the record of flags set and children dispatched to by previous parses
+func (c *Command) forget() {
+	c.actual = nil
+	for _, flag := range c.formal {
+		flag.origin = Origin{}
+	}
+	c.sub = nil
+	c.parsed = false
+	for _, child := range c.children {
+		child.forget()
+	}
+}
+
+func (b *bigValue[T, P]) clone() Getter {
+	cp := *b
+	cp.p = P(new(T))
+	if b.p != nil {
+		cp.p.Set(b.p)
+	}
+	return &cp
+}
+
+func (s *sliceValue[T]) clone() Getter {
+	cp := *s
+	p := append([]T(nil), *s.p...)
+	cp.p = &p
+	return &cp
+}
+
+func (e *endpointsValue) clone() Getter {
+	return &endpointsValue{e.sliceValue.clone().(*sliceValue[string]), e.dial}
+}
+
+func (j *jsonValue) clone() Getter {
+	cp := *j
+	p := *j.p
+	cp.p = &p
+	return &cp
+}
+
+func (j *jsonIntoValue[T]) clone() Getter {
+	p := *j.p
+	return &jsonIntoValue[T]{p: &p}
+}
+
+func (l *locationValue) clone() Getter {
+	p := *l.p
+	return &locationValue{p: &p}
+}
+
+func (r *ratioValue) clone() Getter {
+	cp := *r
+	p := *r.p
+	cp.p = &p
+	return &cp
+}
+
+func (s *secretValue) clone() Getter {
+	cp := *s
+	p := *s.p
+	cp.p = &p
+	return &cp
+}
+
+func (t *templateValue) clone() Getter {
+	cp := *t
+	p := *t.p
+	cp.p = &p
+	return &cp
+}
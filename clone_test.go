@@ -0,0 +1,66 @@
+package mandy
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestClone(t *testing.T) {
+	var n int
+	var waits []time.Duration
+	c := NewCommand("clone", ContinueOnError)
+	c.Int(&n, "num", 1, "a number", false)
+	c.DurationSlice(&waits, "wait", []time.Duration{time.Second}, "some waits", false)
+	child := c.NewChild("sub", "a child")
+	child.Main = func(self *Command) error { return nil }
+
+	results := make([]string, 8)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			cp := c.Clone()
+			cp.Main = func(self *Command) error {
+				results[i] = fmt.Sprint(self.Lookup("num").Value.Get(), self.Lookup("wait").Value)
+				return nil
+			}
+			if err := cp.Execute("--num", fmt.Sprint(i), "--wait", fmt.Sprint(i, "s")); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	for i, got := range results {
+		if want := fmt.Sprintf("%d %ds", i, i); got != want {
+			t.Errorf("run %d: got %q, want %q", i, got, want)
+		}
+	}
+	if n != 1 || len(waits) != 1 || waits[0] != time.Second {
+		t.Errorf("clones leaked into the original: %d %v", n, waits)
+	}
+	if cp := c.Clone(); cp.children[0].parent != cp {
+		t.Error("cloned children should belong to the clone")
+	}
+}
+
+func TestExecuteRestoresArgs(t *testing.T) {
+	c := NewCommand("exec", ContinueOnError)
+	c.Bool(new(bool), "verbose", false, "chatty", false)
+	c.Main = func(self *Command) error { return nil }
+	c.args = []string{"before"}
+	if err := c.Execute("--verbose"); err != nil {
+		t.Fatal(err)
+	}
+	if len(c.args) != 1 || c.args[0] != "before" {
+		t.Errorf("args were not restored: %q", c.args)
+	}
+	if err := c.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if c.actual["verbose"] != nil {
+		t.Error("flags set by a previous Execute should be forgotten")
+	}
+}
@@ -0,0 +1,78 @@
+package mandy
+
+import (
+	"strconv"
+	"time"
+)
+
+// codec converts the elements of collection flags to and from text
+type codec[T any] struct {
+	parse  func(string) (T, error)
+	format func(T) string
+}
+
+var (
+	stringCodec = codec[string]{parseString, formatString}
+	intCodec    = codec[int]{parseInt, strconv.Itoa}
+)
+
+// codecFor returns the codec for elements of type T, reporting false if there is none.
+// T may be a string, bool, integer, or float type, a time.Duration, or a type whose pointer implements Value.
+func codecFor[T any]() (codec[T], bool) {
+	var c any
+	switch any(*new(T)).(type) {
+	case string:
+		c = stringCodec
+	case bool:
+		c = codec[bool]{parseBool, strconv.FormatBool}
+	case int:
+		c = intCodec
+	case int64:
+		c = codec[int64]{parseInt64, formatInt64}
+	case uint:
+		c = codec[uint]{parseUint, formatUint}
+	case uint64:
+		c = codec[uint64]{parseUint64, formatUint64}
+	case float64:
+		c = codec[float64]{parseFloat64, formatFloat64}
+	case time.Duration:
+		c = codec[time.Duration]{parseDuration, time.Duration.String}
+	default:
+		if _, ok := any(new(T)).(Value); !ok {
+			return codec[T]{}, false
+		}
+		return codec[T]{
+			parse: func(s string) (T, error) {
+				var v T
+				err := any(&v).(Value).Set(s)
+				return v, err
+			},
+			format: func(v T) string { return any(&v).(Value).String() },
+		}, true
+	}
+	return c.(codec[T]), true
+}
+
+// parseBool parses booleans as Bool flags do
+func parseBool(s string) (bool, error) {
+	v, err := strconv.ParseBool(s)
+	if err != nil {
+		return false, errParse
+	}
+	return v, nil
+}
+
+// parseInt64 parses integers as Int64 flags do
+func parseInt64(s string) (int64, error) {
+	v, err := strconv.ParseInt(s, 0, 64)
+	return v, numError(err)
+}
+
+// parseUint64 parses unsigned integers as Uint64 flags do
+func parseUint64(s string) (uint64, error) {
+	v, err := strconv.ParseUint(s, 0, 64)
+	return v, numError(err)
+}
+
+func formatInt64(i int64) string   { return strconv.FormatInt(i, 10) }
+func formatUint64(u uint64) string { return strconv.FormatUint(u, 10) }
@@ -0,0 +1,30 @@
+package mandy
+
+import "testing"
+
+func TestParseColor(t *testing.T) {
+	tests := map[string]string{
+		"#FFA500":               "#ffa500",
+		"#f00":                  "#ff0000",
+		"rgb(255, 165, 0)":      "#ffa500",
+		"rgb(100% 0% 50%)":      "#ff0080",
+		"RebeccaPurple":         "#663399",
+		" lightgoldenrodyellow": "#fafad2",
+	}
+	for in, want := range tests {
+		c, err := ParseColor(in)
+		if err != nil {
+			t.Errorf("ParseColor(%q): %v", in, err)
+		} else if c.String() != want {
+			t.Errorf("ParseColor(%q) = %s, want %s", in, c, want)
+		}
+	}
+	for _, bad := range []string{"", "#ff", "#gggggg", "rgb(1, 2)", "rgb(256, 0, 0)", "rgb(101%, 0, 0)", "blurple"} {
+		if _, err := ParseColor(bad); err == nil {
+			t.Errorf("ParseColor(%q) succeeded", bad)
+		}
+	}
+	if name := (Color{0, 255, 255}).Name(); name != "aqua" {
+		t.Errorf("Name() = %q, want aqua", name)
+	}
+}
@@ -21,22 +21,71 @@ import (
 // Flag names must be unique within a Command. An attempt to define a flag whose
 // name is already in use will cause a panic.
 type Command struct {
-	output      io.Writer
-	parent      *Command
-	actual      map[string]*Flag
-	formal      map[string]*Flag
-	Usage       func() string
-	Main        func(self *Command) error
-	Format      string
-	name        string
-	URL         string
-	children    []*Command
-	args        []string
-	aliases     []string
-	help        helpNode
-	parsed      bool
-	errorPolicy ErrorPolicy
-	lambda      bool // indicates whether the lambda flag was invoked
+	output       io.Writer
+	input        io.Reader
+	stdout       io.Writer
+	parent       *Command
+	actual       map[string]*Flag
+	formal       map[string]*Flag
+	Usage        func(w io.Writer, c *Command) error // writes the usage message, the default one if nil
+	Main         func(self *Command) error
+	Format       string
+	DefaultStyle DefaultStyle // how flag defaults are rendered in the default usage
+	Guess        GuessPolicy  // what a command without Main does with a misspelling of a child's name
+	// BareAssignments makes Parse treat free arguments of the form "key=value" as
+	// flag assignments when key is the full name of one of the command's flags.
+	// Other free arguments, including those with unknown keys, are positional
+	// and, as usual, stop flag parsing; so do arguments following "--".
+	BareAssignments bool
+	GlobalOptions   bool   // accept the flags of the command's ancestors and list them under "global options:" in the default usage
+	StrictNames     bool   // panic, rather than warn, when a flag and a child are given the same name
+	ResponseFiles   bool   // on a root command, make Parse expand "@file" arguments into the arguments the file holds
+	HelpHint        bool   // under ExitOnError, follow errors with the command line that prints the failing command's help
+	RerunHint       bool   // under ExitOnError, follow errors raised after parsing with the parsed command line, secrets redacted
+	Hidden          bool   // leave the command out of its parent's usage, though it may still be dispatched to
+	Summary         string // one line description shown in the parent's usage
+	Example         string // sample invocations, one per line, shown in the default usage
+	Footer          string // text/template rendered beneath the flags in the default usage
+	EnvFile         string // a dotenv file, such as ".env", loaded by DiscoverConfig after the project-local config
+	Version         string
+	name            string
+	URL             string // resolved, if empty, by SetURLResolver's function, or inherited, when help is first rendered
+	children        []*Command
+	sub             *Command // the child dispatched to by the last parse
+	experimental    string   // environment variable enabling the command, if it is experimental
+	reserved        []string // flag and child names that may not be registered
+	refused         string   // the message of the last registration refused for its name
+	exit            func(code int)
+	args            []string
+	aliases         []string
+	help            helpNode
+	parsed          bool
+	errorPolicy     ErrorPolicy
+	lambda          bool                             // indicates whether the lambda flag was invoked
+	unsorted        bool                             // list flags in registration order
+	registered      int                              // the number of flags ever registered
+	order           []*Flag                          // the formal flags in the chosen order, nil when stale
+	match           *matcher                         // the index of the formal flags and children, nil when stale
+	usageMemo       *usageMemo                       // the last default usage message rendered
+	urlPending      bool                             // whether an empty URL is yet to be resolved, or inherited from the parent
+	sealed          bool                             // whether flags, children, and aliases may no longer be added
+	addedBy         string                           // the plugin that added the command, if any
+	environ         func(key string) (string, bool)  // reads environment variables, os.LookupEnv if nil
+	clock           Clock                            // tells the time, SystemClock if nil
+	negateBools     bool                             // whether boolean flags are made Negatable as they are registered
+	setups          []func(*Command) (func(), error) // run by Execute before Main, returning a function undoing their work
+	requirements    []requirement                    // constraints on which flags must be set together
+	checks          []func(*Command) error           // run by Parse once the flags are resolved, validating them together
+	configured      map[string]string                // flag values loaded by LoadConfig
+	profiled        map[string]map[string]string     // flag values loaded by LoadConfig for each profile
+	configFrom      map[string]map[string]string     // the files configured's and profiled's values came from, by profile
+	configPaths     []string                         // the config files loaded by LoadConfig
+	untrusted       map[string]bool                  // the project-local config files loaded without being trusted, by path
+	profileFlag     *Flag                            // the flag selecting a profile, if ConfigProfiles defined it
+	prefixes        map[string]PrefixHandler         // handlers of arguments with custom prefixes, by prefix
+	trustPrompt     TrustPrompt                      // decides whether to trust project-local config files, if RequireTrust was called
+	defaults        map[string]string                // the defaults the command gives its ancestors' flags, by name
+	responseEnds    []int                            // on a root command, the arguments following each response file being parsed
 }
 
 // sortFlags returns the flags as a slice in lexicographical sorted order.
@@ -53,6 +102,36 @@ func sortFlags(flags map[string]*Flag) []*Flag {
 	return result
 }
 
+// SortFlags chooses whether help messages and the visitors list flags in lexicographical order,
+// the default, or in the order they were registered.
+func (c *Command) SortFlags(sorted bool) {
+	c.unsorted = !sorted
+	c.invalidate()
+}
+
+// orderFlags returns the flags as a slice in the command's chosen order
+func (c *Command) orderFlags(flags map[string]*Flag) []*Flag {
+	result := sortFlags(flags)
+	if c.unsorted {
+		sort.SliceStable(result, func(i, j int) bool {
+			return result[i].ordinal < result[j].ordinal
+		})
+	}
+	return result
+}
+
+// register adds the flag to the command's formal set, recording its registration order
+func (c *Command) register(flag *Flag) {
+	if c.formal == nil {
+		c.formal = make(map[string]*Flag)
+	}
+	c.registered++
+	flag.ordinal = c.registered
+	c.markNegatable(flag)
+	c.formal[flag.Name] = flag
+	c.invalidate()
+}
+
 func (c *Command) ChildNames() []string {
 	return c.childNames()
 }
@@ -77,12 +156,21 @@ func (c *Command) childNames() (out []string) {
 }
 
 func (c *Command) AddAlias(args ...string) error {
+	for _, arg := range args {
+		if err := c.errSealed("alias", arg); err != nil {
+			return err
+		}
+		if c.parent != nil {
+			if err := c.parent.errSealed("alias", arg); err != nil {
+				return err
+			}
+		}
+	}
 	blocked := []string{}
-	if pcn := c.parent.parent.childNames(); c.parent != nil {
-		slices.Sort(pcn)
-		pcn = slices.Compact(pcn)
+	if c.parent != nil {
+		pcn := c.parent.childNames()
 		for _, arg := range args {
-			if slices.Contains(pcn, arg) {
+			if slices.Contains(pcn, arg) || slices.Contains(c.parent.reserved, arg) {
 				blocked = append(blocked, arg)
 			}
 		}
@@ -92,17 +180,29 @@ func (c *Command) AddAlias(args ...string) error {
 		return fmt.Errorf("the following args are taken: %v", blocked)
 	}
 
+	if c.parent != nil {
+		for _, arg := range args {
+			if _, ok := c.parent.formal[arg]; ok {
+				c.parent.warnCollision(arg)
+			}
+		}
+	}
 	c.aliases = append(c.aliases, args...)
+	if c.parent != nil {
+		c.parent.invalidate()
+	}
 	return nil
 }
 
-// Output returns the destination for usage and error messages. os.Stderr is returned if
-// output was not set or was set to nil.
+// Output returns the destination for usage and error messages. The parent's output is returned if
+// output was not set or was set to nil, and os.Stderr if no ancestor's was set either.
 func (c *Command) Output() io.Writer {
-	if c.output == nil {
-		return os.Stderr
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		if cmd.output != nil {
+			return cmd.output
+		}
 	}
-	return c.output
+	return os.Stderr
 }
 
 // Name returns the name of the flag set.
@@ -116,23 +216,23 @@ func (c *Command) ErrorPolicy() ErrorPolicy {
 }
 
 // SetOutput sets the destination for usage and error messages.
-// If output is nil, os.Stderr is used.
+// If output is nil, the parent's output, or os.Stderr, is used.
 func (c *Command) SetOutput(output io.Writer) {
 	c.output = output
 }
 
-// VisitAll visits the flags in lexicographical order, calling fn for each.
+// VisitAll visits the flags in lexicographical, or registration, order, calling fn for each.
 // It visits all flags, even those not set.
 func (c *Command) VisitAll(fn func(*Flag)) {
-	for _, flag := range sortFlags(c.formal) {
+	for flag := range c.Flags() {
 		fn(flag)
 	}
 }
 
-// Visit visits the flags in lexicographical order, calling fn for each.
+// Visit visits the flags in lexicographical, or registration, order, calling fn for each.
 // It visits only those flags that have been set.
 func (c *Command) VisitSet(fn func(*Flag)) {
-	for _, flag := range sortFlags(c.actual) {
+	for flag := range c.SetFlags() {
 		fn(flag)
 	}
 }
@@ -148,25 +248,112 @@ func (c *Command) Set(name, value string) error {
 	if !ok {
 		return fmt.Errorf("no such flag -%v", name)
 	}
-	err := flag.Value.Set(value)
+	return c.setFrom(flag, value, Origin{Source: SourceProgram})
+}
+
+// set assigns a value from the command line to the flag and records it as visited
+func (c *Command) set(flag *Flag, value string) error {
+	return c.setFrom(flag, value, Origin{Source: SourceCommandLine})
+}
+
+// setFrom assigns a value from the origin's source to the flag and records it as visited, and where from
+func (c *Command) setFrom(flag *Flag, value string, origin Origin) error {
+	if err := flag.checkDerived(); err != nil {
+		return err
+	}
+	if err := flag.checkSource(origin.Source); err != nil {
+		return err
+	}
+	if _, set := c.actual[flag.Name]; set && flag.once {
+		return fmt.Errorf("%w: --%s", ErrOnce, flag.Name)
+	}
+	value, err := flag.indirect(value, origin)
+	if err != nil {
+		return err
+	}
+	if rv, ok := flag.Value.(resolvingValue); ok && origin.trusted() {
+		err = rv.resolve(value, c.lookupEnv)
+	} else {
+		err = flag.Value.Set(value)
+	}
 	if err != nil {
 		return err
 	}
 	if c.actual == nil {
 		c.actual = make(map[string]*Flag)
 	}
-	c.actual[name] = flag
+	c.actual[flag.Name] = flag
+	flag.origin = origin
 	return nil
 }
 
-// a string describing the default values of all defined command-line flags in the set.
+// a string describing the default values of all defined command-line flags in the set, in the order chosen by SortFlags.
+// If GlobalOptions is set, the flags of the set's ancestors follow in a block of their own.
 func (c *Command) Defaults() string {
+	if c.GlobalOptions {
+		if globals := c.usageGlobals(); globals != "" {
+			return c.usageFlags() + "\n" + globals
+		}
+	}
 	return c.usageFlags()
 }
 
+// Dump describes the current value of each of the command's flags, one per line.
+// Secret values are redacted, though the credentials they were resolved from are shown.
+func (c *Command) Dump() (out string) {
+	c.VisitAll(func(f *Flag) {
+		out += fmt.Sprintf("%s=%s\t(%s)\n", f.Name, f.redact(f.Value.String()), c.source(f))
+	})
+	return
+}
+
+// source describes where the flag's value came from: "default" or "set",
+// followed by the credential a secret was resolved from, if any
+func (c *Command) source(f *Flag) string {
+	source := "default"
+	if c.Visited(f) {
+		source = "set"
+	}
+	if p, ok := f.Value.(interface{ Provenance() string }); ok && p.Provenance() != "" {
+		source += " from " + p.Provenance()
+	}
+	return source
+}
+
 // defaultUsage is the default function to print a usage message.
 func (c *Command) defaultUsage() string {
-	return strings.Join([]string{c.usageHeader(), c.usageFlags(), c.URL}, "\n")
+	sections := []string{c.usageHeader(), c.Defaults()}
+	if len(c.children) > 0 {
+		sections = append(sections, c.usageChildren())
+	}
+	if c.Example != "" {
+		sections = append(sections, c.usageExample())
+	}
+	return strings.Join(append(sections, c.usageFooter()), "\n")
+}
+
+// usageExample indents the command's example beneath a heading
+func (c Command) usageExample() string {
+	return "examples:\n\t" + strings.ReplaceAll(strings.TrimRight(c.Example, "\n"), "\n", "\n\t") + "\n"
+}
+
+// usageChildren lists the command's children alongside their summaries
+func (c Command) usageChildren() (out string) {
+	out = "commands:\n"
+	for _, child := range c.children {
+		if child.hidden() {
+			continue
+		}
+		out += "\t" + child.name
+		if child.Summary != "" {
+			out += "\t" + child.Summary
+		}
+		if child.addedBy != "" && child.addedBy != c.addedBy {
+			out += " (plugin " + child.addedBy + ")"
+		}
+		out += "\n"
+	}
+	return
 }
 
 func (c Command) usageHeader() string {
@@ -174,8 +361,41 @@ func (c Command) usageHeader() string {
 }
 
 func (c Command) usageFlags() (out string) {
-	for _, flag := range c.formal {
-		out += "\t" + flag.usage() + "\n"
+	c.VisitAll(func(flag *Flag) {
+		out += "\t" + flag.usage(c.DefaultStyle) + "\n"
+	})
+	return
+}
+
+// globals returns the flags of the command's ancestors, nearest first, omitting help flags
+// and those shadowed by a nearer command's flag of the same name
+func (c *Command) globals() (out []*Flag) {
+	seen := map[string]bool{HelpName: true}
+	for name := range c.formal {
+		seen[name] = true
+	}
+	for cmd := c.parent; cmd != nil; cmd = cmd.parent {
+		cmd.VisitAll(func(flag *Flag) {
+			if !seen[flag.Name] {
+				seen[flag.Name] = true
+				out = append(out, flag)
+			}
+		})
+	}
+	return
+}
+
+// usageGlobals lists the flags inherited from the command's ancestors, if there are any
+func (c *Command) usageGlobals() (out string) {
+	globals := c.globals()
+	if len(globals) == 0 {
+		return ""
+	}
+	out = "global options:\n"
+	for _, flag := range globals {
+		f := *flag
+		f.DefValue = c.inheritedDefault(flag)
+		out += "\t" + f.usage(c.DefaultStyle) + "\n"
 	}
 	return
 }
@@ -227,7 +447,9 @@ func (c *Command) NArg() int { return len(c.args) }
 // Args returns the non-flag arguments.
 func (c *Command) Args() []string { return c.args }
 
-// Argc returns a channel to the non-flag arguments.
+// Argch returns a channel to the non-flag arguments.
+//
+// Deprecated: the goroutine feeding the channel leaks unless the channel is drained; use ArgSeq.
 func (c *Command) Argch() chan string {
 	out := make(chan string)
 	go func() {
@@ -275,6 +497,17 @@ func (c *Command) String(p *string, name string, value string, usage string, sho
 	return c.Var(newStringValue(value, p), name, usage, short)
 }
 
+// Secret defines a string flag with specified name, default value, and usage string.
+// The argument p points to a string variable in which to store the value of the flag.
+// The flag's value is redacted in usage messages, dumps, and invocation records.
+// Arguments like "env:VAR" or "keyring:service/account" are resolved, at parse time, by the
+// CredentialResolver registered for their scheme, unless they come from an untrusted project-local config
+// file (see RequireTrust); "cmd:pass show token" too, once EnableCredentialResolver("cmd") is called.
+// Write "literal:env:VAR" to mean "env:VAR" itself.
+func (c *Command) Secret(p *string, name string, value string, usage string, short bool) *Flag {
+	return c.Var(newSecretValue(value, p), name, usage, short)
+}
+
 // Float64 defines a float64 flag with specified name, default value, and usage string.
 // The argument p points to a float64 variable in which to store the value of the flag.
 func (c *Command) Float64(p *float64, name string, value float64, usage string, short bool) *Flag {
@@ -288,6 +521,20 @@ func (c *Command) Duration(p *time.Duration, name string, value time.Duration, u
 	return c.Var(newDurationValue(value, p), name, usage, short)
 }
 
+// DurationSlice defines a []time.Duration flag with specified name, default value, and usage string.
+// The argument p points to a []time.Duration variable in which to store the value of the flag.
+// Each occurrence of the flag accepts a comma separated list of values acceptable to time.ParseDuration.
+func (c *Command) DurationSlice(p *[]time.Duration, name string, value []time.Duration, usage string, short bool) *Flag {
+	return c.Var(newSliceValue(value, p, parseDuration, time.Duration.String), name, usage, short)
+}
+
+// TimeWindow defines a TimeWindow flag with specified name, default value, and usage string.
+// The argument p points to a TimeWindow variable in which to store the value of the flag.
+// The flag accepts a value acceptable to ParseTimeWindow.
+func (c *Command) TimeWindow(p *TimeWindow, name string, value TimeWindow, usage string, short bool) *Flag {
+	return c.Var(newTimeWindowValue(value, p), name, usage, short)
+}
+
 // Func defines a flag with the specified name and usage string.
 // Each time the flag is seen, fn is called with the value of the flag.
 // If fn returns a non-nil error, it will be treated as a flag value parsing error.
@@ -298,15 +545,28 @@ func (c *Command) Func(fn func(string) error, name, usage string, short bool) *F
 // Check if a command accepts a given flag name
 // return the name of the matching flag
 // else empty string
+// exact names win, then the earliest registered flag allowing its initial
 func (c *Command) accepts(name string) string {
-	for k, v := range c.formal {
-		if (v.Short && name == k[:1]) || name == k {
-			return k
+	if _, ok := c.formal[name]; ok {
+		return name
+	}
+	for _, flag := range c.matcher().initials[name] {
+		if flag.Short {
+			return flag.Name
 		}
 	}
 	return ""
 }
 
+// unknown returns the error for a flag the command does not define,
+// which is ErrHelp for the name of a disabled help flag or its initial
+func (c *Command) unknown(name string) error {
+	if name == HelpName || name == HelpName[:1] {
+		return ErrHelp
+	}
+	return fmt.Errorf("unknown flag: %s", name)
+}
+
 // Var defines a flag with the specified name and usage string. The type and
 // value of the flag are represented by the first argument, of type Value, which
 // typically holds a user-defined implementation of Value. For instance, the
@@ -314,11 +574,15 @@ func (c *Command) accepts(name string) string {
 // of strings by giving the slice the methods of Value; in particular, Set would
 // decompose the comma-separated string into the slice.
 func (c *Command) Var(value Getter, name string, usage string, short bool) *Flag {
+	defer profileRecord(c, phaseRegister, profileStart())
+	c.panicSealed("flag", name)
 	// Flag must not begin "-" or contain "=".
 	if strings.HasPrefix(name, "-") {
 		panic(c.sprintf("flag %q begins with -", name))
 	} else if strings.Contains(name, "=") {
 		panic(c.sprintf("flag %q contains =", name))
+	} else if c.isReserved(name) {
+		c.refuse("flag %q is reserved", name)
 	}
 
 	// Remember the default value as a string; it won't change.
@@ -328,16 +592,15 @@ func (c *Command) Var(value Getter, name string, usage string, short bool) *Flag
 		Value:       value,
 		DefValue:    value.String(),
 		Short:       short,
+		addedBy:     c.addedBy,
 	}
 	_, alreadythere := c.formal[name]
 	if alreadythere {
-		var msg string
+		// Happens only if flags are declared with identical names
 		if c.name == "" {
-			msg = c.sprintf("flag redefined: %s", name)
-		} else {
-			msg = c.sprintf("%s flag redefined: %s", c.name, name)
+			c.refuse("flag redefined: %s", name)
 		}
-		panic(msg) // Happens only if flags are declared with identical names
+		c.refuse("%s flag redefined: %s", c.name, name)
 	}
 	if flag.Short {
 		for _, other := range c.formal {
@@ -346,15 +609,15 @@ func (c *Command) Var(value Getter, name string, usage string, short bool) *Flag
 					other.Short = false
 					continue
 				}
-				panic(c.sprintf("Short name collision between %q and %q flags", flag.Name, other.Name))
+				c.refuse("Short name collision between %q and %q flags", flag.Name, other.Name)
 			}
 		}
 	}
 
-	if c.formal == nil {
-		c.formal = make(map[string]*Flag)
+	if c.child(name) != nil {
+		c.warnCollision(name)
 	}
-	c.formal[name] = flag
+	c.register(flag)
 
 	return flag
 }
@@ -366,6 +629,13 @@ func (c *Command) sprintf(format string, a ...any) string {
 	return msg
 }
 
+// refuse panics with the formatted message, printed as by sprintf, after recording it,
+// so that Mount can tell a name that's taken or reserved from a bug in a group
+func (c *Command) refuse(format string, a ...any) {
+	c.refused = c.sprintf(format, a...)
+	panic(c.refused)
+}
+
 // failf prints to standard error a formatted error and usage message and
 // returns the error.
 func (c *Command) failf(format string, a ...any) error {
@@ -374,13 +644,35 @@ func (c *Command) failf(format string, a ...any) error {
 	return errors.New(msg)
 }
 
-// usage calls the Usage method for the flag set if one is specified,
-// or the appropriate default usage function otherwise.
+// usage writes the usage message to the command's output
 func (c *Command) usage() {
+	c.WriteUsage(c.Output())
+}
+
+// WriteUsage writes the usage message to w with the Usage function for the command if one is specified,
+// or the default usage function otherwise.
+func (c *Command) WriteUsage(w io.Writer) error {
 	if c.Usage == nil {
-		c.defaultUsage()
-	} else {
-		c.Usage()
+		_, err := io.WriteString(w, c.memoUsage())
+		return err
+	}
+	return c.Usage(w, c)
+}
+
+// UsageString returns the usage message that WriteUsage would write
+func (c *Command) UsageString() string {
+	var b strings.Builder
+	if err := c.WriteUsage(&b); err != nil {
+		c.Warn(err)
+	}
+	return b.String()
+}
+
+// UsageFunc adapts an old style, string returning, usage function for use as a Command's Usage
+func UsageFunc(fn func() string) func(w io.Writer, c *Command) error {
+	return func(w io.Writer, _ *Command) error {
+		_, err := io.WriteString(w, fn())
+		return err
 	}
 }
 
@@ -482,68 +774,153 @@ func (c *Command) parseOne() (*Command, bool, error) {
 		return nil, false, nil
 	}
 	arg := c.args[0]
+	if handled, err := c.handlePrefixed(arg); handled {
+		return nil, err == nil, err
+	}
+	if len(arg) > 1 && arg[0] == '@' && c.responseFiles() {
+		if arg[1] == '@' {
+			arg = arg[1:] // stands for itself, less the first "@"
+			c.args = append([]string{arg}, c.args[1:]...)
+		} else if err := c.expandResponseFile(); err != nil {
+			c.args = c.args[1:]
+			return nil, false, err
+		} else {
+			return nil, true, nil
+		}
+	}
+	if len(arg) < 2 || arg[0] != '-' {
+		if flag := c.assignment(arg); flag != nil {
+			c.args = c.args[1:]
+			value := arg[len(flag.Name)+1:]
+			if err := c.set(flag, value); err != nil {
+				return nil, false, fmt.Errorf("invalid value for flag %s: %s: %w", flag.Name, flag.redact(value), err)
+			}
+			return nil, true, nil
+		}
+		// flag parsing stops at the first free argument or "-"
+		// the former may name a child to dispatch to
+		return c.child(arg), false, nil
+	}
 	c.args = c.args[1:]
+	if arg == "--" {
+		return nil, false, nil
+	}
 	// Check if it's a flag-value pair
 	if strings.Contains(arg, "=") {
-		parts := strings.SplitN(arg, "=", 2)
+		parts := strings.SplitN(trimDashes(arg), "=", 2)
 		flagName := parts[0]
 		flagValue := parts[1]
 		// Find the flag in the command's flag set
-		flag := c.formal[c.accepts(flagName)]
+		owner, flag := c.reach(func(cmd *Command) *Flag { return cmd.formal[cmd.accepts(flagName)] })
 		if flag == nil {
-			return nil, false, fmt.Errorf("unknown flag: %s", flagName)
-		}
-		// Check if the flag has a value type other than bool
-		if !flag.Value.IsBool() {
-			if err := flag.Value.Set(flagValue); err != nil {
-				return nil, false, fmt.Errorf("invalid value for flag %s: %s", flagName, flagValue)
+			if _, negated := c.reach(func(cmd *Command) *Flag { return cmd.negated(flagName) }); negated != nil {
+				return nil, false, fmt.Errorf("unexpected value for negated flag: %s", flagName)
 			}
-		} else {
-			return nil, false, fmt.Errorf("unexpected value for boolean flag: %s", flagName)
+			return nil, false, c.unknown(flagName)
+		}
+		if err := owner.set(flag, flagValue); err != nil {
+			return nil, false, fmt.Errorf("invalid value for flag %s: %s: %w", flagName, flag.redact(flagValue), err)
 		}
 		return nil, true, nil
 	}
 	// Check if it's a long flag
 	if strings.HasPrefix(arg, "--") {
 		flagName := strings.TrimPrefix(arg, "--")
-		flag := c.formal[c.accepts(flagName)]
+		owner, flag := c.reach(func(cmd *Command) *Flag { return cmd.formal[cmd.accepts(flagName)] })
 		if flag == nil {
-			return nil, false, fmt.Errorf("unknown flag: %s", flagName)
+			if owner, flag = c.reach(func(cmd *Command) *Flag { return cmd.negated(flagName) }); flag != nil {
+				if err := owner.set(flag, "false"); err != nil {
+					return nil, false, fmt.Errorf("invalid value for flag %s: %w", flagName, err)
+				}
+				return nil, true, nil
+			}
+			return nil, false, c.unknown(flagName)
 		}
 		// Check if the flag is a bool flag
-
-		if f, ok := flag.Value.Get().(boolFlag); f != nil && ok {
-			flag.Value.Set("true")
+		if flag.Value.IsBool() {
+			if err := owner.set(flag, "true"); err != nil {
+				return nil, false, fmt.Errorf("invalid value for flag %s: %w", flagName, err)
+			}
+		} else if flag.NoOptDefVal != "" {
+			if err := owner.set(flag, flag.NoOptDefVal); err != nil {
+				return nil, false, fmt.Errorf("invalid value for flag %s: %s: %w", flagName, flag.NoOptDefVal, err)
+			}
 		} else {
-			return nil, false, fmt.Errorf("missing value for non-boolean flag: %s", flagName)
+			if len(c.args) == 0 {
+				return nil, false, fmt.Errorf("missing value for non-boolean flag: %s", flagName)
+			}
+			if err := owner.set(flag, c.args[0]); err != nil {
+				return nil, false, fmt.Errorf("invalid value for flag %s: %s: %w", flagName, flag.redact(c.args[0]), err)
+			}
+			c.args = c.args[1:]
 		}
 		return nil, true, nil
 	}
 	// Check if it's a short flag or a shorthand for a long flag
-	if strings.HasPrefix(arg, "-") {
-		flagNames := strings.TrimPrefix(arg, "-")
-		for i, flagName := range flagNames {
-			flag := c.formal[c.accepts(string(flagName))]
-			if flag == nil {
-				return nil, false, fmt.Errorf("unknown flag: %s", string(flagName))
+	flagNames := strings.TrimPrefix(arg, "-")
+	for i, flagName := range flagNames {
+		owner, flag := c.reach(func(cmd *Command) *Flag { return cmd.formal[cmd.accepts(string(flagName))] })
+		if flag == nil {
+			return nil, false, c.unknown(string(flagName))
+		}
+		// Check if the flag is a bool flag
+		if flag.Value.IsBool() {
+			if err := owner.set(flag, "true"); err != nil {
+				return nil, false, fmt.Errorf("invalid value for flag %s: %w", string(flagName), err)
 			}
-			// Check if the flag is a bool flag
-			if flag.Value.IsBool() {
-				flag.Value.Set("true")
-			} else if i == len(flagNames)-1 {
-				// Last term is assumed to be the value for non-boolean flag
-				if len(c.args) == 0 {
-					return nil, false, fmt.Errorf("missing value for non-boolean flag: %s", string(flagName))
-				}
-				flag.Value.Set(c.args[0])
-				c.args = c.args[1:]
-			} else {
-				return nil, false, fmt.Errorf("unexpected value for boolean flag: %s", string(flagName))
+		} else if flag.NoOptDefVal != "" {
+			if err := owner.set(flag, flag.NoOptDefVal); err != nil {
+				return nil, false, fmt.Errorf("invalid value for flag %s: %s: %w", string(flagName), flag.NoOptDefVal, err)
+			}
+		} else if i == len(flagNames)-1 {
+			// Last term is assumed to be the value for non-boolean flag
+			if len(c.args) == 0 {
+				return nil, false, fmt.Errorf("missing value for non-boolean flag: %s", string(flagName))
+			}
+			if err := owner.set(flag, c.args[0]); err != nil {
+				return nil, false, fmt.Errorf("invalid value for flag %s: %s: %w", string(flagName), flag.redact(c.args[0]), err)
 			}
+			c.args = c.args[1:]
+		} else {
+			return nil, false, fmt.Errorf("unexpected value for boolean flag: %s", string(flagName))
 		}
-		return nil, true, nil
 	}
-	return nil, false, nil
+	return nil, true, nil
+}
+
+// reach returns the first flag found by find on the command or, if GlobalOptions is set,
+// on its nearest ancestor, along with the command it was found on
+func (c *Command) reach(find func(*Command) *Flag) (*Command, *Flag) {
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		if flag := find(cmd); flag != nil {
+			return cmd, flag
+		}
+		if !c.GlobalOptions {
+			break
+		}
+	}
+	return c, nil
+}
+
+// assignment returns the flag named by a bare "key=value" argument
+// returns nil unless BareAssignments is enabled and key is exactly the name of a defined flag
+func (c *Command) assignment(arg string) *Flag {
+	if !c.BareAssignments {
+		return nil
+	}
+	key, _, ok := strings.Cut(arg, "=")
+	if !ok || key == "" {
+		return nil
+	}
+	return c.formal[key]
+}
+
+// trimDashes removes the one or two dashes prefixing a flag
+func trimDashes(arg string) string {
+	if strings.HasPrefix(arg, "--") {
+		return arg[2:]
+	}
+	return strings.TrimPrefix(arg, "-")
 }
 
 // MustParse parses flag definitions from the argument list
@@ -551,40 +928,43 @@ func (c *Command) MustParse() {
 	c.Handle(c.Parse())
 }
 
-// WarnIf prints help and exits if help is needed
+// WarnIf writes the formatted message to the command's output unless b holds
 func (c *Command) WarnIf(b bool, fmtArgs ...any) {
-	if !b {
-		if len(fmtArgs) > 0 {
-			msg := fmtArgs[0].(string)
-			if []rune(msg)[len([]rune(msg))-1] != '\n' {
-				msg += "\n"
-			}
-			c.Warnf(msg, fmtArgs[1:]...)
-		}
+	if !b && len(fmtArgs) > 0 {
+		c.Warnf(fmtArgs[0].(string), fmtArgs[1:]...)
 	}
 }
 
-// HelpIf prints help and exits if help is needed
-func (c *Command) HelpIf(b bool, fmtArgs ...any) {
-	if b {
-		if len(fmtArgs) > 0 {
-			msg := fmtArgs[0].(string)
-			if []rune(msg)[len([]rune(msg))-1] != '\n' {
-				msg += "\n"
-			}
-			fmt.Printf(msg, fmtArgs[1:]...)
+// HelpIf writes the formatted message, if any, and the usage message to the command's output,
+// then exits, if b holds. It reports whether it did so, which matters only if the exit function returns.
+func (c *Command) HelpIf(b bool, fmtArgs ...any) bool {
+	if !b {
+		return false
+	}
+	if len(fmtArgs) > 0 {
+		msg := fmtArgs[0].(string)
+		if !strings.HasSuffix(msg, "\n") {
+			msg += "\n"
 		}
-		c.PrintHelp()
+		fmt.Fprintf(c.Output(), msg, fmtArgs[1:]...)
 	}
+	c.PrintHelp()
+	return true
 }
 
 // Parse parses flag definitions from the argument list, which should not
 // include the command name. Must be called after all flags in the Command
 // are defined and before flags are accessed by the program.
 // The return value will be ErrHelp if -help or -h were set but not defined.
+// Where a flag and a child share a name, dashed arguments always refer to the flag,
+// and the first free argument to the child; Check reports such collisions.
+// If ResponseFiles is set on a root command, each argument of the form "@file" found where a flag may be,
+// before any "--" and not as the value of a flag, is replaced by the arguments the file holds, separated by
+// whitespace and quoted as for SplitLine, which may name other files in turn, up to ResponseFileDepth deep;
+// this sidesteps the length limit of command lines on Windows. Write "@@x" there for the argument "@x".
+// Prefixes given to HandlePrefix take precedence, and values such as FromFile's "--flag @path" are left alone.
 // func (c *Command) Parse(arguments []string) error {
 func (c *Command) Parse(args ...string) error {
-	defer c.setparsed()
 	switch {
 	case c.parent != nil:
 		c.args = c.parent.args[1:]
@@ -593,22 +973,92 @@ func (c *Command) Parse(args ...string) error {
 	default:
 		c.args = os.Args[1:]
 	}
+	return c.parse()
+}
+
+// parse parses the command's pending args
+func (c *Command) parse() error {
+	defer profileRecord(c, phaseParse, profileStart())
+	defer c.setparsed()
+	c.sub = nil // a previous parse may have dispatched elsewhere
+	if c.parent == nil {
+		c.responseEnds = nil
+	}
+	var first error
 	for {
 		child, seen, err := c.parseOne()
 		if seen {
 			continue
 		}
+		if child == nil && err == nil {
+			if child, err = c.guess(); err != nil {
+				c.Handle(err)
+				return err
+			}
+		}
 		if child != nil {
+			if err := child.gate(); err != nil {
+				c.Handle(err)
+				return err
+			}
+			// completion must work however incomplete the command line before it is
+			if err := c.resolve(); err != nil && child.name != CompleteName {
+				c.Handle(err)
+				return err
+			}
+			if err := child.applyDefaults(); err != nil {
+				c.Handle(err)
+				return err
+			}
+			c.sub = child
 			return child.Parse()
 		}
 		if err == nil {
 			break
 		}
+		if first == nil {
+			first = err
+		}
+		c.Handle(err)
+	}
+	if first != nil {
+		return first
+	}
+	if format := c.helpFormat(); format != "" {
+		if err := c.WriteHelp(c.Stdout(), format); err != nil {
+			return err
+		}
+		return ErrHelp
+	}
+	if err := c.resolve(); err != nil {
 		c.Handle(err)
+		return err
 	}
 	return nil
 }
 
+// resolve completes the parse of the command's own flags, filling those not given on the command line
+// from the environment, then the config, then checking that the required ones are set
+// and that the endpoints of those given to Flag.Dial are reachable
+func (c *Command) resolve() error {
+	if err := c.applyEnv(); err != nil {
+		return err
+	}
+	if err := c.applyConfig(); err != nil {
+		return err
+	}
+	if err := c.checkProfile(); err != nil {
+		return err
+	}
+	if err := c.checkRequired(); err != nil {
+		return err
+	}
+	if err := c.runChecks(); err != nil {
+		return err
+	}
+	return c.checkReachable()
+}
+
 func (c *Command) setparsed() {
 	c.parsed = true
 }
@@ -620,29 +1070,39 @@ func (c Command) Parsed() bool {
 
 func (c *Command) SetHelpFlag(name string, short bool) (out *Flag) {
 	delete(c.formal, HelpName)
-	p := new(bool)
-	out = c.Var(newBoolValue(false, p), name, "print this message", short)
+	c.invalidate()
+	out = c.Var(newHelpValue(), name, helpUsage, short)
 	HelpName = name
 	return
 }
 
+// DisableHelpFlag removes the command's help flag.
+// Thereafter the help predicates report false, and parsing the flag's name, or its initial,
+// returns ErrHelp without rendering any help
+func (c *Command) DisableHelpFlag() {
+	delete(c.formal, HelpName)
+	delete(c.actual, HelpName)
+	c.invalidate()
+}
+
 // func (c *Command) HelpFlag() *Flag {}
 
 // NewCommand returns a new, empty flag set with the specified name and
 // error handling property. If the name is not empty, it will be printed
 // in the default usage message and in error messages.
 func NewCommand(name string, errorPolicy ErrorPolicy) *Command {
+	start := profileStart()
 	c := &Command{
 		name:        name,
 		errorPolicy: errorPolicy,
 		Format:      "%s [options] [args...]",
-		URL:         EnvUrl(name),
+		Footer:      DefaultFooter,
+		urlPending:  true,
 	}
 	if name != HelpName {
-		p := new(bool)
-		c.Var(newBoolValue(false, p), HelpName, "print this message", true)
-		c.Usage = c.defaultUsage
+		c.Var(newHelpValue(), HelpName, helpUsage, true)
 	}
+	profileRecord(c, phaseConstruct, start)
 	return c
 }
 
@@ -651,11 +1111,33 @@ func NewCommand(name string, errorPolicy ErrorPolicy) *Command {
 // in the default usage message and in error messages.
 // The ErrorPolicy will be inherited from the command.
 // If the name is set to "help" it will not have a help flag
-func (c *Command) NewChild(name string) *Command {
+// The summary, if one is given, is listed beside the child's name in the parent's usage message;
+// it may also be set later through the child's Summary field.
+func (c *Command) NewChild(name string, summary ...string) *Command {
+	c.panicSealed("command", name)
+	if c.isReserved(name) {
+		c.refuse("command %q is reserved", name)
+	}
+	if _, ok := c.formal[name]; ok && name != HelpName {
+		c.warnCollision(name) // a help child beside the help flag is conventional
+	}
 	s := NewCommand(name, c.errorPolicy)
+	s.Summary = strings.Join(summary, " ")
 	s.parent = c
 	s.URL = c.URL
+	s.Version = c.Version
+	s.Footer = c.Footer
+	s.DefaultStyle = c.DefaultStyle
+	s.GlobalOptions = c.GlobalOptions
+	s.StrictNames = c.StrictNames
+	s.HelpHint = c.HelpHint
+	s.RerunHint = c.RerunHint
+	s.Guess = c.Guess
+	s.unsorted = c.unsorted
+	s.negateBools = c.negateBools
+	s.addedBy = c.addedBy
 	c.children = append(c.children, s)
+	c.invalidate()
 	return s
 }
 
@@ -669,16 +1151,43 @@ func (c *Command) first() *Command {
 	return c
 }
 
+// child returns the child with the given name or alias, or nil if there is none
+func (c *Command) child(name string) *Command {
+	return c.matcher().children[name]
+}
+
+// leaf returns the deepest command dispatched to by the last parse
+func (c *Command) leaf() *Command {
+	for c.sub != nil {
+		c = c.sub
+	}
+	return c
+}
+
 // Run a command's "Main" attribute on a specific set of arguments
 // Overrides os.Args usage
+// If the args name a child, the child's Main is run instead, in the directory given to any Chdir flag.
 // Returns ErrNilMain if command.Main is nil.
-func (c *Command) Execute(args ...string) error {
+// Flags already set by a previous run are forgotten, but their values are not reset;
+// Execute a Clone to start from a command's pristine state or to run it concurrently.
+// The command's args are restored when Execute returns.
+// Under js, exits that would stop the program make Execute return an *ExitError instead.
+func (c *Command) Execute(args ...string) (err error) {
+	defer catchExit(&err)
+	defer func(saved []string) { c.args = saved }(c.args)
 	c.args = args
+	c.forget()
 
-	err := c.Parse()
+	err = c.parse()
 	if err == nil {
-		if c.Main != nil {
-			return c.Main(c)
+		leaf := c.leaf()
+		if leaf.Main != nil {
+			undo, err := c.prepare()
+			defer undo()
+			if err != nil {
+				return err
+			}
+			return runIn(c.workdir(), func() error { return leaf.Main(leaf) })
 		}
 		return ErrNilMain
 	}
@@ -694,17 +1203,35 @@ func (c *Command) Init(name string, errorPolicy ErrorPolicy) {
 	c.errorPolicy = errorPolicy
 }
 
-// a bash-value-safe wrapper on os.Exit
+// a bash-value-safe wrapper on the command's exit function
 // appends a \n to msg if msg's non-empty and not \n terminated
-// always writes to stderr
+// writes to the command's output
 func (c Command) Exit(msg string, code uint8) {
 	c.Warn(but.New(msg))
-	os.Exit(int(code))
+	c.terminate(int(code))
+}
+
+// SetExit replaces the function called, in place of os.Exit, when the command or its children exit.
+// If fn is nil, the parent's exit function, or os.Exit, is used; under js, where os.Exit would
+// stop the wasm instance, the default stops the command and makes Execute return an *ExitError.
+func (c *Command) SetExit(fn func(code int)) {
+	c.exit = fn
+}
+
+// terminate calls the nearest exit function set on the command or its ancestors
+func (c *Command) terminate(code int) {
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		if cmd.exit != nil {
+			cmd.exit(code)
+			return
+		}
+	}
+	defaultExit(code)
 }
 
-// Print the Usage() text and exit with error code #1
-func (c Command) PrintHelp() {
-	c.Exit(c.Usage(), 1)
+// Print the usage message and exit with error code #1
+func (c *Command) PrintHelp() {
+	c.Exit(c.UsageString(), 1)
 }
 
 // Behave as consistent with the chosen error handling method
@@ -716,12 +1243,24 @@ func (c Command) PrintHelp() {
 // ).
 func (c Command) Handle(err error) {
 	if err != nil {
+		if errors.Is(err, ErrHelp) {
+			if c.errorPolicy == ExitOnError {
+				c.terminate(0)
+			}
+			return
+		}
 		switch c.errorPolicy {
 		case ContinueOnError:
-			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(c.Output(), err)
 		case ExitOnError:
-			fmt.Fprintln(os.Stderr, err)
-			os.Exit(1)
+			fmt.Fprintln(c.Output(), err)
+			if hint := c.helpHint(); hint != "" {
+				fmt.Fprintln(c.Output(), hint)
+			}
+			if hint := c.rerunHint(); hint != "" {
+				fmt.Fprintln(c.Output(), hint)
+			}
+			c.terminate(1)
 		case PanicOnError:
 			panic(err)
 		default:
@@ -730,7 +1269,7 @@ func (c Command) Handle(err error) {
 	}
 }
 
-// print an error to stderr if, and only if, it is not nil
+// print an error to the command's output if, and only if, it is not nil
 func (c Command) Warn(err error) {
 	if err == nil {
 		return
@@ -740,11 +1279,8 @@ func (c Command) Warn(err error) {
 		if msg[len(msg)-1] != '\n' {
 			msg += "\n"
 		}
-		os.Stderr.WriteString(msg)
+		io.WriteString(c.Output(), msg)
 	}
-	// if err != nil {
-	// 	os.Stderr.WriteString(err.Error() + "\n")
-	// }
 }
 
 func (c Command) Warnf(msg string, args ...any) {
@@ -758,10 +1294,9 @@ func (c Command) Warnf(msg string, args ...any) {
 }
 
 // Check if command is receiving input via stdin
+// False where the input can't be inspected, such as under js.
 func (c *Command) Receiving() bool {
-	stat, err := os.Stdin.Stat()
-	c.Handle(err)
-	return stat.Size() > 0
+	return pending(c.Input())
 }
 
 // Infer whether or not the user needs help
@@ -771,12 +1306,11 @@ func (c *Command) Receiving() bool {
 //		the help flag was invoked
 //		or no args/flags were invoked
 //
-// hacker note:
-//
-//	herein lies a panic that will trigger if you unset the default help flag
+// Always false if the help flag has been disabled
 func (c Command) HelpWorthy() bool {
-	_, defined := c.formal[HelpName]
-	but.MustBool(defined, "help flag %q is undefined for this command", HelpName)
+	if _, defined := c.formal[HelpName]; !defined {
+		return false
+	}
 
 	_, used := c.actual[HelpName]
 
@@ -792,31 +1326,22 @@ func (c Command) HelpWorthy() bool {
 //	 parsed
 //		the help flag was invoked
 //
-// hacker note:
-//
-//	herein lies a panic that will trigger if you unset the default help flag
+// Always false if the help flag has been disabled
 func (c Command) HelpNeeded() bool {
-	_, defined := c.formal[HelpName]
-	// but.Must(defined, "help flag %q is undefined for this command", HelpName)
-	println("defined", defined)
-	but.MustBool(defined, errUndefinedHelp.Fmt(HelpName))
+	if _, defined := c.formal[HelpName]; !defined {
+		return false
+	}
 
 	_, used := c.actual[HelpName]
 
 	return c.Parsed() && used
 }
 
-const errUndefinedHelp but.Note = "help flag %q is undefined for this command"
-
 // Deprecated
 // Checks if
 //
 //	 HelpNeeded
 //		or HelpWorthy
-//
-// hacker note:
-//
-//	hereinlies a panic that will trigger if you unset the default help flag
 func (c *Command) HelpWanted() bool {
 	return c.HelpNeeded() || c.HelpWorthy()
 }
@@ -852,12 +1377,60 @@ func isFstr(s string) bool {
 
 // derive a url from the $REPO_HOST and $DEVELOPER environment variables
 // name refers to the name of the cli/command
-func EnvUrl(name string) string {
+// returns an empty string, and no error, if $REPO_HOST is unset
+func EnvUrl(name string) (string, error) {
+	return envURL(name, os.LookupEnv)
+}
+
+// envURL is EnvUrl, reading the environment through lookup
+func envURL(name string, lookup func(string) (string, bool)) (string, error) {
 	var (
-		repoHost = os.Getenv("REPO_HOST")
-		devName  = os.Getenv("DEVELOPER")
+		repoHost, _ = lookup("REPO_HOST")
+		devName, _  = lookup("DEVELOPER")
 	)
+	if repoHost == "" {
+		return "", nil
+	}
 	out, err := url.JoinPath(repoHost, devName, name)
-	but.Must(err)
+	if err != nil {
+		return "", err
+	}
+	return out, nil
+}
+
+// url returns the command's URL, resolving it from the command's name, or inheriting the parent's,
+// the first time it's needed, unless it has been set
+func (c *Command) url() string {
+	if c.urlPending && c.URL == "" {
+		if c.parent != nil {
+			c.URL = c.parent.url()
+		} else {
+			c.URL = urlResolver(c.name, c.lookupEnv)
+		}
+	}
+	c.urlPending = false
+	return c.URL
+}
+
+// urlResolver derives the URL of root commands from their names and environments
+var urlResolver = envURLResolver
+
+// envURLResolver is the default url resolver
+// it wraps EnvUrl, discarding malformed urls
+func envURLResolver(name string, lookup func(string) (string, bool)) string {
+	out, err := envURL(name, lookup)
+	if err != nil {
+		return ""
+	}
 	return out
 }
+
+// SetURLResolver replaces the function used to derive the URLs of root commands from their names,
+// which happens when their help is first rendered. Passing nil restores the default, EnvUrl based, resolver.
+func SetURLResolver(fn func(name string) string) {
+	if fn == nil {
+		urlResolver = envURLResolver
+		return
+	}
+	urlResolver = func(name string, _ func(string) (string, bool)) string { return fn(name) }
+}
@@ -0,0 +1,125 @@
+package mandy
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// CommandLine is the default command, parsed from os.Args by Parse.
+// The top-level functions, such as Int and Parse, are wrappers for its methods.
+var CommandLine = NewCommand(programName(), ExitOnError)
+
+// programName is the name the running program was invoked by, without its directory
+func programName() string {
+	if len(os.Args) == 0 {
+		return ""
+	}
+	return filepath.Base(os.Args[0])
+}
+
+// Bool defines a bool flag on the CommandLine, bound to p
+func Bool(p *bool, name string, value bool, usage string, short bool) *Flag {
+	return CommandLine.Bool(p, name, value, usage, short)
+}
+
+// Int defines an int flag on the CommandLine, bound to p
+func Int(p *int, name string, value int, usage string, short bool) *Flag {
+	return CommandLine.Int(p, name, value, usage, short)
+}
+
+// Int64 defines an int64 flag on the CommandLine, bound to p
+func Int64(p *int64, name string, value int64, usage string, short bool) *Flag {
+	return CommandLine.Int64(p, name, value, usage, short)
+}
+
+// Uint defines a uint flag on the CommandLine, bound to p
+func Uint(p *uint, name string, value uint, usage string, short bool) *Flag {
+	return CommandLine.Uint(p, name, value, usage, short)
+}
+
+// Uint64 defines a uint64 flag on the CommandLine, bound to p
+func Uint64(p *uint64, name string, value uint64, usage string, short bool) *Flag {
+	return CommandLine.Uint64(p, name, value, usage, short)
+}
+
+// String defines a string flag on the CommandLine, bound to p
+func String(p *string, name string, value string, usage string, short bool) *Flag {
+	return CommandLine.String(p, name, value, usage, short)
+}
+
+// Secret defines a secret string flag on the CommandLine, bound to p
+func Secret(p *string, name string, value string, usage string, short bool) *Flag {
+	return CommandLine.Secret(p, name, value, usage, short)
+}
+
+// Float64 defines a float64 flag on the CommandLine, bound to p
+func Float64(p *float64, name string, value float64, usage string, short bool) *Flag {
+	return CommandLine.Float64(p, name, value, usage, short)
+}
+
+// Duration defines a time.Duration flag on the CommandLine, bound to p
+func Duration(p *time.Duration, name string, value time.Duration, usage string, short bool) *Flag {
+	return CommandLine.Duration(p, name, value, usage, short)
+}
+
+// DurationSlice defines a []time.Duration flag on the CommandLine, bound to p
+func DurationSlice(p *[]time.Duration, name string, value []time.Duration, usage string, short bool) *Flag {
+	return CommandLine.DurationSlice(p, name, value, usage, short)
+}
+
+// Func defines a flag on the CommandLine that calls fn with each of its arguments
+func Func(fn func(string) error, name, usage string, short bool) *Flag {
+	return CommandLine.Func(fn, name, usage, short)
+}
+
+// Var defines a flag on the CommandLine with a user defined value
+func Var(value Getter, name string, usage string, short bool) *Flag {
+	return CommandLine.Var(value, name, usage, short)
+}
+
+// NewChild adds a child command to the CommandLine
+func NewChild(name string, summary ...string) *Command {
+	return CommandLine.NewChild(name, summary...)
+}
+
+// Parse parses the command-line flags from os.Args[1:]. Must be called
+// after all flags are defined and before flags are accessed by the program.
+// Errors are handled as per the CommandLine's ErrorPolicy, ExitOnError by default.
+func Parse() {
+	CommandLine.Parse(os.Args[1:]...)
+}
+
+// Parsed reports whether the command-line flags have been parsed
+func Parsed() bool { return CommandLine.Parsed() }
+
+// Arg returns the i'th command-line argument remaining after flags have been processed,
+// or an empty string if there is no such argument
+func Arg(i int) string { return CommandLine.Arg(i) }
+
+// NArg is the number of arguments remaining after flags have been processed
+func NArg() int { return CommandLine.NArg() }
+
+// Args returns the non-flag command-line arguments
+func Args() []string { return CommandLine.Args() }
+
+// NFlag returns the number of command-line flags that have been set
+func NFlag() int { return CommandLine.NFlag() }
+
+// Lookup returns the named command-line flag, or nil if none exists
+func Lookup(name string) *Flag { return CommandLine.Lookup(name) }
+
+// Set sets the value of the named command-line flag
+func Set(name, value string) error { return CommandLine.Set(name, value) }
+
+// VisitAll visits the command-line flags in the CommandLine's chosen order, calling fn for each
+func VisitAll(fn func(*Flag)) { CommandLine.VisitAll(fn) }
+
+// VisitSet visits the command-line flags that have been set, in the CommandLine's chosen order
+func VisitSet(fn func(*Flag)) { CommandLine.VisitSet(fn) }
+
+// Visit is VisitSet, under the name the standard library's flag package uses
+func Visit(fn func(*Flag)) { CommandLine.VisitSet(fn) }
+
+// Defaults describes the default values of the command-line flags
+func Defaults() string { return CommandLine.Defaults() }
@@ -0,0 +1,44 @@
+package mandy
+
+import (
+	"os"
+	"slices"
+	"testing"
+)
+
+func TestCommandLine(t *testing.T) {
+	defer func(args []string, cl *Command) { os.Args, CommandLine = args, cl }(os.Args, CommandLine)
+	ResetForTesting(nil)
+
+	var (
+		n       int
+		name    string
+		verbose bool
+	)
+	Int(&n, "n", 1, "a number", false)
+	String(&name, "name", "", "a name", false)
+	Bool(&verbose, "verbose", false, "talk more", true)
+
+	os.Args = []string{"prog", "-n", "3", "-v", "a", "b"}
+	Parse()
+	if !Parsed() {
+		t.Fatal("Parsed() = false after Parse")
+	}
+	if n != 3 || !verbose || name != "" {
+		t.Errorf("n, verbose, name = %d, %v, %q", n, verbose, name)
+	}
+	if NArg() != 2 || Arg(1) != "b" || !slices.Equal(Args(), []string{"a", "b"}) {
+		t.Errorf("args = %q", Args())
+	}
+	if NFlag() != 2 {
+		t.Errorf("NFlag() = %d, want 2", NFlag())
+	}
+	var set []string
+	Visit(func(f *Flag) { set = append(set, f.Name) })
+	if !slices.Equal(set, []string{"n", "verbose"}) {
+		t.Errorf("visited %q", set)
+	}
+	if err := Set("name", "gopher"); err != nil || name != "gopher" || Lookup("name").Value.String() != "gopher" {
+		t.Errorf("Set(name) = %v, name = %q", err, name)
+	}
+}
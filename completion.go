@@ -0,0 +1,184 @@
+package mandy
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A Completer suggests arguments for a flag.
+// Values may implement it to provide shell completion candidates.
+type Completer interface {
+	Complete(prefix string) []string
+}
+
+// Completions returns the candidates suggested by the flag's value for the given prefix
+// returns nil if the value does not implement Completer
+func (f *Flag) Completions(prefix string) []string {
+	if comp, ok := f.Value.(Completer); ok {
+		return comp.Complete(prefix)
+	}
+	return nil
+}
+
+// A FileCompleter is implemented by values naming files, whose completions are file names.
+// Extensions returns the extensions, such as ".json", of the files to offer, or nil for any file.
+type FileCompleter interface {
+	Extensions() []string
+}
+
+// CompleteName is the name of the child added by CompletionCommand
+const CompleteName = "__complete"
+
+// A CompletionDirective tells the shell what to do with the candidates printed by the CompletionCommand
+type CompletionDirective uint8
+
+const (
+	CompleteNoSpace     CompletionDirective = 1 << iota // don't follow the completed word with a space
+	CompleteFiles                                       // offer file names as well as the candidates
+	CompleteFilterFiles                                 // offer only file names, with the candidates as their extensions
+)
+
+// CompletionCommand adds a Hidden child, named CompleteName, implementing the completion protocol called by
+// shell completion scripts, so that the scripts need not know the command's structure. It is called as
+//
+//	tool __complete <cword> [words...]
+//
+// where words are the words of the command line after the program's name, and cword is the index of the one
+// being completed; it may equal the number of words, to complete a new one. It prints the candidates,
+// each on a line of its own and followed, if it has one, by a tab and its description;
+// then a last line holding a colon and the CompletionDirective, as a decimal number.
+// WriteCompletion writes the scripts calling it for bash, zsh, and fish.
+func (c *Command) CompletionCommand() *Command {
+	child := c.NewChild(CompleteName, "print shell completion candidates")
+	child.Hidden = true
+	child.Main = func(self *Command) error {
+		args := self.Args()
+		if len(args) == 0 {
+			return fmt.Errorf("%w: %s wants the index of the word to complete", errParse, CompleteName)
+		}
+		cword, err := strconv.Atoi(args[0])
+		words := args[1:]
+		if err != nil || cword < 0 || cword > len(words) {
+			return fmt.Errorf("%w: %q is not the index of one of %d words", errParse, args[0], len(words))
+		}
+		prefix := ""
+		if cword < len(words) {
+			prefix = words[cword]
+		}
+		candidates, directive := self.parent.complete(words[:cword], prefix)
+		w := bufio.NewWriter(self.Stdout())
+		for _, cand := range candidates {
+			fmt.Fprintln(w, cand)
+		}
+		fmt.Fprintf(w, ":%d\n", directive)
+		return w.Flush()
+	}
+	return child
+}
+
+// complete returns the candidates, as printed by the CompletionCommand, for a word beginning with prefix,
+// given the words before it
+func (c *Command) complete(before []string, prefix string) ([]string, CompletionDirective) {
+	cmd, pending, positional := c, (*Flag)(nil), false
+	for _, word := range before {
+		switch {
+		case pending != nil:
+			pending = nil
+		case positional:
+		case word == "--":
+			positional = true
+		case len(word) > 1 && word[0] == '-':
+			if !strings.Contains(word, "=") {
+				name := strings.TrimPrefix(word, "--")
+				if name == word {
+					name = word[len(word)-1:] // a short cluster's last flag takes the value
+				}
+				if flag := cmd.formal[cmd.accepts(name)]; flag != nil && !flag.Value.IsBool() && flag.NoOptDefVal == "" {
+					pending = flag
+				}
+			}
+		case cmd.child(word) != nil:
+			cmd = cmd.child(word)
+		default:
+			positional = true // flag parsing stops at the first free argument
+		}
+	}
+	switch {
+	case pending != nil:
+		return cmd.completeValue(pending, "", prefix)
+	case !positional && strings.HasPrefix(prefix, "--") && strings.Contains(prefix, "="):
+		name, value, _ := strings.Cut(prefix, "=")
+		if flag := cmd.formal[cmd.accepts(name[2:])]; flag != nil {
+			return cmd.completeValue(flag, name+"=", value)
+		}
+		return nil, 0
+	case !positional && strings.HasPrefix(prefix, "-"):
+		return cmd.completeFlags(prefix), 0
+	case !positional:
+		var out []string
+		for _, child := range cmd.children {
+			if !child.hidden() && strings.HasPrefix(child.name, prefix) {
+				out = append(out, completion(child.name, child.Summary))
+			}
+		}
+		if len(out) > 0 || len(cmd.children) > 0 && cmd.Main == nil {
+			return out, 0
+		}
+	}
+	return nil, CompleteFiles
+}
+
+// completeFlags returns the long forms, and negated forms, of the command's flags beginning with prefix
+func (c *Command) completeFlags(prefix string) (out []string) {
+	for flag := range c.Flags() {
+		if flag.IsDerived() {
+			continue // there's nothing to set
+		}
+		_, desc := UnquoteDescription(flag)
+		names := []string{"--" + flag.Name}
+		if flag.negatable && flag.Value.IsBool() {
+			names = append(names, "--"+NegationPrefix+flag.Name)
+		}
+		for _, name := range names {
+			if strings.HasPrefix(name, prefix) {
+				out = append(out, completion(name, desc))
+			}
+		}
+	}
+	return out
+}
+
+// completeValue returns the candidates for the flag's value, each preceded by lead, for a value beginning with prefix
+func (c *Command) completeValue(flag *Flag, lead, prefix string) ([]string, CompletionDirective) {
+	if fc, ok := flag.Value.(FileCompleter); ok {
+		if exts := fc.Extensions(); len(exts) > 0 {
+			return exts, CompleteFilterFiles
+		}
+		return nil, CompleteFiles
+	}
+	if _, ok := flag.Value.(Completer); !ok {
+		return nil, CompleteFiles
+	}
+	var out []string
+	directive := CompleteNoSpace
+	for _, cand := range c.completions(flag, prefix) {
+		out = append(out, lead+cand)
+		if !strings.HasSuffix(cand, "=") && !strings.HasSuffix(cand, "/") {
+			directive = 0 // only partial words, such as a map's "key=", go unspaced
+		}
+	}
+	if len(out) == 0 {
+		directive = 0
+	}
+	return out, directive
+}
+
+// completion renders a candidate with its description, if it has one
+func completion(word, desc string) string {
+	if desc = strings.TrimSpace(strings.SplitN(desc, "\n", 2)[0]); desc != "" {
+		return word + "\t" + desc
+	}
+	return word
+}
@@ -0,0 +1,142 @@
+package mandy
+
+import (
+	"bytes"
+	"os/exec"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type schemaFile string
+
+func (s *schemaFile) Set(v string) error   { *s = schemaFile(v); return nil }
+func (s *schemaFile) Get() any             { return string(*s) }
+func (s *schemaFile) String() string       { return string(*s) }
+func (s *schemaFile) IsBool() bool         { return false }
+func (s *schemaFile) Extensions() []string { return []string{".json", ".yaml"} }
+
+func TestCompletionCommand(t *testing.T) {
+	var (
+		verbose, dry bool
+		level, name  string
+		port         int
+		schema       schemaFile
+	)
+	c := NewCommand("tool", ContinueOnError)
+	c.Bool(&verbose, "verbose", false, "talk more", true)
+	c.Enum(&level, "level", "info", "log `level`", true, "debug", "info", "warn")
+	c.String(&name, "name", "", "", false).Required()
+	c.CompletionCommand()
+	serve := c.NewChild("serve", "run the server")
+	serve.NegateBools()
+	serve.Bool(&dry, "dry-run", false, "change nothing", false)
+	serve.Int(&port, "port", 80, "", true)
+	serve.Var(&schema, "schema", "", false)
+	serve.Main = func(*Command) error { return nil }
+	c.NewChild("secret", "").Hidden = true
+
+	var out bytes.Buffer
+	c.SetStdout(&out)
+	for _, tc := range []struct {
+		words     []string
+		want      []string
+		directive string
+	}{
+		{[]string{"0", "s"}, []string{"serve\trun the server"}, "0"},
+		{[]string{"0", "--"}, []string{"--help\tprint this message (or render it as plain, man, md, or json)", "--level\tlog level", "--name", "--verbose\ttalk more"}, "0"},
+		{[]string{"1", "--level"}, []string{"debug", "info", "warn"}, "0"},
+		{[]string{"1", "-vl", "d"}, []string{"debug"}, "0"},
+		{[]string{"0", "--level=w"}, []string{"--level=warn"}, "0"},
+		{[]string{"2", "-v", "serve", "--d"}, []string{"--dry-run\tchange nothing"}, "0"},
+		{[]string{"1", "serve", "--no"}, []string{"--no-dry-run\tchange nothing"}, "0"},
+		{[]string{"2", "serve", "--port"}, nil, "2"},
+		{[]string{"2", "serve", "--schema", "x"}, []string{".json", ".yaml"}, "4"},
+		{[]string{"1", "serve", "x"}, nil, "2"},
+		{[]string{"1", "--", "s"}, nil, "2"},
+	} {
+		out.Reset()
+		if err := c.Clone().Execute(append([]string{CompleteName}, tc.words...)...); err != nil {
+			t.Errorf("%q: %v", tc.words, err)
+			continue
+		}
+		lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+		got, directive := lines[:len(lines)-1], lines[len(lines)-1]
+		if len(got) == 0 {
+			got = nil
+		}
+		if !reflect.DeepEqual(got, tc.want) || directive != ":"+tc.directive {
+			t.Errorf("%q: completed %q with %s, want %q with :%s", tc.words, got, directive, tc.want, tc.directive)
+		}
+	}
+
+	c.SetOutput(&out)
+	for _, words := range [][]string{nil, {"x"}, {"3", "a"}} {
+		if err := c.Clone().Execute(append([]string{CompleteName}, words...)...); err == nil {
+			t.Errorf("completing with arguments %q succeeded", words)
+		}
+	}
+}
+
+func TestWriteCompletion(t *testing.T) {
+	c := NewCommand("tool", ContinueOnError)
+	var out bytes.Buffer
+	if err := c.WriteCompletion(&out, "bash"); err == nil {
+		t.Error("wrote a completion script without a completion command")
+	}
+	c.CompletionCommand()
+	if err := c.WriteCompletion(&out, "csh"); err == nil {
+		t.Error("wrote a completion script for csh")
+	}
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		out.Reset()
+		if err := c.NewChild(shell, "").WriteCompletion(&out, shell); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(out.String(), "tool __complete ") {
+			t.Errorf("the %s script doesn't call tool __complete:\n%s", shell, out.String())
+		}
+	}
+
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("no bash to run the script")
+	}
+	out.Reset()
+	if err := c.WriteCompletion(&out, "bash"); err != nil {
+		t.Fatal(err)
+	}
+	// tool stands in for the program, recording its arguments and replying with a canned completion
+	script := out.String() + `
+tool() { printf '%s|' "$@" >&3; printf %b "$REPLY_"; }
+COMP_WORDS=("${WORDS[@]}") COMP_CWORD=$CWORD
+_tool_complete 3>&1
+printf '\n'
+printf '%s|' "${COMPREPLY[@]}"
+`
+	for _, tc := range []struct {
+		words  []string
+		cword  int
+		reply  string
+		args   string
+		result string
+	}{
+		{[]string{"tool", "s"}, 1, `serve\trun the server\n:0\n`, "__complete|0|s|", "serve|"},
+		{[]string{"tool", "-v", ""}, 2, `serve\nsecret\n:0\n`, "__complete|1|-v||", "serve|secret|"},
+		{[]string{"tool", "--level", "=", "w"}, 3, `--level=warn\n:0\n`, "__complete|0|--level=w|", "warn|"},
+		{[]string{"tool", "--level", "="}, 2, `--level=debug\n--level=info\n:1\n`, "__complete|0|--level=|", "debug|info|"},
+		{[]string{"tool", "--port", "8"}, 2, `:0\n`, "__complete|1|--port|8|", "|"},
+	} {
+		cmd := exec.Command("bash", "--norc", "--noprofile", "-c", `WORDS=("${@:2}") CWORD=$1; `+script, "bash", strconv.Itoa(tc.cword))
+		cmd.Args = append(cmd.Args, tc.words...)
+		cmd.Env = []string{"REPLY_=" + tc.reply}
+		got, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("%q: %v", tc.words, err)
+		}
+		args, result, _ := strings.Cut(string(got), "\n")
+		if args != tc.args || result != tc.result {
+			t.Errorf("%q: called tool with %q and completed %q, want %q and %q", tc.words, args, result, tc.args, tc.result)
+		}
+	}
+}
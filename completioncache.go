@@ -0,0 +1,113 @@
+package mandy
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// CompletionCacheFile is the file, in the StateDir named after the root command, holding cached completions
+const CompletionCacheFile = "completions.json"
+
+// CompletionName is the name of the child added by CompletionCacheCommand
+const CompletionName = "completion"
+
+// a cachedCompletion is the record of the candidates a completer gave for a prefix
+type cachedCompletion struct {
+	Time       time.Time     `json:"time"`
+	TTL        time.Duration `json:"ttl"` // the flag's, when the candidates were cached
+	Candidates []string      `json:"candidates"`
+}
+
+// CacheCompletions makes the CompletionCommand keep the candidates the flag's Completer suggests, for each prefix,
+// for ttl, so that completers that hit the network don't slow every keystroke. The cache lives in the
+// CompletionCacheFile. Expired candidates are still offered, rather than none, if the completer suggests nothing,
+// as it may when offline, until the cache is next written, which drops every expired entry.
+func (f *Flag) CacheCompletions(ttl time.Duration) *Flag {
+	f.cacheTTL = ttl
+	return f
+}
+
+// completions returns the candidates the flag suggests for the prefix, through the cache if the flag's are cached
+func (c *Command) completions(flag *Flag, prefix string) []string {
+	if flag.cacheTTL <= 0 {
+		return flag.Completions(prefix)
+	}
+	file, err := c.completionCache()
+	if err != nil {
+		return flag.Completions(prefix)
+	}
+	cache := readCompletionCache(file)
+	key := c.path() + " --" + flag.Name + "=" + prefix
+	entry, cached := cache[key]
+	now := c.Now()
+	if cached && now.Sub(entry.Time) < flag.cacheTTL {
+		return entry.Candidates
+	}
+	fresh := flag.Completions(prefix)
+	if len(fresh) == 0 && cached {
+		return entry.Candidates
+	}
+	for k, entry := range cache {
+		if now.Sub(entry.Time) >= entry.TTL {
+			delete(cache, k)
+		}
+	}
+	cache[key] = cachedCompletion{Time: now, TTL: flag.cacheTTL, Candidates: fresh}
+	// completions may run concurrently, so the file is replaced whole, lest a torn write discard the cache
+	if data, err := json.Marshal(cache); err == nil && os.MkdirAll(filepath.Dir(file), 0o700) == nil {
+		writeAtomic(file, data, 0o600) // a failure to cache only costs time
+	}
+	return fresh
+}
+
+// completionCache returns the path of the root command's CompletionCacheFile
+func (c *Command) completionCache() (string, error) {
+	root := c
+	for root.parent != nil {
+		root = root.parent
+	}
+	dir, err := stateDir(root.name, c.lookupEnv)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, CompletionCacheFile), nil
+}
+
+// readCompletionCache reads the cached completions in file, treating a missing or corrupt file as empty
+func readCompletionCache(file string) map[string]cachedCompletion {
+	cache := make(map[string]cachedCompletion)
+	if data, err := os.ReadFile(file); err == nil && json.Unmarshal(data, &cache) != nil {
+		clear(cache)
+	}
+	return cache
+}
+
+// ClearCompletionCache removes the completions cached for the command's flags by CacheCompletions
+func (c *Command) ClearCompletionCache() error {
+	file, err := c.completionCache()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(file); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// CompletionCacheCommand adds, beneath a child named CompletionName, which is added too if need be,
+// a "cache" child whose "clear" child calls ClearCompletionCache, as in "tool completion cache clear"
+func (c *Command) CompletionCacheCommand() *Command {
+	completion := c.child(CompletionName)
+	if completion == nil {
+		completion = c.NewChild(CompletionName, "manage shell completion")
+	}
+	cache := completion.NewChild("cache", "manage cached completions")
+	cache.NewChild("clear", "forget the cached completions").Main = func(self *Command) error {
+		return self.ClearCompletionCache()
+	}
+	return cache
+}
@@ -0,0 +1,97 @@
+package mandy
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// regionValue completes from a list that stands in for a remote one
+type regionValue struct {
+	stringValue
+	regions []string
+	calls   *int
+}
+
+func (r *regionValue) Complete(prefix string) (out []string) {
+	*r.calls++
+	for _, region := range r.regions {
+		if strings.HasPrefix(region, prefix) {
+			out = append(out, region)
+		}
+	}
+	return out
+}
+
+func TestCacheCompletions(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	var (
+		region string
+		calls  int
+		out    bytes.Buffer
+	)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewCommand("tool", ContinueOnError)
+	c.SetClock(ClockFunc(func() time.Time { return now }))
+	c.SetStdout(&out)
+	c.CompletionCommand()
+	c.CompletionCacheCommand()
+	value := &regionValue{stringValue: *newStringValue("", &region), regions: []string{"eu-west", "us-east"}, calls: &calls}
+	c.Var(value, "region", "", false).CacheCompletions(time.Hour)
+
+	complete := func() string {
+		t.Helper()
+		out.Reset()
+		if err := c.Clone().Execute(CompleteName, "1", "--region", "e"); err != nil {
+			t.Fatal(err)
+		}
+		return out.String()
+	}
+	if got := complete(); got != "eu-west\n:0\n" || calls != 1 {
+		t.Errorf("first completion printed %q after %d calls, want eu-west after 1", got, calls)
+	}
+	value.regions = []string{"eu-north"}
+	if got := complete(); got != "eu-west\n:0\n" || calls != 1 {
+		t.Errorf("completion within the TTL printed %q after %d calls, want the cached eu-west after 1", got, calls)
+	}
+	now = now.Add(2 * time.Hour)
+	if got := complete(); got != "eu-north\n:0\n" || calls != 2 {
+		t.Errorf("completion after the TTL printed %q after %d calls, want eu-north after 2", got, calls)
+	}
+	value.regions = nil
+	now = now.Add(2 * time.Hour)
+	if got := complete(); got != "eu-north\n:0\n" || calls != 3 {
+		t.Errorf("completion while offline printed %q after %d calls, want the expired eu-north after 3", got, calls)
+	}
+
+	file, err := c.completionCache()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(file); err != nil {
+		t.Fatalf("no cache at %s: %v", file, err)
+	}
+
+	value.regions = []string{"us-east"}
+	if err := c.Clone().Execute(CompleteName, "1", "--region", "u"); err != nil {
+		t.Fatal(err)
+	}
+	if cache := readCompletionCache(file); len(cache) != 1 || cache["tool --region=u"].TTL != time.Hour {
+		t.Errorf("writing the cache kept %v, want only the fresh entry for u", cache)
+	}
+	if want := filepath.Join(os.Getenv("XDG_STATE_HOME"), "tool", CompletionCacheFile); file != want {
+		t.Errorf("cache at %s, want %s", file, want)
+	}
+	if err := c.Clone().Execute(CompletionName, "cache", "clear"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(file); !os.IsNotExist(err) {
+		t.Errorf("after clearing, stat(%s) = %v", file, err)
+	}
+	if err := c.ClearCompletionCache(); err != nil {
+		t.Errorf("clearing an empty cache: %v", err)
+	}
+}
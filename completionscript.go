@@ -0,0 +1,138 @@
+package mandy
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteCompletion writes a completion script for the given shell, "bash", "zsh", or "fish", to w.
+// The scripts know nothing of the command's flags and children: they call the command's
+// CompletionCommand, which must have been added to its root, for the candidates of each word.
+func (c *Command) WriteCompletion(w io.Writer, shell string) error {
+	root := c
+	for root.parent != nil {
+		root = root.parent
+	}
+	if root.child(CompleteName) == nil {
+		return fmt.Errorf("mandy: %s has no %s command, see CompletionCommand", root.name, CompleteName)
+	}
+	var script string
+	switch shell {
+	case "bash":
+		script = bashCompletion
+	case "zsh":
+		script = zshCompletion
+	case "fish":
+		script = fishCompletion
+	default:
+		return fmt.Errorf("mandy: no completion script for %q, only bash, zsh, and fish", shell)
+	}
+	_, err := fmt.Fprintf(w, script, root.name, shellIdentifier(root.name), Quote(root.name, PosixShell), CompleteName)
+	return err
+}
+
+// shellIdentifier turns a program's name into one usable in the names of shell functions
+func shellIdentifier(name string) string {
+	return strings.Map(func(r rune) rune {
+		if 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// The completion scripts are formatted with the program's name, its name as an identifier,
+// its name quoted for the shell, and CompleteName. Each turns the directive's bits,
+// CompleteNoSpace (1), CompleteFiles (2), and CompleteFilterFiles (4), into the shell's options.
+
+// bashCompletion rejoins the words bash splits at "=" and ":", and trims what precedes the
+// last of those from the candidates, since bash replaces only the text following it
+const bashCompletion = `# bash completion for %[1]s, generated by mandy
+_%[2]s_complete() {
+	local IFS=$'\n' i word n
+	local -a words=() cands=()
+	for ((i = 1; i <= COMP_CWORD; i++)); do
+		word=${COMP_WORDS[i]} n=${#words[@]}
+		if ((n > 0)) && [[ $word == [=:] || ${words[n-1]} == *[=:] ]]; then
+			words[n-1]+=$word
+		else
+			words+=("$word")
+		fi
+	done
+	local cur=${words[${#words[@]}-1]} out
+	out=$(%[3]s %[4]s "$((${#words[@]} - 1))" "${words[@]}" 2>/dev/null) || return
+	local directive=${out##*:}
+	while IFS= read -r word; do
+		[[ -n $word ]] && cands+=("${word%%%%$'\t'*}")
+	done <<< "${out%%:*}"
+	COMPREPLY=()
+	if ((directive & 4)); then
+		for word in "${cands[@]}"; do
+			COMPREPLY+=($(compgen -f -X "!*$word" -- "$cur"))
+		done
+		COMPREPLY+=($(compgen -d -- "$cur"))
+		compopt -o filenames 2>/dev/null
+	else
+		COMPREPLY=("${cands[@]}")
+		if ((directive & 2)); then
+			COMPREPLY+=($(compgen -f -- "$cur"))
+			compopt -o filenames 2>/dev/null
+		fi
+	fi
+	((directive & 1)) && compopt -o nospace 2>/dev/null
+	if [[ $cur == *[=:]* ]]; then
+		local lead=${cur%%"${cur##*[=:]}"}
+		COMPREPLY=("${COMPREPLY[@]#"$lead"}")
+	fi
+	return 0
+}
+complete -F _%[2]s_complete %[1]s
+`
+
+const zshCompletion = `#compdef %[1]s
+# zsh completion for %[1]s, generated by mandy
+_%[2]s() {
+	local out directive line
+	local -a lines cands opts
+	out=$(%[3]s %[4]s $((CURRENT - 2)) "${(@)words[2,CURRENT]}" 2>/dev/null) || return
+	lines=("${(@f)out}")
+	directive=${lines[-1]#:}
+	for line in "${(@)lines[1,-2]}"; do
+		if [[ $line == *$'\t'* ]]; then
+			cands+=("${${line%%%%$'\t'*}//:/\\:}:${line#*$'\t'}")
+		else
+			cands+=("${line//:/\\:}")
+		fi
+	done
+	if ((directive & 4)); then
+		_files -g "*(${(j:|:)cands})"
+		return
+	fi
+	((directive & 1)) && opts=(-S '')
+	((${#cands})) && _describe -t values '%[1]s' cands "${opts[@]}"
+	((directive & 2)) && _files
+	return 0
+}
+compdef _%[2]s %[1]s
+`
+
+const fishCompletion = `# fish completion for %[1]s, generated by mandy
+function __%[2]s_complete
+	set -l words (commandline -opc)[2..-1] (commandline -ct)
+	set -l out (%[3]s %[4]s (math (count $words) - 1) $words 2>/dev/null); or return
+	set -l directive (string replace -- ':' '' $out[-1])
+	set -e out[-1]
+	if test (math "bitand($directive, 4)") -ne 0
+		for ext in $out
+			__fish_complete_suffix $ext
+		end
+		return
+	end
+	printf '%%s\n' $out
+	if test (math "bitand($directive, 2)") -ne 0
+		__fish_complete_path (commandline -ct)
+	end
+end
+complete -c %[1]s -f -a '(__%[2]s_complete)'
+`
@@ -0,0 +1,501 @@
+package mandy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ConfigName is the name of the child added by ConfigCommand
+const ConfigName = "config"
+
+// ConfigSep separates the names of children, and finally that of a flag, in config keys such as "serve.addr"
+const ConfigSep = "."
+
+// ErrConfigKey is returned for config keys that do not name a flag
+var ErrConfigKey = errors.New("mandy: config key names no flag")
+
+// configFile holds flag values keyed by flag name, with those of children nested under the children's names
+type configFile map[string]any
+
+// readConfig reads a config file in the format its extension implies, treating a missing file as an empty one
+func readConfig(path string) (configFile, error) {
+	return readConfigAs(path, ConfigAuto)
+}
+
+// readConfigAs reads a config file in the given format, treating a missing file as an empty one
+func readConfigAs(path string, format ConfigFormat) (configFile, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return make(configFile), nil
+	} else if err != nil {
+		return nil, err
+	}
+	return decodeConfig(path, format, data)
+}
+
+// decodeConfig decodes the contents of the config file at path in the given format
+func decodeConfig(path string, format ConfigFormat, data []byte) (configFile, error) {
+	decode, ok := configDecoders[formatOf(path, format)]
+	if !ok {
+		return nil, fmt.Errorf("%s: unknown config format %q", path, format)
+	}
+	f, err := decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return f, nil
+}
+
+// write replaces the file at path with the config, in the format its extension implies; dotenv files are unsupported.
+// The file keeps its permissions, or is made readable by its owner alone if it's new, since values may be secret.
+func (f configFile) write(path string) error {
+	var data []byte
+	switch formatOf(path, ConfigAuto) {
+	case ConfigYAML:
+		data = encodeYAML(f)
+	case ConfigTOML:
+		data = encodeTOML(f)
+	case ConfigINI:
+		data = encodeINI(f)
+	case ConfigDotenv:
+		return fmt.Errorf("%s: writing dotenv files: %w", path, errors.ErrUnsupported)
+	default:
+		var err error
+		if data, err = json.MarshalIndent(f, "", "\t"); err != nil {
+			return err
+		}
+		data = append(data, '\n')
+	}
+	mode := fs.FileMode(0o600)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return writeAtomic(path, data, mode)
+}
+
+// writeAtomic replaces the file at path with data by renaming a temporary file, written beside it, over it
+func writeAtomic(path string, data []byte, mode fs.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // fails harmlessly once renamed
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// lookup returns the value the config holds for key, as text
+func (f configFile) lookup(key string) (string, bool) {
+	names := strings.Split(key, ConfigSep)
+	section := f
+	for _, name := range names[:len(names)-1] {
+		if section, _ = section[name].(map[string]any); section == nil {
+			return "", false
+		}
+	}
+	v, ok := section[names[len(names)-1]]
+	if !ok {
+		return "", false
+	}
+	return configText(v), true
+}
+
+// set stores value under key, creating sections as needed
+func (f configFile) set(key, value string) {
+	names := strings.Split(key, ConfigSep)
+	section := map[string]any(f)
+	for _, name := range names[:len(names)-1] {
+		next, ok := section[name].(map[string]any)
+		if !ok {
+			next = make(map[string]any)
+			section[name] = next
+		}
+		section = next
+	}
+	section[names[len(names)-1]] = value
+}
+
+// keys returns the config's keys, flattened and sorted
+func (f configFile) keys() (out []string) {
+	var walk func(prefix string, section map[string]any)
+	walk = func(prefix string, section map[string]any) {
+		for name, v := range section {
+			if sub, ok := v.(map[string]any); ok {
+				walk(prefix+name+ConfigSep, sub)
+			} else {
+				out = append(out, prefix+name)
+			}
+		}
+	}
+	walk("", f)
+	sort.Strings(out)
+	return out
+}
+
+// configText renders a decoded config value as it would be given on the command line
+func configText(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case []any:
+		parts := make([]string, len(v))
+		for i, elem := range v {
+			parts[i] = configText(elem)
+		}
+		return strings.Join(parts, DefaultSeparator)
+	}
+	return fmt.Sprint(v)
+}
+
+// configFlag returns the flag that key names on c or its descendants
+func (c *Command) configFlag(key string) (*Flag, error) {
+	names := strings.Split(key, ConfigSep)
+	cmd := c
+	for _, name := range names[:len(names)-1] {
+		if cmd = cmd.child(name); cmd == nil {
+			return nil, fmt.Errorf("%w: %q", ErrConfigKey, key)
+		}
+	}
+	flag := cmd.formal[names[len(names)-1]]
+	if flag == nil || flag.Name == HelpName {
+		return nil, fmt.Errorf("%w: %q", ErrConfigKey, key)
+	}
+	return flag, nil
+}
+
+// configKeys returns the keys of the flags of c and its descendants, other than help and derived flags and those of
+// config and flags children, paired with their flags, in the order chosen by SortFlags
+func (c *Command) configKeys() (keys []string, flags []*Flag) {
+	var walk func(prefix string, cmd *Command)
+	walk = func(prefix string, cmd *Command) {
+		cmd.VisitAll(func(f *Flag) {
+			if f.Name != HelpName && !f.IsDerived() {
+				keys, flags = append(keys, prefix+f.Name), append(flags, f)
+			}
+		})
+		for _, child := range cmd.children {
+			if child.name != ConfigName && child.name != FlagsName {
+				walk(prefix+child.name+ConfigSep, child)
+			}
+		}
+	}
+	walk("", c)
+	return
+}
+
+// checkConfig validates each of the config's values, from the given origin, against the flag its key names,
+// without setting them
+func (c *Command) checkConfig(f configFile, origin Origin) error {
+	var errs []error
+	for _, key := range f.keys() {
+		flag, err := c.configFlag(key)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		value, _ := f.lookup(key)
+		resolved, err := flag.indirect(value, origin)
+		if err == nil {
+			err = cloneValue(flag.Value).Set(resolved)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid value for %s: %s: %w", key, flag.redact(value), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validateConfig separates a config's profiles from the rest of it, and checks them both
+func (c *Command) validateConfig(f configFile, origin Origin) (base configFile, profiles map[string]configFile, err error) {
+	if base, profiles, err = c.splitProfiles(f); err != nil {
+		return nil, nil, err
+	}
+	errs := []error{c.checkConfig(base, origin)}
+	for name, profile := range profiles {
+		if err := c.checkConfig(profile, origin); err != nil {
+			errs = append(errs, fmt.Errorf("profile %s: %w", name, err))
+		}
+	}
+	return base, profiles, errors.Join(errs...)
+}
+
+// LoadConfig reads flag values from the config file at path, for Parse to give the command's flags,
+// and its descendants', that are set neither on the command line nor by their Env variables;
+// so the command line wins over the environment, the environment over the config, and the config over defaults.
+// The file is read in the format its extension implies, as per ConfigFormat, or as JSON.
+// Keys name flags, and sections, objects, or tables named after children hold the children's flags;
+// a ProfileSection holds the profiles selected by the flag ConfigProfiles defines.
+// Values are checked against their flags; if any key or value is invalid, nothing is loaded and the errors
+// are returned. Values loaded by later calls override those loaded by earlier ones.
+func (c *Command) LoadConfig(path string) error {
+	return c.LoadConfigAs(path, ConfigAuto)
+}
+
+// LoadConfigAs is LoadConfig reading the file in the given format, whatever its extension.
+// Dotenv files name environment variables, rather than flags; each supplies the flags bound to it by Flag.Env,
+// with the precedence of a config file, so that it yields to the environment. Other variables are ignored.
+func (c *Command) LoadConfigAs(path string, format ConfigFormat) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return c.loadConfigData(path, format, data)
+}
+
+// loadConfigData loads the contents of the config file at path, as LoadConfigAs does
+func (c *Command) loadConfigData(path string, format ConfigFormat, data []byte) error {
+	f, err := decodeConfig(path, format, data)
+	if err != nil {
+		return err
+	}
+	if formatOf(path, format) == ConfigDotenv {
+		f = c.envConfig(f)
+	}
+	base, profiles, err := c.validateConfig(f, Origin{Source: SourceConfig, Path: path, Untrusted: c.untrustedConfig(path)})
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	c.loadConfig(base, "", path)
+	for name, profile := range profiles {
+		c.loadConfig(profile, name, path)
+		if c.profiled == nil {
+			c.profiled = make(map[string]map[string]string)
+		}
+		if c.profiled[name] == nil {
+			c.profiled[name] = make(map[string]string) // so that empty profiles may be selected
+		}
+	}
+	c.configPaths = append(c.configPaths, path)
+	return nil
+}
+
+// loadConfig records the values of a checked config section, or of the named profile's section,
+// for the command and its children, along with the path of the file they were loaded from
+func (c *Command) loadConfig(section map[string]any, profile, path string) {
+	for name, v := range section {
+		if sub, ok := v.(map[string]any); ok {
+			c.child(name).loadConfig(sub, profile, path)
+			continue
+		}
+		if c.configFrom == nil {
+			c.configFrom = make(map[string]map[string]string)
+		}
+		if c.configFrom[profile] == nil {
+			c.configFrom[profile] = make(map[string]string)
+		}
+		c.configFrom[profile][name] = path
+		if profile != "" {
+			if c.profiled == nil {
+				c.profiled = make(map[string]map[string]string)
+			}
+			if c.profiled[profile] == nil {
+				c.profiled[profile] = make(map[string]string)
+			}
+			c.profiled[profile][name] = configText(v)
+			continue
+		}
+		if c.configured == nil {
+			c.configured = make(map[string]string)
+		}
+		c.configured[name] = configText(v)
+	}
+}
+
+// applyConfig sets the command's flags that were not set by the command line or the environment from
+// the values loaded by LoadConfig, preferring those of the selected profile, which is itself resolved first
+func (c *Command) applyConfig() error {
+	var errs []error
+	flags := c.ordinalFlags()
+	if c.profileFlag != nil {
+		flags = append([]*Flag{c.profileFlag}, slices.DeleteFunc(flags, func(f *Flag) bool { return f == c.profileFlag })...)
+	}
+	for _, flag := range flags {
+		value, ok := c.configured[flag.Name]
+		profile := ""
+		if v, selected := c.profiled[c.activeProfile()][flag.Name]; selected && flag != c.profileFlag {
+			value, ok, profile = v, true, c.activeProfile()
+		}
+		if _, set := c.actual[flag.Name]; set || !ok {
+			continue
+		}
+		path := c.configFrom[profile][flag.Name]
+		if err := c.setFrom(flag, value, Origin{Source: SourceConfig, Path: path, Untrusted: c.untrustedConfig(path)}); err != nil {
+			errs = append(errs, fmt.Errorf("invalid value for flag %s from config: %s: %w", flag.Name, flag.redact(value), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// untrustedConfig reports whether the config file at path was loaded, by the command or an ancestor, without being trusted
+func (c *Command) untrustedConfig(path string) bool {
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		if cmd.untrusted[path] {
+			return true
+		}
+	}
+	return false
+}
+
+// envConfig turns a config keyed by environment variable into one keyed by the flags bound to the variables
+func (c *Command) envConfig(vars configFile) configFile {
+	f := make(configFile)
+	keys, flags := c.configKeys()
+	for i, key := range keys {
+		if v, ok := vars[flags[i].env]; ok && flags[i].env != "" {
+			f.set(key, configText(v))
+		}
+	}
+	return f
+}
+
+// ConfigCommand adds a child, named ConfigName, for managing the config file at path, with children
+//
+//	get KEY          print the value of a flag, from the file or else its default
+//	set KEY VALUE    check the value against the flag and store it in the file
+//	list             print the value of every flag, and where it comes from
+//	edit             open the file in $VISUAL or $EDITOR, then check it
+//	list-profiles    print the names of the profiles in the file
+//
+// Keys are flag names prefixed by the names of the children defining them and ConfigSep, such as "serve.addr".
+// The file is read and written in the format LoadConfig implies from its extension.
+func (c *Command) ConfigCommand(path string) *Command {
+	config := c.NewChild(ConfigName, "manage the configuration file")
+	root := c
+
+	get := config.NewChild("get", "print a configured value")
+	get.Main = func(self *Command) error {
+		if self.NArg() != 1 {
+			return fmt.Errorf("usage: %s get KEY", config.name)
+		}
+		key := self.Arg(0)
+		flag, err := root.configFlag(key)
+		if err != nil {
+			return err
+		}
+		f, err := readConfig(path)
+		if err != nil {
+			return err
+		}
+		value, ok := f.lookup(key)
+		if !ok {
+			value = flag.DefValue
+		}
+		_, err = fmt.Fprintln(self.Stdout(), flag.redact(value))
+		return err
+	}
+
+	set := config.NewChild("set", "store a configured value")
+	set.Main = func(self *Command) error {
+		if self.NArg() != 2 {
+			return fmt.Errorf("usage: %s set KEY VALUE", config.name)
+		}
+		key, value := self.Arg(0), self.Arg(1)
+		flag, err := root.configFlag(key)
+		if err != nil {
+			return err
+		}
+		if err := cloneValue(flag.Value).Set(value); err != nil {
+			return fmt.Errorf("invalid value for %s: %s: %w", key, flag.redact(value), err)
+		}
+		f, err := readConfig(path)
+		if err != nil {
+			return err
+		}
+		f.set(key, value)
+		return f.write(path)
+	}
+
+	list := config.NewChild("list", "print every configurable value")
+	list.Main = func(self *Command) error {
+		f, err := readConfig(path)
+		if err != nil {
+			return err
+		}
+		keys, flags := root.configKeys()
+		for i, key := range keys {
+			value, source := flags[i].DefValue, "default"
+			if v, ok := f.lookup(key); ok {
+				value, source = v, "config"
+			}
+			if _, err := fmt.Fprintf(self.Stdout(), "%s=%s\t(%s)\n", key, flags[i].redact(value), source); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	edit := config.NewChild("edit", "edit the configuration file")
+	edit.Main = func(self *Command) error {
+		editor, ok := self.lookupEnv("VISUAL")
+		if !ok || editor == "" {
+			if editor, ok = self.lookupEnv("EDITOR"); !ok || editor == "" {
+				editor = "vi"
+			}
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		words, err := SplitLine(editor)
+		if err != nil || len(words) == 0 {
+			return fmt.Errorf("invalid editor %q: %v", editor, err)
+		}
+		cmd := exec.Command(words[0], append(words[1:], path)...)
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = self.Input(), self.Stdout(), self.Output()
+		if err := cmd.Run(); err != nil {
+			return err
+		}
+		f, err := readConfig(path)
+		if err != nil {
+			return err
+		}
+		_, _, err = root.validateConfig(f, Origin{Source: SourceConfig, Path: path})
+		return err
+	}
+
+	profiles := config.NewChild("list-profiles", "print the names of the configured profiles")
+	profiles.Main = func(self *Command) error {
+		f, err := readConfig(path)
+		if err != nil {
+			return err
+		}
+		_, sections, err := root.splitProfiles(f)
+		if err != nil {
+			return err
+		}
+		names := make([]string, 0, len(sections))
+		for name := range sections {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if _, err := fmt.Fprintln(self.Stdout(), name); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return config
+}
@@ -0,0 +1,184 @@
+package mandy
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestConfigCommand(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	var (
+		verbose bool
+		port    int
+		token   string
+	)
+	c := NewCommand("tool", ContinueOnError)
+	c.SetOutput(io.Discard)
+	var out bytes.Buffer
+	c.SetStdout(&out)
+	c.Bool(&verbose, "verbose", false, "", false)
+	serve := c.NewChild("serve", "")
+	serve.Int(&port, "port", 8080, "", false)
+	serve.Secret(&token, "token", "", "", false)
+	c.ConfigCommand(path)
+
+	run := func(args ...string) (string, error) {
+		out.Reset()
+		err := c.Clone().Execute(args...)
+		return out.String(), err
+	}
+	if got, err := run("config", "get", "serve.port"); err != nil || got != "8080\n" {
+		t.Errorf("get before set = %q, %v, want the default", got, err)
+	}
+	if _, err := run("config", "set", "serve.port", "80"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := run("config", "set", "serve.token", "hunter2"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := run("config", "get", "serve.port"); err != nil || got != "80\n" {
+		t.Errorf("get after set = %q, %v, want 80", got, err)
+	}
+	if _, err := run("config", "set", "serve.port", "eighty"); !errors.Is(err, errParse) {
+		t.Errorf("set with an invalid value = %v, want errParse", err)
+	}
+	if _, err := run("config", "set", "serve.host", "x"); !errors.Is(err, ErrConfigKey) {
+		t.Errorf("set with an unknown key = %v, want ErrConfigKey", err)
+	}
+	want := "verbose=false\t(default)\nserve.port=80\t(config)\nserve.token=" + Redacted + "\t(config)\n"
+	if got, err := run("config", "list"); err != nil || got != want {
+		t.Errorf("list = %q, %v, want %q", got, err, want)
+	}
+	if info, err := os.Stat(path); err != nil || runtime.GOOS != "windows" && info.Mode().Perm() != 0o600 {
+		t.Errorf("config set wrote a file with mode %v, %v, want 0600", info.Mode(), err)
+	}
+
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("no shell to stand in for an editor")
+	}
+	c.SetEnviron(MapEnviron(map[string]string{"EDITOR": `sh -c 'echo "{\"bogus\": 1}" > "$1"' sh`}))
+	if _, err := run("config", "edit"); !errors.Is(err, ErrConfigKey) || !strings.Contains(err.Error(), "bogus") {
+		t.Errorf("edit introducing an unknown key = %v, want ErrConfigKey", err)
+	}
+}
+
+func TestConfigWrite(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, []byte("port: 80\n"), 0o640); err != nil {
+		t.Fatal(err)
+	}
+	f, err := readConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.set("serve.token", "hunter2")
+	if err := f.write(path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "port: \"80\"\nserve:\n  token: \"hunter2\"\n"; string(data) != want {
+		t.Errorf("wrote %q, want YAML %q", data, want)
+	}
+	if info, err := os.Stat(path); err != nil || runtime.GOOS != "windows" && info.Mode().Perm() != 0o640 {
+		t.Errorf("rewritten file has mode %v, %v, want 0640 kept", info.Mode(), err)
+	}
+	if entries, err := os.ReadDir(dir); err != nil || len(entries) != 1 {
+		t.Errorf("directory holds %v, %v, want the config alone", entries, err)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.toml")
+	if err := os.WriteFile(path, []byte("addr = \":9000\"\nlevel = \"3\"\nname = \"config\"\n\n[serve]\nport = \"81\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var (
+		addr, name  string
+		level, port int
+	)
+	c := NewCommand("tool", ContinueOnError)
+	c.SetEnviron(MapEnviron(map[string]string{"TOOL_ADDR": ":7000", "TOOL_NAME": "env"}))
+	c.String(&addr, "addr", ":8080", "", false).Env("TOOL_ADDR")
+	c.String(&name, "name", "default", "", false).Env("TOOL_NAME")
+	c.Int(&level, "level", 1, "", false).Once()
+	serve := c.NewChild("serve", "")
+	serve.Int(&port, "port", 80, "", false)
+	if err := c.LoadConfig(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Parse("--name", "cli", "serve"); err != nil {
+		t.Fatal(err)
+	}
+	if name != "cli" || addr != ":7000" || level != 3 || port != 81 {
+		t.Errorf("name, addr, level, port = %q, %q, %d, %d, want cli from the command line, :7000 from the environment, and 3 and 81 from the config", name, addr, level, port)
+	}
+
+	bad := filepath.Join(dir, "bad.yaml")
+	if err := os.WriteFile(bad, []byte("level: high\nserve:\n  host: x\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.LoadConfig(bad); !errors.Is(err, errParse) || !errors.Is(err, ErrConfigKey) {
+		t.Errorf("LoadConfig(%s) = %v, want errParse and ErrConfigKey", bad, err)
+	}
+	if c.configured["level"] != "3" {
+		t.Errorf("a failed LoadConfig changed the loaded values: %v", c.configured)
+	}
+}
+
+func TestLoadConfigAs(t *testing.T) {
+	dir := t.TempDir()
+	dotenv := filepath.Join(dir, ".env")
+	if err := os.WriteFile(dotenv, []byte("TOOL_ADDR=:9000\nTOOL_PORT=81\nUNRELATED=x\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ini := filepath.Join(dir, "toolrc")
+	if err := os.WriteFile(ini, []byte("name = ini\n\n[serve]\nport = 82\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var (
+		addr, name string
+		port       int
+	)
+	c := NewCommand("tool", ContinueOnError)
+	c.SetEnviron(MapEnviron(map[string]string{"TOOL_PORT": "83"}))
+	c.String(&addr, "addr", ":8080", "", false).Env("TOOL_ADDR")
+	serve := c.NewChild("serve", "")
+	serve.Int(&port, "port", 80, "", false).Env("TOOL_PORT")
+	if err := c.LoadConfig(dotenv); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Parse("serve"); err != nil {
+		t.Fatal(err)
+	}
+	if addr != ":9000" || port != 83 {
+		t.Errorf("addr, port = %q, %d, want :9000 from the dotenv file and 83 from the environment", addr, port)
+	}
+
+	c = NewCommand("tool", ContinueOnError)
+	c.String(&name, "name", "default", "", false)
+	c.NewChild("serve", "").Int(&port, "port", 80, "", false)
+	if err := c.LoadConfigAs(ini, ConfigINI); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Parse("serve"); err != nil {
+		t.Fatal(err)
+	}
+	if name != "ini" || port != 82 {
+		t.Errorf("name, port = %q, %d, want ini and 82 from the INI file", name, port)
+	}
+	if err := c.LoadConfigAs(ini, ConfigFormat("xml")); err == nil {
+		t.Error("LoadConfigAs with an unknown format succeeded")
+	}
+}
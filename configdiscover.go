@@ -0,0 +1,83 @@
+package mandy
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+)
+
+// systemConfigDir holds the system-wide config directories searched by DiscoverConfig
+var systemConfigDir = "/etc"
+
+// discoverExtensions are tried, in order, when a config file searched for by DiscoverConfig
+// doesn't exist under its bare name
+var discoverExtensions = []string{".toml", ".yaml", ".yml", ".json", ".ini"}
+
+// ConfigSearchPaths returns the config files DiscoverConfig searches for, from the most specific to the least:
+// ./.<name>rc, $XDG_CONFIG_HOME/<name>/config (~/.config/<name>/config on unix-likes if it's unset, or in
+// the user config directory elsewhere), and /etc/<name>/config on unix-likes, where name is the command's.
+func (c *Command) ConfigSearchPaths() []string {
+	paths := []string{"." + c.name + "rc"}
+	if dir, ok := c.lookupEnv("XDG_CONFIG_HOME"); ok && dir != "" {
+		paths = append(paths, filepath.Join(dir, c.name, "config"))
+	} else if runtime.GOOS != "windows" && runtime.GOOS != "darwin" && runtime.GOOS != "plan9" {
+		if home, ok := c.lookupEnv("HOME"); ok && home != "" {
+			paths = append(paths, filepath.Join(home, ".config", c.name, "config"))
+		}
+	} else if dir, err := os.UserConfigDir(); err == nil {
+		paths = append(paths, filepath.Join(dir, c.name, "config"))
+	}
+	if runtime.GOOS != "windows" && runtime.GOOS != "plan9" {
+		paths = append(paths, filepath.Join(systemConfigDir, c.name, "config"))
+	}
+	return paths
+}
+
+// DiscoverConfig loads, by LoadConfig, each of the ConfigSearchPaths that exists, from the least specific
+// to the most, so that project-local values override the user's, which override the system's,
+// then the EnvFile, if it is set and exists. A file may carry an extension that names its format,
+// such as .myclirc.yaml or config.toml, if there is none under the bare name, which is read as JSON.
+// The project-local file and the EnvFile are subject to RequireTrust, and are loaded as untrusted
+// when it wasn't called and they haven't been trusted. The first error ends the search.
+func (c *Command) DiscoverConfig() error {
+	paths := c.ConfigSearchPaths()
+	for i, path := range slices.Backward(paths) {
+		if path = existingConfig(path); path == "" {
+			continue
+		}
+		load := c.LoadConfig
+		if i == 0 {
+			load = func(path string) error { return c.loadTrusted(path, ConfigAuto) }
+		}
+		if err := load(path); err != nil {
+			return err
+		}
+	}
+	if c.EnvFile != "" {
+		return c.loadTrusted(c.EnvFile, ConfigDotenv)
+	}
+	return nil
+}
+
+// existingConfig returns path, or the first of its variants with discoverExtensions that exists,
+// or the empty string if there are none
+func existingConfig(path string) string {
+	for _, ext := range append([]string{""}, discoverExtensions...) {
+		if info, err := os.Stat(path + ext); err == nil && !info.IsDir() {
+			return path + ext
+		} else if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return path + ext // let LoadConfig report it
+		}
+	}
+	return ""
+}
+
+// ConfigPaths returns the config files loaded into the command, by LoadConfig or DiscoverConfig,
+// in the order they were loaded; values from later files override those from earlier ones.
+// Files that didn't exist are omitted.
+func (c *Command) ConfigPaths() []string {
+	return slices.Clone(c.configPaths)
+}
@@ -0,0 +1,63 @@
+package mandy
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDiscoverConfig(t *testing.T) {
+	root := t.TempDir()
+	project, user, system := filepath.Join(root, "project"), filepath.Join(root, "user"), filepath.Join(root, "etc")
+	files := map[string]string{
+		filepath.Join(project, ".toolrc.toml"):       "name = \"project\"\n",
+		filepath.Join(user, "tool", "config"):        `{"name": "user", "level": "2"}`,
+		filepath.Join(system, "tool", "config.yaml"): "name: system\nlevel: 1\naddr: :9000\n",
+	}
+	for path, data := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	start, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(project); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(start)
+	defer func(dir string) { systemConfigDir = dir }(systemConfigDir)
+	systemConfigDir = system
+
+	var (
+		addr, name string
+		level      int
+	)
+	c := NewCommand("tool", ContinueOnError)
+	c.SetEnviron(MapEnviron(map[string]string{"XDG_CONFIG_HOME": user}))
+	c.String(&addr, "addr", ":8080", "", false)
+	c.String(&name, "name", "default", "", false)
+	c.Int(&level, "level", 0, "", false)
+	want := []string{".toolrc", filepath.Join(user, "tool", "config"), filepath.Join(system, "tool", "config")}
+	if paths := c.ConfigSearchPaths(); !reflect.DeepEqual(paths, want) {
+		t.Errorf("ConfigSearchPaths() = %q, want %q", paths, want)
+	}
+	if err := c.DiscoverConfig(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Parse("--"); err != nil {
+		t.Fatal(err)
+	}
+	if addr != ":9000" || level != 2 || name != "project" {
+		t.Errorf("addr, level, name = %q, %d, %q, want :9000 from the system, 2 from the user, and project from the project", addr, level, name)
+	}
+	want = []string{filepath.Join(system, "tool", "config.yaml"), filepath.Join(user, "tool", "config"), ".toolrc.toml"}
+	if paths := c.ConfigPaths(); !reflect.DeepEqual(paths, want) {
+		t.Errorf("ConfigPaths() = %q, want %q", paths, want)
+	}
+}
@@ -0,0 +1,89 @@
+package mandy
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ProfileSection is the key under which config files hold profiles, such as [profile.staging] in TOML
+const ProfileSection = "profile"
+
+// ProfileFlagName is the name of the flag defined by ConfigProfiles
+const ProfileFlagName = "profile"
+
+// ErrProfile is returned by Parse when the selected profile is in none of the loaded config files
+var ErrProfile = errors.New("mandy: no such profile")
+
+// ConfigProfiles defines a flag, named ProfileFlagName and bound to the environment variable envVar,
+// if it isn't empty, that selects one of the profiles held under the ProfileSection of the files LoadConfig
+// reads. A profile's values override the rest of the config's, for the command and its descendants,
+// but not the environment's or the command line's.
+// The profile may itself be selected by a config file holding no profiles, as one holding them can't.
+func (c *Command) ConfigProfiles(envVar string) *Flag {
+	c.profileFlag = c.String(new(string), ProfileFlagName, "", "config `profile` to use", false)
+	if envVar != "" {
+		c.profileFlag.Env(envVar)
+	}
+	return c.profileFlag
+}
+
+// splitProfiles separates a config's profiles from the rest of it; a ProfileSection is taken
+// for the flags of a child instead if the command has a child by that name
+func (c *Command) splitProfiles(f configFile) (base configFile, profiles map[string]configFile, err error) {
+	section, ok := f[ProfileSection].(map[string]any)
+	if !ok || c.child(ProfileSection) != nil {
+		return f, nil, nil
+	}
+	base = make(configFile, len(f))
+	for key, v := range f {
+		if key != ProfileSection {
+			base[key] = v
+		}
+	}
+	profiles = make(map[string]configFile, len(section))
+	for name, v := range section {
+		profile, ok := v.(map[string]any)
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: profile %s should be a section", ErrConfigKey, name)
+		}
+		profiles[name] = profile
+	}
+	return base, profiles, nil
+}
+
+// activeProfile returns the profile selected for the command by its own, or its nearest ancestor's,
+// ConfigProfiles flag
+func (c *Command) activeProfile() string {
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		if cmd.profileFlag != nil {
+			return cmd.profileFlag.Value.String()
+		}
+	}
+	return ""
+}
+
+// checkProfile returns an error, wrapping ErrProfile, if the command's ConfigProfiles flag selects a profile
+// that LoadConfig didn't find
+func (c *Command) checkProfile() error {
+	if c.profileFlag == nil {
+		return nil
+	}
+	name := c.profileFlag.Value.String()
+	if name == "" || c.hasProfile(name) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrProfile, name)
+}
+
+// hasProfile reports whether a loaded config gave the named profile values for the command or its descendants
+func (c *Command) hasProfile(name string) bool {
+	if _, ok := c.profiled[name]; ok {
+		return true
+	}
+	for _, child := range c.children {
+		if child.hasProfile(name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package mandy
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const profileConfig = `
+addr = ":8080"
+
+[serve]
+port = "80"
+
+[profile.dev]
+
+[profile.staging]
+addr = "staging:8080"
+
+[profile.staging.serve]
+port = "8443"
+`
+
+func TestConfigProfiles(t *testing.T) {
+	dir := t.TempDir()
+	path, local := filepath.Join(dir, "config.toml"), filepath.Join(dir, "local.json")
+	if err := os.WriteFile(path, []byte(profileConfig), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(local, []byte(`{"profile": "staging"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		env   map[string]string
+		args  []string
+		local bool // whether to load the config selecting staging
+		addr  string
+		port  int
+		err   error
+	}{
+		{nil, []string{"serve"}, false, ":8080", 80, nil},
+		{nil, []string{"--profile", "dev", "serve"}, false, ":8080", 80, nil},
+		{nil, []string{"--profile", "staging", "serve"}, false, "staging:8080", 8443, nil},
+		{map[string]string{"TOOL_PROFILE": "staging"}, []string{"serve"}, false, "staging:8080", 8443, nil},
+		{map[string]string{"TOOL_PROFILE": "staging"}, []string{"--addr", "cli", "serve", "--port", "1"}, false, "cli", 1, nil},
+		{nil, []string{"serve"}, true, "staging:8080", 8443, nil},
+		{nil, []string{"--profile", "dev", "serve"}, true, ":8080", 80, nil},
+		{nil, []string{"--profile", "prod", "serve"}, false, "", 0, ErrProfile},
+	} {
+		var addr string
+		var port int
+		c := NewCommand("tool", ContinueOnError)
+		c.SetOutput(io.Discard)
+		c.SetEnviron(MapEnviron(test.env))
+		c.ConfigProfiles("TOOL_PROFILE")
+		c.String(&addr, "addr", "", "", false)
+		c.NewChild("serve", "").Int(&port, "port", 0, "", false)
+		if err := c.LoadConfig(path); err != nil {
+			t.Fatal(err)
+		}
+		if test.local {
+			if err := c.LoadConfig(local); err != nil {
+				t.Fatal(err)
+			}
+		}
+		err := c.Parse(test.args...)
+		switch {
+		case test.err != nil:
+			if !errors.Is(err, test.err) {
+				t.Errorf("env %v, Parse(%q) = %v, want %v", test.env, test.args, err, test.err)
+			}
+		case err != nil:
+			t.Errorf("env %v, Parse(%q): %v", test.env, test.args, err)
+		case addr != test.addr || port != test.port:
+			t.Errorf("env %v, Parse(%q): addr, port = %q, %d, want %q, %d", test.env, test.args, addr, port, test.addr, test.port)
+		}
+	}
+}
+
+func TestListProfiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte(profileConfig), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c := NewCommand("tool", ContinueOnError)
+	var out bytes.Buffer
+	c.SetStdout(&out)
+	c.ConfigProfiles("")
+	c.String(new(string), "addr", "", "", false)
+	c.NewChild("serve", "").Int(new(int), "port", 0, "", false)
+	c.ConfigCommand(path)
+	if err := c.Execute("config", "list-profiles"); err != nil || out.String() != "dev\nstaging\n" {
+		t.Errorf("list-profiles = %q, %v", out.String(), err)
+	}
+}
@@ -0,0 +1,44 @@
+package mandy
+
+import (
+	"strconv"
+)
+
+// -- count Value
+// an int incremented each time its flag appears without a value
+type countValue int
+
+func newCountValue(val int, p *int) *countValue {
+	*p = val
+	return (*countValue)(p)
+}
+
+// Set increments the count for the bare "true" the parser supplies to boolean flags,
+// resets it for "false", and otherwise takes the value as an explicit count
+func (i *countValue) Set(s string) error {
+	switch s {
+	case "true":
+		*i++
+		return nil
+	case "false":
+		*i = 0
+		return nil
+	}
+	v, err := strconv.ParseInt(s, 0, strconv.IntSize)
+	if err != nil {
+		return numError(err)
+	}
+	*i = countValue(v)
+	return nil
+}
+
+func (i *countValue) Get() any       { return int(*i) }
+func (i *countValue) String() string { return strconv.Itoa(int(*i)) }
+func (i *countValue) IsBool() bool   { return true }
+
+// Count defines an int flag with specified name, default value, and usage string,
+// which counts its occurrences, so -v -v, -vv, and --verbose=2 each leave 2 in a verbose count.
+// The argument p points to an int variable in which to store the value of the flag.
+func (c *Command) Count(p *int, name string, value int, usage string, short bool) *Flag {
+	return c.Var(newCountValue(value, p), name, usage, short)
+}
@@ -0,0 +1,39 @@
+package mandy
+
+import (
+	"testing"
+)
+
+func TestCount(t *testing.T) {
+	for _, test := range []struct {
+		args []string
+		want int
+	}{
+		{[]string{"--"}, 0},
+		{[]string{"-v"}, 1},
+		{[]string{"-vvv"}, 3},
+		{[]string{"-v", "--verbose", "-vq"}, 3},
+		{[]string{"--verbose=5", "-v"}, 6},
+		{[]string{"-vv", "--verbose=false"}, 0},
+	} {
+		var verbose int
+		var quiet bool
+		c := NewCommand("test", ContinueOnError)
+		c.Count(&verbose, "verbose", 0, "verbosity", true)
+		c.Bool(&quiet, "quiet", false, "quiet", true)
+		if err := c.Parse(test.args...); err != nil {
+			t.Errorf("Parse(%q): %v", test.args, err)
+			continue
+		}
+		if verbose != test.want {
+			t.Errorf("Parse(%q): verbose = %d, want %d", test.args, verbose, test.want)
+		}
+	}
+
+	var verbose int
+	c := NewCommand("test", ContinueOnError)
+	c.Count(&verbose, "verbose", 0, "verbosity", true)
+	if err := c.Parse("--verbose=lots"); err == nil {
+		t.Error("Parse(--verbose=lots) succeeded")
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !windows
+
+package mandy
+
+import "errors"
+
+// credRead reports errors.ErrUnsupported; there is no Credential Manager on this platform
+func credRead(target string) (string, error) { return "", errors.ErrUnsupported }
+
+// credWrite reports errors.ErrUnsupported; there is no Credential Manager on this platform
+func credWrite(target, user, secret string) error { return errors.ErrUnsupported }
+
+// credDelete reports errors.ErrUnsupported; there is no Credential Manager on this platform
+func credDelete(target string) error { return errors.ErrUnsupported }
@@ -0,0 +1,146 @@
+package mandy
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+)
+
+// Database is a FlagGroup of database connection settings, conventionally mounted under the "db" prefix:
+// either --url, or --host, --port, --user, --password, and --name, along with --driver.
+// The password is a Secret flag, so it may be resolved from the environment, a command, or a keyring.
+type Database struct {
+	Driver   string // the database/sql driver; postgres, pgx, mysql, sqlite, and sqlite3 DSNs can be assembled
+	URL      string // a complete DSN, used in place of the other fields
+	Host     string
+	Port     uint16 // the driver's default port if zero
+	User     string
+	Password string
+	Name     string // the database's name, or, for sqlite, its path
+}
+
+// Register defines the group's flags on c
+func (d *Database) Register(c *Command, prefix string) {
+	c.String(&d.Driver, Prefixed(prefix, "driver"), d.Driver, "database/sql `driver` to connect with", false)
+	c.Var(newDSNValue(d.URL, &d.URL), Prefixed(prefix, "url"), "connection `url`, instead of the other database flags", false)
+	c.String(&d.Host, Prefixed(prefix, "host"), d.Host, "database server `host`", false)
+	c.Var(newPortValue(d.Port, &d.Port), Prefixed(prefix, "port"), "database server `port`", false)
+	c.String(&d.User, Prefixed(prefix, "user"), d.User, "database `user`", false)
+	c.Secret(&d.Password, Prefixed(prefix, "password"), d.Password, "database `password`", false)
+	c.String(&d.Name, Prefixed(prefix, "name"), d.Name, "database `name`", false)
+}
+
+// defaultPorts maps the drivers whose DSNs can be assembled to their servers' default ports
+var defaultPorts = map[string]uint16{"postgres": 5432, "pgx": 5432, "mysql": 3306}
+
+// DSN assembles the data source name for the group's driver
+func (d *Database) DSN() (string, error) {
+	if d.URL != "" {
+		if d.Host != "" || d.Port != 0 || d.User != "" || d.Password != "" || d.Name != "" {
+			return "", fmt.Errorf("%w: a database url cannot be combined with its parts", errParse)
+		}
+		return d.URL, nil
+	}
+	port := d.Port
+	if port == 0 {
+		port = defaultPorts[d.Driver]
+	}
+	host := net.JoinHostPort(d.Host, strconv.Itoa(int(port)))
+	switch d.Driver {
+	case "postgres", "pgx":
+		if d.Host == "" {
+			return "", fmt.Errorf("%w: a %s database needs a host", errParse, d.Driver)
+		}
+		u := url.URL{Scheme: "postgres", Host: host, Path: "/" + d.Name}
+		if d.User != "" {
+			u.User = url.UserPassword(d.User, d.Password)
+		}
+		return u.String(), nil
+	case "mysql":
+		if d.Host == "" {
+			return "", fmt.Errorf("%w: a %s database needs a host", errParse, d.Driver)
+		}
+		dsn := ""
+		if d.User != "" {
+			dsn = d.User
+			if d.Password != "" {
+				dsn += ":" + d.Password
+			}
+			dsn += "@"
+		}
+		return dsn + "tcp(" + host + ")/" + d.Name, nil
+	case "sqlite", "sqlite3":
+		if d.Name == "" {
+			return "", fmt.Errorf("%w: a %s database needs a name", errParse, d.Driver)
+		}
+		return d.Name, nil
+	case "":
+		return "", fmt.Errorf("%w: no database driver was given", errParse)
+	}
+	return "", fmt.Errorf("%w: cannot assemble a DSN for the %s driver, give a url instead", errParse, d.Driver)
+}
+
+// Open opens the database with the group's driver and DSN, and checks that it can be reached.
+// The driver must have been registered with database/sql, usually by importing its package.
+func (d *Database) Open(ctx context.Context) (*sql.DB, error) {
+	dsn, err := d.DSN()
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open(d.Driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// -- DSN Value
+// holds a url with a scheme, which is redacted in usage messages and dumps since it may embed a password
+type dsnValue string
+
+func newDSNValue(val string, p *string) *dsnValue {
+	*p = val
+	return (*dsnValue)(p)
+}
+
+func (v *dsnValue) Set(s string) error {
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme == "" {
+		return fmt.Errorf("%w: not a url with a scheme", errParse) // s isn't quoted, since it may hold a password
+	}
+	*v = dsnValue(s)
+	return nil
+}
+
+func (v *dsnValue) Get() any       { return string(*v) }
+func (v *dsnValue) String() string { return string(*v) }
+func (v *dsnValue) IsBool() bool   { return false }
+func (v *dsnValue) redacted()      {}
+
+// -- port Value
+type portValue uint16
+
+func newPortValue(val uint16, p *uint16) *portValue {
+	*p = val
+	return (*portValue)(p)
+}
+
+func (v *portValue) Set(s string) error {
+	n, err := strconv.ParseUint(s, 10, 16)
+	if err != nil {
+		return numError(err)
+	}
+	*v = portValue(n)
+	return nil
+}
+
+func (v *portValue) Get() any       { return uint16(*v) }
+func (v *portValue) String() string { return strconv.Itoa(int(*v)) }
+func (v *portValue) IsBool() bool   { return false }
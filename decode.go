@@ -0,0 +1,612 @@
+package mandy
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ConfigFormat names a format of config files, for Command.LoadConfigAs
+type ConfigFormat string
+
+const (
+	ConfigAuto   ConfigFormat = ""     // the format implied by the file's extension, or JSON
+	ConfigJSON   ConfigFormat = "json" // .json
+	ConfigYAML   ConfigFormat = "yaml" // .yaml and .yml
+	ConfigTOML   ConfigFormat = "toml" // .toml
+	ConfigINI    ConfigFormat = "ini"  // .ini, with sections named after children
+	ConfigDotenv ConfigFormat = "env"  // .env, of variables supplying flags bound by Flag.Env
+)
+
+// configExtensions maps the extensions of config files to their formats
+var configExtensions = map[string]ConfigFormat{
+	".json": ConfigJSON,
+	".yaml": ConfigYAML,
+	".yml":  ConfigYAML,
+	".toml": ConfigTOML,
+	".ini":  ConfigINI,
+	".env":  ConfigDotenv,
+}
+
+// configDecoders maps config formats to the functions decoding them
+var configDecoders = map[ConfigFormat]func(data []byte) (configFile, error){
+	ConfigJSON:   decodeJSON,
+	ConfigYAML:   decodeYAML,
+	ConfigTOML:   decodeTOML,
+	ConfigINI:    decodeINI,
+	ConfigDotenv: decodeDotenv,
+}
+
+// formatOf returns the format of the config file at path, given format, resolving ConfigAuto by its extension
+func formatOf(path string, format ConfigFormat) ConfigFormat {
+	if format != ConfigAuto {
+		return format
+	}
+	if f, ok := configExtensions[strings.ToLower(filepath.Ext(path))]; ok {
+		return f
+	}
+	return ConfigJSON
+}
+
+// decoderFor returns the function decoding config files named like path
+func decoderFor(path string) func(data []byte) (configFile, error) {
+	return configDecoders[formatOf(path, ConfigAuto)]
+}
+
+func decodeJSON(data []byte) (configFile, error) {
+	f := make(configFile)
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// splitOutside splits s on the separator wherever it is outside quotes and brackets
+func splitOutside(s string, sep byte) (parts []string) {
+	var quote byte
+	depth, start := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		case c == sep && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// stripComment removes a comment, begun by one of the marks outside quotes, from the line;
+// if spaced is set, as in YAML, the mark must begin the line or follow whitespace
+func stripComment(line, marks string, spaced bool) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case strings.IndexByte(marks, c) >= 0 && (!spaced || i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// unquote removes the quotes from a double quoted string, with its escapes, or a single quoted one
+func unquote(s string, doubledSingles bool) (string, error) {
+	switch {
+	case len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"':
+		return strconv.Unquote(s)
+	case len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'':
+		s = s[1 : len(s)-1]
+		if doubledSingles {
+			s = strings.ReplaceAll(s, "''", "'")
+		}
+		return s, nil
+	}
+	return s, nil
+}
+
+// -- YAML
+// a subset, of block mappings and lists of scalars, sufficient for flag values
+
+type yamlLine struct {
+	indent int
+	text   string
+	number int
+}
+
+type yamlParser struct {
+	lines []yamlLine
+	i     int
+}
+
+func decodeYAML(data []byte) (configFile, error) {
+	var p yamlParser
+	for n, line := range strings.Split(string(data), "\n") {
+		text := strings.TrimRight(stripComment(line, "#", true), " \t\r")
+		trimmed := strings.TrimLeft(text, " ")
+		if trimmed == "" || trimmed == "---" || trimmed == "..." {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "\t") {
+			return nil, fmt.Errorf("line %d: tabs may not indent YAML", n+1)
+		}
+		p.lines = append(p.lines, yamlLine{len(text) - len(trimmed), trimmed, n + 1})
+	}
+	if len(p.lines) == 0 {
+		return make(configFile), nil
+	}
+	m, err := p.mapping(p.lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.i < len(p.lines) {
+		return nil, fmt.Errorf("line %d: unexpected indentation", p.lines[p.i].number)
+	}
+	return configFile(m), nil
+}
+
+// mapping reads the keys and values indented by indent
+func (p *yamlParser) mapping(indent int) (map[string]any, error) {
+	m := make(map[string]any)
+	for p.i < len(p.lines) && p.lines[p.i].indent == indent {
+		line := p.lines[p.i]
+		key, rest, ok := yamlPair(line.text)
+		if strings.HasPrefix(line.text, "-") || !ok {
+			return nil, fmt.Errorf("line %d: expected a key", line.number)
+		}
+		key, err := unquote(key, true)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line.number, err)
+		}
+		p.i++
+		var v any
+		switch {
+		case rest != "":
+			if v, err = yamlScalar(rest); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line.number, err)
+			}
+		case p.i < len(p.lines) && p.lines[p.i].indent > indent:
+			if strings.HasPrefix(p.lines[p.i].text, "-") {
+				v, err = p.list(p.lines[p.i].indent)
+			} else {
+				v, err = p.mapping(p.lines[p.i].indent)
+			}
+		case p.i < len(p.lines) && p.lines[p.i].indent == indent && strings.HasPrefix(p.lines[p.i].text, "-"):
+			v, err = p.list(indent)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if _, dup := m[key]; dup {
+			return nil, fmt.Errorf("line %d: duplicate key %q", line.number, key)
+		}
+		if v != nil {
+			m[key] = v
+		}
+	}
+	return m, nil
+}
+
+// list reads the items indented by indent
+func (p *yamlParser) list(indent int) ([]any, error) {
+	var items []any
+	for p.i < len(p.lines) && p.lines[p.i].indent == indent && strings.HasPrefix(p.lines[p.i].text, "-") {
+		line := p.lines[p.i]
+		item := strings.TrimSpace(line.text[1:])
+		if _, _, pair := yamlPair(item); item == "" || pair {
+			return nil, fmt.Errorf("line %d: list items must be scalars", line.number)
+		}
+		v, err := yamlScalar(item)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line.number, err)
+		}
+		items = append(items, v)
+		p.i++
+	}
+	return items, nil
+}
+
+// yamlPair splits "key: value", at the first colon outside quotes and brackets that ends the text or precedes a space
+func yamlPair(text string) (key, value string, ok bool) {
+	parts := splitOutside(text, ':')
+	for i := 1; i < len(parts); i++ {
+		if parts[i] == "" && i == len(parts)-1 || strings.HasPrefix(parts[i], " ") {
+			key = strings.TrimSpace(strings.Join(parts[:i], ":"))
+			return key, strings.TrimSpace(strings.Join(parts[i:], ":")), key != ""
+		}
+	}
+	return "", "", false
+}
+
+// yamlScalar decodes a quoted or plain scalar, or a flow sequence of them; null values are nil
+func yamlScalar(s string) (any, error) {
+	switch {
+	case s == "~" || s == "null":
+		return nil, nil
+	case s[0] == '|' || s[0] == '>' || s[0] == '{' || s[0] == '&' || s[0] == '*':
+		return nil, fmt.Errorf("%w: unsupported YAML value %q", errParse, s)
+	case s[0] == '[':
+		if s[len(s)-1] != ']' {
+			return nil, fmt.Errorf("%w: unterminated sequence %q", errParse, s)
+		}
+		var items []any
+		for _, part := range splitOutside(s[1:len(s)-1], ',') {
+			if part = strings.TrimSpace(part); part == "" {
+				continue
+			}
+			v, err := yamlScalar(part)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, v)
+		}
+		return items, nil
+	}
+	return unquote(s, true)
+}
+
+// encodeYAML renders the config as block YAML, with values quoted as strings
+func encodeYAML(f configFile) []byte {
+	var b strings.Builder
+	var write func(indent string, m map[string]any)
+	write = func(indent string, m map[string]any) {
+		keys := make([]string, 0, len(m))
+		for key := range m {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			name := yamlQuoteKey(key)
+			switch v := m[key].(type) {
+			case map[string]any:
+				fmt.Fprintf(&b, "%s%s:\n", indent, name)
+				write(indent+"  ", v)
+			case []any:
+				if len(v) == 0 {
+					fmt.Fprintf(&b, "%s%s: []\n", indent, name)
+					continue
+				}
+				fmt.Fprintf(&b, "%s%s:\n", indent, name)
+				for _, item := range v {
+					fmt.Fprintf(&b, "%s  - %s\n", indent, strconv.Quote(configText(item)))
+				}
+			default:
+				fmt.Fprintf(&b, "%s%s: %s\n", indent, name, strconv.Quote(configText(v)))
+			}
+		}
+	}
+	write("", f)
+	return []byte(b.String())
+}
+
+// yamlQuoteKey quotes keys that can't be plain
+func yamlQuoteKey(key string) string {
+	for _, r := range key {
+		if !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' || r == '-' || r == '_') {
+			return strconv.Quote(key)
+		}
+	}
+	if key == "" || key == "-" {
+		return strconv.Quote(key)
+	}
+	return key
+}
+
+// -- TOML
+// a subset, of tables, dotted keys, and arrays of strings, numbers, and booleans, sufficient for flag values
+
+func decodeTOML(data []byte) (configFile, error) {
+	root := make(configFile)
+	section := map[string]any(root)
+	lines := strings.Split(string(data), "\n")
+	for n := 0; n < len(lines); n++ {
+		number := n + 1
+		line := strings.TrimSpace(stripComment(lines[n], "#", false))
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "[["):
+			return nil, fmt.Errorf("line %d: arrays of tables are not supported", number)
+		case line[0] == '[':
+			if line[len(line)-1] != ']' {
+				return nil, fmt.Errorf("line %d: unterminated table header", number)
+			}
+			keys, err := tomlKey(line[1 : len(line)-1])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", number, err)
+			}
+			if section, err = tomlTable(root, keys); err != nil {
+				return nil, fmt.Errorf("line %d: %w", number, err)
+			}
+			continue
+		}
+		parts := splitOutside(line, '=')
+		if len(parts) < 2 {
+			return nil, fmt.Errorf("line %d: expected key = value", number)
+		}
+		value := strings.TrimSpace(strings.Join(parts[1:], "="))
+		for strings.Count(value, "[") > strings.Count(value, "]") && n+1 < len(lines) {
+			n++
+			value += " " + strings.TrimSpace(stripComment(lines[n], "#", false))
+		}
+		keys, err := tomlKey(parts[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", number, err)
+		}
+		table, err := tomlTable(section, keys[:len(keys)-1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", number, err)
+		}
+		last := keys[len(keys)-1]
+		if _, dup := table[last]; dup {
+			return nil, fmt.Errorf("line %d: duplicate key %q", number, last)
+		}
+		if table[last], err = tomlValue(value); err != nil {
+			return nil, fmt.Errorf("line %d: %w", number, err)
+		}
+	}
+	return root, nil
+}
+
+// tomlKey splits a possibly dotted, possibly quoted, key into its parts
+func tomlKey(s string) (keys []string, err error) {
+	for _, part := range splitOutside(s, '.') {
+		key, err := unquote(strings.TrimSpace(part), false)
+		if err != nil {
+			return nil, err
+		}
+		if key == "" {
+			return nil, fmt.Errorf("%w: empty key in %q", errParse, s)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// tomlTable returns the table reached by following keys from t, creating tables as needed
+func tomlTable(t map[string]any, keys []string) (map[string]any, error) {
+	for _, key := range keys {
+		v, ok := t[key]
+		if !ok {
+			v = make(map[string]any)
+			t[key] = v
+		}
+		if t, ok = v.(map[string]any); !ok {
+			return nil, fmt.Errorf("%w: %q is not a table", errParse, key)
+		}
+	}
+	return t, nil
+}
+
+// tomlValue decodes a string, array, or bare value; bare values, such as numbers, booleans, and dates, are kept as text
+func tomlValue(s string) (any, error) {
+	switch {
+	case s == "":
+		return nil, fmt.Errorf("%w: missing value", errParse)
+	case strings.HasPrefix(s, `"""`) || strings.HasPrefix(s, "'''") || s[0] == '{':
+		return nil, fmt.Errorf("%w: unsupported TOML value %s", errParse, s)
+	case s[0] == '[':
+		if s[len(s)-1] != ']' {
+			return nil, fmt.Errorf("%w: unterminated array %s", errParse, s)
+		}
+		items := []any{}
+		for _, part := range splitOutside(s[1:len(s)-1], ',') {
+			if part = strings.TrimSpace(part); part == "" {
+				continue
+			}
+			v, err := tomlValue(part)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, v)
+		}
+		return items, nil
+	case s[0] == '"' || s[0] == '\'':
+		if len(s) < 2 || s[len(s)-1] != s[0] {
+			return nil, fmt.Errorf("%w: unterminated string %s", errParse, s)
+		}
+		return unquote(s, false)
+	}
+	return strings.ReplaceAll(s, "_", ""), nil
+}
+
+// encodeTOML renders the config as TOML, with values quoted as strings
+func encodeTOML(f configFile) []byte {
+	var b strings.Builder
+	var write func(prefix string, t map[string]any)
+	write = func(prefix string, t map[string]any) {
+		keys := make([]string, 0, len(t))
+		for key := range t {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		var tables []string
+		for _, key := range keys {
+			if _, ok := t[key].(map[string]any); ok {
+				tables = append(tables, key)
+				continue
+			}
+			fmt.Fprintf(&b, "%s = %s\n", tomlQuoteKey(key), tomlText(t[key]))
+		}
+		for _, key := range tables {
+			name := prefix + tomlQuoteKey(key)
+			fmt.Fprintf(&b, "\n[%s]\n", name)
+			write(name+".", t[key].(map[string]any))
+		}
+	}
+	write("", f)
+	return []byte(strings.TrimLeft(b.String(), "\n"))
+}
+
+// tomlQuoteKey quotes keys that can't be bare
+func tomlQuoteKey(key string) string {
+	for _, r := range key {
+		if !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' || r == '-' || r == '_') {
+			return strconv.Quote(key)
+		}
+	}
+	return key
+}
+
+// tomlText renders a decoded value as a TOML string or array of them
+func tomlText(v any) string {
+	if items, ok := v.([]any); ok {
+		parts := make([]string, len(items))
+		for i, item := range items {
+			parts[i] = tomlText(item)
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	}
+	return strconv.Quote(configText(v))
+}
+
+// -- INI
+// sections, named after children and nested with ConfigSep, of "key = value" or "key: value" lines;
+// repeated keys accumulate into a list
+
+func decodeINI(data []byte) (configFile, error) {
+	root := make(configFile)
+	section := map[string]any(root)
+	for n, line := range strings.Split(string(data), "\n") {
+		number := n + 1
+		line = strings.TrimSpace(stripComment(line, "#;", true))
+		switch {
+		case line == "":
+			continue
+		case line[0] == '[':
+			if line[len(line)-1] != ']' {
+				return nil, fmt.Errorf("line %d: unterminated section header", number)
+			}
+			var err error
+			if section, err = tomlTable(root, strings.Split(strings.TrimSpace(line[1:len(line)-1]), ConfigSep)); err != nil {
+				return nil, fmt.Errorf("line %d: %w", number, err)
+			}
+			continue
+		}
+		i := strings.IndexAny(line, "=:")
+		if i <= 0 {
+			return nil, fmt.Errorf("line %d: expected key = value", number)
+		}
+		key := strings.TrimSpace(line[:i])
+		value, err := unquote(strings.TrimSpace(line[i+1:]), false)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", number, err)
+		}
+		switch prev := section[key].(type) {
+		case nil:
+			section[key] = value
+		case string:
+			section[key] = []any{prev, value}
+		case []any:
+			section[key] = append(prev, value)
+		default:
+			return nil, fmt.Errorf("line %d: %q is a section", number, key)
+		}
+	}
+	return root, nil
+}
+
+// encodeINI renders the config as INI, with lists as repeated keys
+func encodeINI(f configFile) []byte {
+	var b strings.Builder
+	var write func(name string, t map[string]any)
+	write = func(name string, t map[string]any) {
+		keys := make([]string, 0, len(t))
+		for key := range t {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		var sections []string
+		for _, key := range keys {
+			switch v := t[key].(type) {
+			case map[string]any:
+				sections = append(sections, key)
+			case []any:
+				for _, item := range v {
+					fmt.Fprintf(&b, "%s = %s\n", key, iniText(item))
+				}
+			default:
+				fmt.Fprintf(&b, "%s = %s\n", key, iniText(v))
+			}
+		}
+		for _, key := range sections {
+			full := key
+			if name != "" {
+				full = name + ConfigSep + key
+			}
+			fmt.Fprintf(&b, "\n[%s]\n", full)
+			write(full, t[key].(map[string]any))
+		}
+	}
+	write("", f)
+	return []byte(strings.TrimLeft(b.String(), "\n"))
+}
+
+// iniText renders a value, quoting it if decodeINI would otherwise read it differently
+func iniText(v any) string {
+	text := configText(v)
+	if text != strings.TrimSpace(text) || strings.ContainsAny(text, "#;\"'\n") {
+		return strconv.Quote(text)
+	}
+	return text
+}
+
+// -- dotenv
+// KEY=VALUE lines, optionally exported, decoded into a flat config keyed by variable name
+
+func decodeDotenv(data []byte) (configFile, error) {
+	f := make(configFile)
+	for n, line := range strings.Split(string(data), "\n") {
+		number := n + 1
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		key, value, ok := strings.Cut(line, "=")
+		if key = strings.TrimSpace(key); !ok || key == "" {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", number)
+		}
+		value = strings.TrimSpace(value)
+		if value != "" && (value[0] == '"' || value[0] == '\'') {
+			end := strings.LastIndexByte(value, value[0])
+			if end == 0 {
+				return nil, fmt.Errorf("line %d: unterminated quote", number)
+			}
+			if rest := strings.TrimSpace(value[end+1:]); rest != "" && rest[0] != '#' {
+				return nil, fmt.Errorf("line %d: unexpected text after quoted value", number)
+			}
+			var err error
+			if value, err = unquote(value[:end+1], false); err != nil {
+				return nil, fmt.Errorf("line %d: %w", number, err)
+			}
+		} else {
+			if i := strings.Index(value, " #"); i >= 0 {
+				value = strings.TrimSpace(value[:i])
+			}
+		}
+		f[key] = value
+	}
+	return f, nil
+}
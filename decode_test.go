@@ -0,0 +1,121 @@
+package mandy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecoders(t *testing.T) {
+	want := configFile{
+		"verbose": "true",
+		"tags":    []any{"a", "b,c"},
+		"serve":   map[string]any{"addr": "http://localhost:80", "name": "it's"},
+	}
+	for ext, data := range map[string]string{
+		".yaml": `
+# tool settings
+verbose: true
+tags: [a, "b,c"]
+serve:
+  addr: http://localhost:80  # the listener
+  name: 'it''s'
+`,
+		".yml": `---
+verbose: true
+tags:
+  - a
+  - "b,c"
+serve:
+    addr: "http://localhost:80"
+    name: it's
+`,
+		".toml": `
+verbose = true # loud
+tags = [
+  "a",
+  'b,c',
+]
+
+[serve]
+addr = "http://localhost:80"
+name = "it's"
+`,
+		".ini": `
+; tool settings
+verbose = true
+tags = a
+tags: "b,c"
+
+[serve]
+addr = http://localhost:80 # the listener
+name = it's
+`,
+		".json": `{"verbose": "true", "tags": ["a", "b,c"], "serve": {"addr": "http://localhost:80", "name": "it's"}}`,
+	} {
+		got, err := decoderFor("config" + ext)([]byte(data))
+		if err != nil {
+			t.Errorf("%s: %v", ext, err)
+		} else if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: decoded %#v, want %#v", ext, got, want)
+		}
+	}
+
+	for ext, data := range map[string]string{
+		".yaml": "a: 1\n  b: 2\n",
+		".yml":  "a: |\n  text\n",
+		".toml": "[[servers]]\n",
+		".ini":  "[serve\nport = 80\n",
+		".env":  "PORT\n",
+	} {
+		if _, err := decoderFor("config" + ext)([]byte(data)); err == nil {
+			t.Errorf("%s: decoding %q succeeded", ext, data)
+		}
+	}
+}
+
+func TestEncodeTOML(t *testing.T) {
+	f := configFile{"verbose": "true", "serve": map[string]any{"port": "80", "tags": []any{"a"}}}
+	text := string(encodeTOML(f))
+	if want := "verbose = \"true\"\n\n[serve]\nport = \"80\"\ntags = [\"a\"]\n"; text != want {
+		t.Errorf("encodeTOML = %q, want %q", text, want)
+	}
+	if got, err := decodeTOML([]byte(text)); err != nil || !reflect.DeepEqual(got, f) {
+		t.Errorf("decodeTOML(encodeTOML(f)) = %#v, %v, want %#v", got, err, f)
+	}
+}
+
+func TestEncodeYAML(t *testing.T) {
+	f := configFile{"verbose": "true", "a key": "x: y", "serve": map[string]any{"port": "80", "tags": []any{"a", "#b"}}}
+	text := string(encodeYAML(f))
+	if want := "\"a key\": \"x: y\"\nserve:\n  port: \"80\"\n  tags:\n    - \"a\"\n    - \"#b\"\nverbose: \"true\"\n"; text != want {
+		t.Errorf("encodeYAML = %q, want %q", text, want)
+	}
+	if got, err := decodeYAML([]byte(text)); err != nil || !reflect.DeepEqual(got, f) {
+		t.Errorf("decodeYAML(encodeYAML(f)) = %#v, %v, want %#v", got, err, f)
+	}
+}
+
+func TestEncodeINI(t *testing.T) {
+	f := configFile{"verbose": "true", "note": "a; b", "serve": map[string]any{"port": "80", "tags": []any{"a", "b"}}}
+	text := string(encodeINI(f))
+	if want := "note = \"a; b\"\nverbose = true\n\n[serve]\nport = 80\ntags = a\ntags = b\n"; text != want {
+		t.Errorf("encodeINI = %q, want %q", text, want)
+	}
+	if got, err := decodeINI([]byte(text)); err != nil || !reflect.DeepEqual(got, f) {
+		t.Errorf("decodeINI(encodeINI(f)) = %#v, %v, want %#v", got, err, f)
+	}
+}
+
+func TestDecodeDotenv(t *testing.T) {
+	got, err := decodeDotenv([]byte(`
+# service settings
+export TOOL_ADDR=:9000
+TOOL_NAME = "a # b" # quoted
+TOOL_NOTE=it's # bare
+TOOL_EMPTY=
+`))
+	want := configFile{"TOOL_ADDR": ":9000", "TOOL_NAME": "a # b", "TOOL_NOTE": "it's", "TOOL_EMPTY": ""}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("decodeDotenv = %#v, %v, want %#v", got, err, want)
+	}
+}
@@ -0,0 +1,53 @@
+package mandy
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrDerived is returned when a flag registered with Command.Derived is assigned a value
+var ErrDerived = errors.New("mandy: derived flag can't be set")
+
+// -- derived Value
+// a read-only value computed from the command it belongs to whenever it's read
+type derivedValue struct {
+	cmd     *Command
+	compute func(*Command) string
+}
+
+func (d *derivedValue) Set(string) error { return ErrDerived }
+func (d *derivedValue) Get() any         { return d.String() }
+func (d *derivedValue) String() string {
+	if d.compute == nil || d.cmd == nil {
+		return ""
+	}
+	return d.compute(d.cmd)
+}
+func (d *derivedValue) IsBool() bool { return false }
+
+// Derived defines a read-only flag whose value is computed from the command, typically from its other flags,
+// each time it is read, such as an --endpoint derived from --region. It appears in help, next to its current value,
+// and in Dump, but is left out of config files, and any attempt to set it fails with ErrDerived.
+func (c *Command) Derived(name string, compute func(*Command) string, usage string) *Flag {
+	if compute == nil {
+		panic(c.sprintf("derived flag %s has no compute function", name))
+	}
+	value := &derivedValue{compute: compute}
+	flag := c.Var(value, name, usage, false).HideDefault()
+	value.cmd = c // not before Var, lest compute run before the flags it reads are defined
+	return flag
+}
+
+// IsDerived reports whether the flag was registered with Command.Derived
+func (f *Flag) IsDerived() bool {
+	_, ok := f.Value.(*derivedValue)
+	return ok
+}
+
+// checkDerived refuses to assign values to derived flags
+func (f *Flag) checkDerived() error {
+	if f.IsDerived() {
+		return fmt.Errorf("%w: --%s is computed from other flags", ErrDerived, f.Name)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package mandy
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDerived(t *testing.T) {
+	var region string
+	c := NewCommand("tool", ContinueOnError)
+	c.String(&region, "region", "eu-west-1", "the region to use", false)
+	c.Derived("endpoint", func(self *Command) string {
+		return "https://" + self.Lookup("region").Value.String() + ".example.com"
+	}, "the endpoint requests go to")
+
+	if usage := c.UsageString(); !strings.Contains(usage, "(derived: https://eu-west-1.example.com)") {
+		t.Errorf("usage lacks the derived value:\n%s", usage)
+	}
+	var dump string
+	c.Main = func(self *Command) error {
+		dump = self.Dump()
+		return nil
+	}
+	clone := c.Clone()
+	if err := clone.Execute("--region", "us-east-2"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(dump, "endpoint=https://us-east-2.example.com\t") {
+		t.Errorf("dump after --region us-east-2:\n%s", dump)
+	}
+	if usage := clone.UsageString(); !strings.Contains(usage, "(derived: https://us-east-2.example.com)") {
+		t.Errorf("usage shows a stale derived value:\n%s", usage)
+	}
+	if got := c.Lookup("endpoint").Value.String(); got != "https://eu-west-1.example.com" {
+		t.Errorf("original's endpoint = %q, computed from the clone", got)
+	}
+
+	for name, err := range map[string]error{
+		"command line": c.Clone().Execute("--endpoint", "https://elsewhere"),
+		"Set":          c.Set("endpoint", "https://elsewhere"),
+		"SetDefault":   c.SetDefault("endpoint", "https://elsewhere"),
+	} {
+		if !errors.Is(err, ErrDerived) {
+			t.Errorf("setting by %s: err = %v, want ErrDerived", name, err)
+		}
+	}
+	if keys, _ := c.configKeys(); len(keys) != 1 || keys[0] != "region" {
+		t.Errorf("config keys = %q, want [region]", keys)
+	}
+}
@@ -7,15 +7,18 @@ package mandy
 
 	Define flags using mandy.String(), Bool(), Int(), etc.
 
-	This declares an integer flag, -n, stored in the pointer nFlag, with type *int:
-		import "mandy"
-		var nFlag = mandy.Int("n", 1234, "help message for flag n")
-	If you like, you can bind the flag to a variable using the Var() functions.
+	This declares an integer flag, -n, stored in the variable n:
+		import "github.com/kendfss/mandy"
+		var n int
+		func init() {
+			mandy.Int(&n, "n", 1234, "help message for flag n", false)
+		}
+	The last argument lets the flag be referred to by its initial, as in -f for --flagname:
 		var flag int
 		func init() {
-			mandy.Int(&flag, "flagname", 1234, "help message for flagname")
+			mandy.Int(&flag, "flagname", 1234, "help message for flagname", true)
 		}
-	Or you can create custom flags that satisfy the Value interface (with
+	Or you can create custom flags that satisfy the Getter interface (with
 	pointer receivers) and couple them to flag parsing by
 		mandy.Var(&flagVal, "name", "help message for flagname", true)
 	For such flags, the default value is just the initial value of the variable.
@@ -24,13 +27,11 @@ package mandy
 		mandy.Parse()
 	to parse the command line into the defined flags.
 
-	Flags may then be used directly. If you're using the flags themselves,
-	they are all pointers; if you bind to variables, they're values.
-		fmt.Println("ip has value ", *ip)
+	Flags may then be used directly.
 		fmt.Println("flag has value ", flag)
 
 	After parsing, the arguments following the flags are available as the
-	slice flag.Args() or individually as mandy.Arg(i).
+	slice mandy.Args() or individually as mandy.Arg(i).
 	The arguments are indexed from 0 through mandy.NArg()-1.
 
 	Command line flag syntax
@@ -62,7 +63,7 @@ package mandy
 		1, 0, t, f, T, F, true, false, TRUE, FALSE, True, False, y, n, Y, N, yes, no, Yes, No, YES, NO
 	Duration flags accept any input valid for time.ParseDuration.
 
-	The default set of command-line flags is controlled by
+	The default set of command-line flags, mandy.CommandLine, is controlled by
 	top-level functions.  The Command type allows one to define
 	independent sets of flags, such as to implement subcommands
 	in a command-line interface. The methods of Command are
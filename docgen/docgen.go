@@ -0,0 +1,59 @@
+// Package docgen renders documentation from mandy command trees, such as the man pages packagers ship with programs.
+package docgen
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kendfss/mandy"
+)
+
+// ManSection is the section of the manual the pages belong to
+const ManSection = "1"
+
+// ManPage returns the file name of the command's man page, such as "tool-remote-add.1"
+// for the add child of tool's remote child, which is how the pages refer to each other
+func ManPage(c *mandy.Command) string {
+	return strings.ReplaceAll(c.Path(), " ", "-") + "." + ManSection
+}
+
+// WriteManPages writes the man page of the command, and of each of its descendants, into dir,
+// creating it if need be, and returns the paths of the files written. Hidden and experimental
+// commands are left out, along with their descendants. Pages are written even if some fail.
+func WriteManPages(c *mandy.Command, dir string) (paths []string, err error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	var errs []error
+	var walk func(*mandy.Command)
+	walk = func(cmd *mandy.Command) {
+		path := filepath.Join(dir, ManPage(cmd))
+		if err := writeManPage(cmd, path); err != nil {
+			errs = append(errs, err)
+		} else {
+			paths = append(paths, path)
+		}
+		for _, child := range cmd.Children() {
+			if !child.Hidden && !child.IsExperimental() {
+				walk(child)
+			}
+		}
+	}
+	walk(c)
+	return paths, errors.Join(errs...)
+}
+
+// writeManPage writes the command's man page to the named file
+func writeManPage(c *mandy.Command, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := c.WriteHelp(f, mandy.HelpMan); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,58 @@
+package docgen
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/kendfss/mandy"
+)
+
+func TestWriteManPages(t *testing.T) {
+	var name string
+	c := mandy.NewCommand("tool", mandy.ContinueOnError)
+	c.Summary = "does things"
+	c.URL = "https://example.com/tool"
+	c.String(&name, "name", "gopher", "who to greet", true)
+	remote := c.NewChild("remote", "manage remotes")
+	if err := remote.AddAlias("rem"); err != nil {
+		t.Fatal(err)
+	}
+	remote.NewChild("add", "add a remote")
+	c.NewChild("secret", "").Hidden = true
+	c.NewChild("beta", "").Experimental("TOOL_BETA")
+
+	dir := filepath.Join(t.TempDir(), "man1")
+	paths, err := WriteManPages(c, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, path := range paths {
+		names = append(names, filepath.Base(path))
+	}
+	if want := []string{"tool.1", "tool-remote.1", "tool-remote-add.1"}; !slices.Equal(names, want) {
+		t.Errorf("wrote %q, want %q", names, want)
+	}
+
+	for page, wants := range map[string][]string{
+		"tool.1": {
+			".TH TOOL 1", "tool \\- does things", `\fB\-\-name\fR`, "[default: gopher]",
+			`\fBtool\-remote\fR(1)`, "https://example.com/tool",
+		},
+		"tool-remote.1":     {".TH TOOL\\-REMOTE 1", ".SH ALIASES\nrem", `\fBtool\fR(1), \fBtool\-remote\-add\fR(1)`},
+		"tool-remote-add.1": {"add a remote", `\fBtool\-remote\fR(1)`},
+	} {
+		data, err := os.ReadFile(filepath.Join(dir, page))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range wants {
+			if !strings.Contains(string(data), want) {
+				t.Errorf("%s does not contain %q:\n%s", page, want, data)
+			}
+		}
+	}
+}
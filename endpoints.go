@@ -0,0 +1,129 @@
+package mandy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Endpoints is an ordered list of distinct "host:port" addresses
+type Endpoints []string
+
+// ErrUnreachable is returned when none of a set of endpoints accepts a connection
+var ErrUnreachable = errors.New("mandy: no endpoint is reachable")
+
+// ParseEndpoint validates a "host:port" address and returns it in normal form,
+// with the host in lower case and IPv6 addresses bracketed.
+func ParseEndpoint(s string) (string, error) {
+	host, port, err := net.SplitHostPort(strings.TrimSpace(s))
+	if err != nil {
+		return "", fmt.Errorf("%w: endpoint %q should look like host:port", errParse, s)
+	}
+	if host == "" {
+		return "", fmt.Errorf("%w: endpoint %q has no host", errParse, s)
+	}
+	n, err := strconv.ParseUint(port, 10, 16)
+	if err != nil || n == 0 {
+		return "", fmt.Errorf("%w: endpoint %q has an invalid port", errParse, s)
+	}
+	return net.JoinHostPort(strings.ToLower(host), port), nil
+}
+
+// First returns the first endpoint, without checking that it is reachable
+func (e Endpoints) First() (string, error) {
+	if len(e) == 0 {
+		return "", ErrUnreachable
+	}
+	return e[0], nil
+}
+
+// Reachable dials the endpoints over tcp, in order, and returns the first to accept a connection.
+// Each attempt is abandoned after timeout, if it is positive.
+func (e Endpoints) Reachable(ctx context.Context, timeout time.Duration) (string, error) {
+	dialer := net.Dialer{Timeout: timeout}
+	var errs []error
+	for _, addr := range e {
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err == nil {
+			conn.Close()
+			return addr, nil
+		}
+		errs = append(errs, err)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return "", errors.Join(append([]error{ErrUnreachable}, errs...)...)
+}
+
+// -- Endpoints Value
+// a slice value which drops repeated endpoints, keeping the first occurrence
+type endpointsValue struct {
+	*sliceValue[string]
+	dial time.Duration // how long Parse waits for each endpoint to accept a connection, if it checks them
+}
+
+func newEndpointsValue(val Endpoints, p *Endpoints) *endpointsValue {
+	return &endpointsValue{sliceValue: newSliceValue(val, (*[]string)(p), ParseEndpoint, func(s string) string { return s })}
+}
+
+func (e *endpointsValue) Set(s string) error {
+	if err := e.sliceValue.Set(s); err != nil {
+		return err
+	}
+	seen := make(map[string]bool)
+	*e.p = filter(func(addr string) bool {
+		if seen[addr] {
+			return false
+		}
+		seen[addr] = true
+		return true
+	}, *e.p...)
+	return nil
+}
+
+func (e *endpointsValue) Get() any { return Endpoints(append([]string(nil), *e.p...)) }
+func (e *endpointsValue) String() string {
+	if e.sliceValue == nil {
+		return ""
+	}
+	return e.sliceValue.String()
+}
+
+// Endpoints defines an Endpoints flag with specified name, default value, and usage string.
+// The argument p points to an Endpoints variable in which to store the value of the flag.
+// Each occurrence of the flag accepts a comma separated list of host:port addresses,
+// which are kept in the order given, without duplicates.
+func (c *Command) Endpoints(p *Endpoints, name string, value Endpoints, usage string, short bool) *Flag {
+	return c.Var(newEndpointsValue(value, p), name, usage, short)
+}
+
+// Dial makes Parse check that one of the flag's endpoints, if it has any, accepts a tcp connection,
+// waiting up to timeout for each, once the flag's value is settled, and fail with ErrUnreachable otherwise.
+// Dial panics if the flag isn't an Endpoints flag or timeout isn't positive.
+func (f *Flag) Dial(timeout time.Duration) *Flag {
+	ev, ok := f.Value.(*endpointsValue)
+	if !ok || timeout <= 0 {
+		panic(fmt.Sprintf("flag %q cannot dial its endpoints within %v", f.Name, timeout))
+	}
+	ev.dial = timeout
+	return f
+}
+
+// checkReachable dials the endpoints of the command's flags for which Dial was called
+func (c *Command) checkReachable() error {
+	for _, flag := range c.ordered() {
+		ev, ok := flag.Value.(*endpointsValue)
+		if !ok || ev.dial == 0 || len(*ev.p) == 0 {
+			continue
+		}
+		if _, err := Endpoints(*ev.p).Reachable(context.Background(), ev.dial); err != nil {
+			return fmt.Errorf("--%s: %w", flag.Name, err)
+		}
+	}
+	return nil
+}
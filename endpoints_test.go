@@ -0,0 +1,81 @@
+package mandy
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEndpoints(t *testing.T) {
+	var eps Endpoints
+	c := NewCommand("test", ContinueOnError)
+	c.Endpoints(&eps, "peers", Endpoints{"localhost:1"}, "cluster members", false)
+	if err := c.Parse("--peers=B.example:80,[::1]:443", "--peers", "b.example:80,c.example:8080"); err != nil {
+		t.Fatal(err)
+	}
+	if want := (Endpoints{"b.example:80", "[::1]:443", "c.example:8080"}); !reflect.DeepEqual(eps, want) {
+		t.Errorf("got %q, want %q", eps, want)
+	}
+	for _, bad := range []string{"example.com", ":80", "example.com:0", "example.com:http", "example.com:70000"} {
+		if err := c.Set("peers", bad); !errors.Is(err, errParse) {
+			t.Errorf("%s: expected a parse error, got %v", bad, err)
+		}
+	}
+}
+
+func TestEndpointsReachable(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip(err)
+	}
+	defer ln.Close()
+	dead, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip(err)
+	}
+	deadAddr := dead.Addr().String()
+	dead.Close()
+
+	eps := Endpoints{deadAddr, ln.Addr().String()}
+	got, err := eps.Reachable(context.Background(), time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != ln.Addr().String() {
+		t.Errorf("got %s, want %s", got, ln.Addr())
+	}
+	if _, err := (Endpoints{deadAddr}).Reachable(context.Background(), time.Second); !errors.Is(err, ErrUnreachable) {
+		t.Errorf("expected ErrUnreachable, got %v", err)
+	}
+}
+
+func TestEndpointsDial(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip(err)
+	}
+	defer ln.Close()
+	dead, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip(err)
+	}
+	deadAddr := dead.Addr().String()
+	dead.Close()
+
+	c := NewCommand("test", ContinueOnError)
+	c.SetOutput(io.Discard)
+	c.Endpoints(new(Endpoints), "peers", nil, "cluster members", false).Dial(time.Second)
+	if err := c.Clone().Parse("--peers", deadAddr+","+ln.Addr().String()); err != nil {
+		t.Errorf("one reachable endpoint: %v", err)
+	}
+	if err := c.Clone().Parse("--peers", deadAddr); !errors.Is(err, ErrUnreachable) {
+		t.Errorf("expected ErrUnreachable, got %v", err)
+	}
+	if err := c.Clone().Parse("--"); err != nil {
+		t.Errorf("empty lists should not be checked: %v", err)
+	}
+}
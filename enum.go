@@ -0,0 +1,86 @@
+package mandy
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// -- enum Value
+// a value restricted to a declared set of choices
+type enumValue[T comparable] struct {
+	p       *T
+	choices []T
+	codec   codec[T]
+}
+
+func newEnumValue[T comparable](val T, p *T, elem codec[T], choices []T) *enumValue[T] {
+	*p = val
+	return &enumValue[T]{p: p, choices: choices, codec: elem}
+}
+
+func (e *enumValue[T]) Set(s string) error {
+	v, err := e.codec.parse(s)
+	if err != nil || !slices.Contains(e.choices, v) {
+		return fmt.Errorf("%w: %q should be one of %s", errParse, s, e.list())
+	}
+	*e.p = v
+	return nil
+}
+
+func (e *enumValue[T]) Get() any { return *e.p }
+func (e *enumValue[T]) String() string {
+	if e.p == nil {
+		return ""
+	}
+	return e.codec.format(*e.p)
+}
+func (e *enumValue[T]) IsBool() bool { return false }
+
+// list joins the formatted choices with commas
+func (e *enumValue[T]) list() string {
+	names := make([]string, len(e.choices))
+	for i, choice := range e.choices {
+		names[i] = e.codec.format(choice)
+	}
+	return strings.Join(names, ", ")
+}
+
+// bounds lists the choices, for usage messages
+func (e *enumValue[T]) bounds() string { return "one of " + e.list() }
+
+// Complete suggests the choices beginning with prefix
+func (e *enumValue[T]) Complete(prefix string) (out []string) {
+	for _, choice := range e.choices {
+		if name := e.codec.format(choice); strings.HasPrefix(name, prefix) {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+func (e *enumValue[T]) clone() Getter {
+	cp := *e
+	p := *e.p
+	cp.p = &p
+	return &cp
+}
+
+func (e *enumValue[T]) snapshot() func() { return snapshotPointer(e.p) }
+
+// Enum defines a string flag with specified name, default value, and usage string,
+// which rejects values other than the given choices and lists them in usage messages.
+// The argument p points to a string variable in which to store the value of the flag.
+func (c *Command) Enum(p *string, name string, value string, usage string, short bool, choices ...string) *Flag {
+	return c.Var(newEnumValue(value, p, stringCodec, choices), name, usage, short)
+}
+
+// EnumOf defines an Enum flag whose choices are of any type SliceVar accepts, bound to p.
+// EnumOf panics if the type of the choices is unsupported.
+func EnumOf[T comparable](c *Command, p *T, name string, value T, usage string, short bool, choices ...T) *Flag {
+	elem, ok := codecFor[T]()
+	if !ok {
+		panic(c.sprintf("flag %q has unsupported choice type %T", name, value))
+	}
+	return c.Var(newEnumValue(value, p, elem, choices), name, usage, short)
+}
@@ -0,0 +1,36 @@
+package mandy
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestEnum(t *testing.T) {
+	var (
+		format string
+		level  int
+	)
+	c := NewCommand("test", ContinueOnError)
+	f := c.Enum(&format, "format", "text", "output format", false, "text", "json", "yaml")
+	EnumOf(c, &level, "level", 1, "compression level", false, 1, 5, 9)
+
+	if err := c.Parse("--format=json", "--level", "9"); err != nil {
+		t.Fatal(err)
+	}
+	if format != "json" || level != 9 {
+		t.Errorf("format, level = %q, %d", format, level)
+	}
+	if err := c.Parse("--format", "xml"); !errors.Is(err, errParse) || format != "json" {
+		t.Errorf("unlisted choice: err = %v, format = %q", err, format)
+	}
+	if err := c.Parse("--level", "3"); !errors.Is(err, errParse) {
+		t.Errorf("unlisted level: err = %v, want errParse", err)
+	}
+	if want := "output format (one of text, json, yaml)"; f.description() != want {
+		t.Errorf("description = %q, want %q", f.description(), want)
+	}
+	if got := f.Completions("y"); !slices.Equal(got, []string{"yaml"}) {
+		t.Errorf("Completions(y) = %q", got)
+	}
+}
@@ -0,0 +1,67 @@
+package mandy
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// SetEnviron replaces the function through which the command and its children read environment
+// variables, such as those enabling experimental commands, deriving URLs, resolving "env:" secrets,
+// or locating the state directory, so that tests can be hermetic and servers can give each request
+// its own environment.
+// If lookup is nil, the parent's function, or os.LookupEnv, is used.
+func (c *Command) SetEnviron(lookup func(key string) (string, bool)) {
+	c.environ = lookup
+	c.invalidate() // usage messages may list commands the environment enables
+}
+
+// lookupEnv reads an environment variable through the nearest function set by SetEnviron
+func (c *Command) lookupEnv(key string) (string, bool) {
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		if cmd.environ != nil {
+			return cmd.environ(key)
+		}
+	}
+	return os.LookupEnv(key)
+}
+
+// MapEnviron returns a lookup function, for SetEnviron, reading the variables in env
+func MapEnviron(env map[string]string) func(key string) (string, bool) {
+	return func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+}
+
+// Env binds the flag to the environment variable name, which supplies the flag's value when it is not
+// given on the command line, so that the command line wins over the environment, and the environment
+// over the default. The variable is read through the command's SetEnviron function and named in usage messages.
+func (f *Flag) Env(name string) *Flag {
+	f.env = name
+	return f
+}
+
+// EnvName returns the name of the environment variable bound to the flag by Env, if any
+func (f *Flag) EnvName() string {
+	return f.env
+}
+
+// applyEnv sets the command's flags that are bound to environment variables, and were not set by the
+// command line, from the variables that are set
+func (c *Command) applyEnv() error {
+	var errs []error
+	for _, flag := range c.ordinalFlags() {
+		if _, set := c.actual[flag.Name]; set || flag.env == "" {
+			continue
+		}
+		value, ok := c.lookupEnv(flag.env)
+		if !ok {
+			continue
+		}
+		if err := c.setFrom(flag, value, Origin{Source: SourceEnv, Env: flag.env}); err != nil {
+			errs = append(errs, fmt.Errorf("invalid value for flag %s from $%s: %s: %w", flag.Name, flag.env, flag.redact(value), err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,85 @@
+package mandy
+
+import (
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSetEnviron(t *testing.T) {
+	root := NewCommand("tool", ContinueOnError)
+	root.SetEnviron(MapEnviron(map[string]string{
+		"REPO_HOST": "https://git.example.com",
+		"DEVELOPER": "gopher",
+		"TOOL_BETA": "1",
+	}))
+	beta := root.NewChild("beta", "").Experimental("TOOL_BETA")
+	beta.Main = func(*Command) error { return nil }
+
+	if !strings.Contains(root.UsageString(), "https://git.example.com/gopher/tool") {
+		t.Errorf("usage lacks the URL derived from the injected environment:\n%s", root.UsageString())
+	}
+	if err := root.Execute("beta"); err != nil {
+		t.Errorf("experimental command enabled by the injected environment: %v", err)
+	}
+
+	beta.SetEnviron(MapEnviron(nil))
+	if err := root.Execute("beta"); !errors.Is(err, ErrExperimental) {
+		t.Errorf("experimental command under an empty environment: err = %v, want ErrExperimental", err)
+	}
+}
+
+func TestEnvironLookups(t *testing.T) {
+	var token string
+	c := NewCommand("tool", ContinueOnError)
+	c.SetEnviron(MapEnviron(map[string]string{"TOOL_TOKEN": "hunter2", "XDG_STATE_HOME": "/state"}))
+	c.Secret(&token, "token", "", "api token", false)
+
+	if err := c.Parse("--token", "env:TOOL_TOKEN"); err != nil || token != "hunter2" {
+		t.Errorf("token = %q, %v; want it read from the injected environment", token, err)
+	}
+	if _, file, err := c.trustHashes(); err != nil || file != filepath.Join("/state", "tool", TrustFile) {
+		t.Errorf("trust file = %q, %v; want it under the injected $XDG_STATE_HOME", file, err)
+	}
+}
+
+func TestFlagEnv(t *testing.T) {
+	for _, test := range []struct {
+		env  map[string]string
+		args []string
+		want string
+	}{
+		{nil, []string{"--"}, ":8080"},
+		{map[string]string{"APP_ADDR": ":9090"}, []string{"--"}, ":9090"},
+		{map[string]string{"APP_ADDR": ":9090"}, []string{"--addr", ":7070"}, ":7070"},
+	} {
+		var addr string
+		c := NewCommand("test", ContinueOnError)
+		c.SetEnviron(MapEnviron(test.env))
+		c.String(&addr, "addr", ":8080", "listen address", false).Env("APP_ADDR")
+		if err := c.Parse(test.args...); err != nil {
+			t.Errorf("env %v, Parse(%q): %v", test.env, test.args, err)
+		} else if addr != test.want {
+			t.Errorf("env %v, Parse(%q): addr = %q, want %q", test.env, test.args, addr, test.want)
+		}
+	}
+
+	var port int
+	var password string
+	c := NewCommand("test", ContinueOnError)
+	c.SetOutput(io.Discard)
+	c.SetEnviron(MapEnviron(map[string]string{"APP_PORT": "eighty", "APP_PASSWORD": "hunter2"}))
+	c.Int(&port, "port", 80, "listen port", false).Env("APP_PORT")
+	c.String(&password, "password", "", "", false).Env("APP_PASSWORD").Restrict(SourceEnv).Required()
+	if err := c.Parse("--"); !errors.Is(err, errParse) {
+		t.Errorf("Parse with an invalid variable = %v, want errParse", err)
+	}
+	if password != "hunter2" {
+		t.Errorf("password = %q, want it from the environment despite Restrict", password)
+	}
+	if want := "--port\tlisten port [env: APP_PORT]"; !strings.Contains(c.Defaults(), want) {
+		t.Errorf("Defaults() = %q, want it to contain %q", c.Defaults(), want)
+	}
+}
@@ -21,6 +21,19 @@ var (
 	// The error returned when a command that has no main function is Executed
 	ErrNilMain = errors.New("mandy: attempted to Execute a command with no Main function")
 
+	// ErrExperimental is returned when dispatching to an experimental command that has not been enabled
+	ErrExperimental = errors.New("mandy: experimental command is not enabled")
+
+	// ErrConflict is returned by Command.Merge when flag names collide under ConflictError,
+	// and by Command.Mount when a group's flags take names already taken or reserved
+	ErrConflict = errors.New("mandy: flag name conflict")
+
+	// ErrOnce is returned when a flag marked with Flag.Once is assigned a second time
+	ErrOnce = errors.New("mandy: flag may only be set once")
+
+	// ErrRequired is returned by Parse when flags marked with Flag.Required are not set
+	ErrRequired = errors.New("mandy: required flag not set")
+
 	// errParse is returned by Set if a flag's value fails to parse, such as with an invalid integer for Int.
 	// It then gets wrapped through failf to provide more information.
 	errParse = errors.New("parse error")
@@ -0,0 +1,55 @@
+package mandy
+
+import (
+	"fmt"
+)
+
+// ExperimentalName is the name of the root flag which enables every experimental command
+var ExperimentalName = "enable-experimental"
+
+// Experimental marks the command as a preview.
+// It is hidden from its parent's usage and refuses to run unless the
+// environment variable envVar is set, or the root command was given the
+// --enable-experimental flag, which this registers if necessary.
+// A warning is written to the command's output whenever it is dispatched to.
+func (c *Command) Experimental(envVar string) *Command {
+	c.experimental = envVar
+	root := c.first()
+	if _, ok := root.formal[ExperimentalName]; !ok {
+		root.Bool(new(bool), ExperimentalName, false, "enable experimental commands", false)
+	}
+	return c
+}
+
+// IsExperimental reports whether the command has been marked as a preview
+func (c *Command) IsExperimental() bool {
+	return c.experimental != ""
+}
+
+// experimentalEnabled reports whether an experimental command may run
+func (c *Command) experimentalEnabled() bool {
+	if v, _ := c.lookupEnv(c.experimental); v != "" {
+		return true
+	}
+	root := c.first()
+	flag, ok := root.formal[ExperimentalName]
+	return ok && root.Visited(flag) && flag.Value.String() == "true"
+}
+
+// hidden reports whether the command should be left out of its parent's usage
+func (c *Command) hidden() bool {
+	return c.Hidden || c.IsExperimental() && !c.experimentalEnabled()
+}
+
+// gate checks whether the command may be dispatched to
+// experimental commands warn when they are enabled and fail otherwise
+func (c *Command) gate() error {
+	if !c.IsExperimental() {
+		return nil
+	}
+	if !c.experimentalEnabled() {
+		return fmt.Errorf("%w: set $%s or pass --%s to use %q", ErrExperimental, c.experimental, ExperimentalName, c.name_())
+	}
+	fmt.Fprintf(c.Output(), "warning: %q is experimental and may change or be removed\n", c.name_())
+	return nil
+}
@@ -0,0 +1,43 @@
+package mandy
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestExperimental(t *testing.T) {
+	const env = "MANDY_TEST_PREVIEW"
+	ran := false
+	root := NewCommand("root", ContinueOnError)
+	root.SetOutput(io.Discard)
+	preview := root.NewChild("preview", "try new things").Experimental(env)
+	preview.SetOutput(io.Discard)
+	preview.Main = func(*Command) error {
+		ran = true
+		return nil
+	}
+
+	if strings.Contains(root.defaultUsage(), "preview") {
+		t.Error("experimental child should be hidden from usage")
+	}
+	if err := root.Execute("preview"); !errors.Is(err, ErrExperimental) {
+		t.Fatalf("expected ErrExperimental, got %v", err)
+	}
+	if ran {
+		t.Fatal("disabled experimental command ran")
+	}
+
+	if err := root.Execute("--"+ExperimentalName, "preview"); err != nil {
+		t.Fatal(err)
+	}
+	if !ran {
+		t.Fatal("enabled experimental command did not run")
+	}
+
+	t.Setenv(env, "1")
+	if !strings.Contains(root.defaultUsage(), "preview") {
+		t.Error("enabled experimental child should be listed in usage")
+	}
+}
@@ -1,5 +1,10 @@
 package mandy
 
+import (
+	"io"
+	"os"
+)
+
 // Additional routines compiled into the package only during testing.
 
 // var DefaultUsage = Usage
@@ -9,7 +14,11 @@ var DefaultUsage = func() {}
 // After calling ResetForTesting, parse errors in flag handling will not
 // exit the program.
 func ResetForTesting(usage func()) {
-	// CommandLine = NewFlagSet(os.Args[0], ContinueOnError)
-	// CommandLine.Usage = commandLineUsage
-	// Usage = usage
+	CommandLine = NewCommand(os.Args[0], ContinueOnError)
+	if usage != nil {
+		CommandLine.Usage = func(io.Writer, *Command) error {
+			usage()
+			return nil
+		}
+	}
 }
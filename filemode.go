@@ -0,0 +1,136 @@
+package mandy
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ParseFileMode parses a permission mode in octal ("644", "0o755", "4755") or
+// chmod's symbolic notation ("u+rwx", "go-w", "a=r,u+w"), which is applied to base.
+// Only permission bits and the setuid, setgid, and sticky bits are honoured.
+func ParseFileMode(s string, base os.FileMode) (os.FileMode, error) {
+	if s == "" {
+		return 0, fmt.Errorf("%w: empty file mode", errParse)
+	}
+	if '0' <= s[0] && s[0] <= '9' {
+		digits := strings.TrimPrefix(strings.TrimPrefix(s, "0o"), "0O")
+		m, err := strconv.ParseUint(digits, 8, 32)
+		if err != nil || m > 07777 {
+			return 0, fmt.Errorf("%w: invalid file mode %q", errParse, s)
+		}
+		return fromUnixMode(uint32(m)), nil
+	}
+	m := toUnixMode(base)
+	for _, clause := range strings.Split(s, ",") {
+		var err error
+		if m, err = applySymbolicMode(clause, m); err != nil {
+			return 0, fmt.Errorf("%w: invalid file mode %q", errParse, s)
+		}
+	}
+	return fromUnixMode(m), nil
+}
+
+// applySymbolicMode applies a clause such as "ug+rw-x" to the unix mode m
+func applySymbolicMode(clause string, m uint32) (uint32, error) {
+	var who uint32
+	i := 0
+	for ; i < len(clause) && strings.IndexByte("ugoa", clause[i]) >= 0; i++ {
+		who |= map[byte]uint32{'u': 04700, 'g': 02070, 'o': 00007, 'a': 07777}[clause[i]]
+	}
+	if who == 0 {
+		who = 07777
+	}
+	if i == len(clause) {
+		return 0, errParse
+	}
+	for i < len(clause) {
+		op := clause[i]
+		if strings.IndexByte("+-=", op) < 0 {
+			return 0, errParse
+		}
+		var bits uint32
+		for i++; i < len(clause) && strings.IndexByte("+-=", clause[i]) < 0; i++ {
+			bit, ok := map[byte]uint32{'r': 0444, 'w': 0222, 'x': 0111, 's': 06000, 't': 01000}[clause[i]]
+			if !ok {
+				return 0, errParse
+			}
+			bits |= bit
+		}
+		bits &= who | 01000
+		switch op {
+		case '+':
+			m |= bits
+		case '-':
+			m &^= bits
+		case '=':
+			m = m&^who | bits
+		}
+	}
+	return m, nil
+}
+
+// toUnixMode converts m to the numeric mode used by chmod
+func toUnixMode(m os.FileMode) uint32 {
+	u := uint32(m.Perm())
+	if m&os.ModeSetuid != 0 {
+		u |= 04000
+	}
+	if m&os.ModeSetgid != 0 {
+		u |= 02000
+	}
+	if m&os.ModeSticky != 0 {
+		u |= 01000
+	}
+	return u
+}
+
+// fromUnixMode converts a numeric mode, as used by chmod, to an os.FileMode
+func fromUnixMode(u uint32) os.FileMode {
+	m := os.FileMode(u & 0777)
+	if u&04000 != 0 {
+		m |= os.ModeSetuid
+	}
+	if u&02000 != 0 {
+		m |= os.ModeSetgid
+	}
+	if u&01000 != 0 {
+		m |= os.ModeSticky
+	}
+	return m
+}
+
+// FormatFileMode renders a mode's permission and special bits in octal, as in "0644" or "04755"
+func FormatFileMode(m os.FileMode) string {
+	return fmt.Sprintf("%#o", toUnixMode(m))
+}
+
+// -- os.FileMode Value
+type fileModeValue os.FileMode
+
+func newFileModeValue(val os.FileMode, p *os.FileMode) *fileModeValue {
+	*p = val
+	return (*fileModeValue)(p)
+}
+
+// Set parses octal modes absolutely, and symbolic ones relative to the current value
+func (f *fileModeValue) Set(s string) error {
+	v, err := ParseFileMode(s, os.FileMode(*f))
+	if err != nil {
+		return err
+	}
+	*f = fileModeValue(v)
+	return nil
+}
+
+func (f *fileModeValue) Get() any       { return os.FileMode(*f) }
+func (f *fileModeValue) String() string { return FormatFileMode(os.FileMode(*f)) }
+func (f *fileModeValue) IsBool() bool   { return false }
+
+// FileMode defines an os.FileMode flag with specified name, default value, and usage string.
+// The argument p points to an os.FileMode variable in which to store the value of the flag.
+// The flag accepts a value acceptable to ParseFileMode; symbolic modes modify the flag's current value.
+func (c *Command) FileMode(p *os.FileMode, name string, value os.FileMode, usage string, short bool) *Flag {
+	return c.Var(newFileModeValue(value, p), name, usage, short)
+}
@@ -0,0 +1,49 @@
+package mandy
+
+import (
+	"os"
+	"testing"
+)
+
+func TestParseFileMode(t *testing.T) {
+	for _, test := range []struct {
+		in   string
+		base os.FileMode
+		want os.FileMode
+	}{
+		{"644", 0, 0644},
+		{"0o755", 0, 0755},
+		{"4755", 0, 0755 | os.ModeSetuid},
+		{"u+x", 0644, 0744},
+		{"go-w", 0666, 0644},
+		{"a=r,u+w", 0777, 0644},
+		{"+x", 0644, 0755},
+		{"u=rwx,g=rx,o=", 0, 0750},
+		{"u+s,+t", 0755, 0755 | os.ModeSetuid | os.ModeSticky},
+		{"ug+rw-x", 0711, 0661},
+	} {
+		got, err := ParseFileMode(test.in, test.base)
+		if err != nil {
+			t.Errorf("%q: %v", test.in, err)
+		} else if got != test.want {
+			t.Errorf("%q on %v: got %v, want %v", test.in, test.base, got, test.want)
+		}
+	}
+	for _, in := range []string{"", "999", "77777", "u", "u+q", "z+r", "u+r,"} {
+		if _, err := ParseFileMode(in, 0); err == nil {
+			t.Errorf("%q should not parse", in)
+		}
+	}
+}
+
+func TestFileModeFlag(t *testing.T) {
+	var mode os.FileMode
+	c := NewCommand("install", ContinueOnError)
+	flag := c.FileMode(&mode, "mode", 0644, "permissions of installed files", false)
+	if flag.DefValue != "0644" {
+		t.Errorf("default renders as %q", flag.DefValue)
+	}
+	if err := c.Set("mode", "u+x"); err != nil || mode != 0744 {
+		t.Errorf("got %v, %v", mode, err)
+	}
+}
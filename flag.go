@@ -2,7 +2,7 @@ package mandy
 
 import (
 	"fmt"
-	"reflect"
+	"time"
 )
 
 // type FlagSet map[string]*Flag
@@ -14,35 +14,94 @@ type Flag struct {
 	DefValue    string // default value (as text); for usage message
 	Short       bool   // whether or not the flag can be referenced by abbreviation
 	Value       Getter // value as set
+	NoOptDefVal string // if not empty, the value of a non-boolean flag given bare; other values must then follow "="
 	// Value       Value  // value as set
 	// visited bool
+	hideDefault bool          // whether or not usage messages omit the default value
+	ordinal     int           // the flag's position in its command's registration order
+	once        bool          // whether or not a second explicit assignment is an error
+	sources     Source        // the sources the flag may be set from, any if zero
+	addedBy     string        // the plugin that registered the flag, if any
+	negatable   bool          // whether or not --no-<name> sets a boolean flag to false
+	required    bool          // whether or not parsing fails if the flag is not set
+	env         string        // the environment variable supplying the value when the command line doesn't, if any
+	origin      Origin        // where the value was set from, if it was
+	defaultSet  bool          // whether the default was replaced by Command.SetDefault
+	fromFile    bool          // whether values beginning with ValueFilePrefix name the file holding the value
+	cacheTTL    time.Duration // how long the CompletionCommand keeps the value's completions, if at all
+
+	redactor func(string) string // masks the flag's values where they're shown, if set by Redact
 }
 
-// Eq checks if a flag has a given value
-func (f *Flag) Eq(arg any) bool {
-	return reflect.ValueOf(f.Value.Get()).Equal(reflect.ValueOf(arg))
+// DefaultStyle determines how a Command's usage message renders flag defaults.
+type DefaultStyle uint8
+
+const (
+	DefaultInline        DefaultStyle = iota // "[default: x]"
+	DefaultParenthesized                     // "(default x)"
+	DefaultNonZero                           // "[default: x]", omitted for zero values
+)
+
+// Redacted is shown in place of the values of secret flags
+const Redacted = "***"
+
+// redactedValue is implemented by Values whose contents are kept out of logs and usage messages
+type redactedValue interface {
+	redacted()
 }
 
-// func (f *Flag) Visited() bool {
-// 	return f.visited
-// }
+// IsSecret reports whether the flag's value should be kept out of logs and usage messages
+func (f *Flag) IsSecret() bool {
+	_, ok := f.Value.(redactedValue)
+	return ok || f.redactor != nil
+}
 
-// isZeroValue determines whether the string represents the zero
-// value for a flag.
-func isZeroValue(flag *Flag, value string) bool {
-	// Build a zero value of the flag's Value type, and see if the
-	// result of calling its String method equals the value passed in.
-	// This works unless the Value type is itself an interface type.
-	typ := reflect.TypeOf(flag.Value)
-	var z reflect.Value
-	if typ.Kind() == reflect.Pointer {
-		z = reflect.New(typ.Elem())
-	} else {
-		z = reflect.Zero(typ)
+// Redact masks the flag's non-empty values with fn wherever they're shown: in usage messages, dumps,
+// invocation records, config listings, and error messages. Secret flags are masked with Redacted unless
+// Redact replaces it, as with RevealLast, to show enough of a value to tell which one was given.
+func (f *Flag) Redact(fn func(string) string) *Flag {
+	f.redactor = fn
+	return f
+}
+
+// RevealLast returns a function for Redact that masks all but the last n characters of a value,
+// or all of them when there are no more than n
+func RevealLast(n int) func(string) string {
+	return func(value string) string {
+		if runes := []rune(value); len(runes) > n {
+			return Redacted + string(runes[len(runes)-n:])
+		}
+		return Redacted
+	}
+}
+
+// redact masks non-empty values of secret flags
+func (f *Flag) redact(value string) string {
+	if !f.IsSecret() || value == "" {
+		return value
+	}
+	if f.redactor != nil {
+		return f.redactor(value)
 	}
-	return value == z.Interface().(Value).String()
+	return Redacted
 }
 
+// HideDefault stops usage messages from rendering the flag's default value
+func (f *Flag) HideDefault() *Flag {
+	f.hideDefault = true
+	return f
+}
+
+// Once makes any explicit assignment after the first an error, rather than a silent override
+func (f *Flag) Once() *Flag {
+	f.once = true
+	return f
+}
+
+// func (f *Flag) Visited() bool {
+// 	return f.visited
+// }
+
 // UnquoteDescription extracts a back-quoted name from the usage
 // string for a flag and returns it and the un-quoted usage.
 // Given "a `name` to show" it returns ("name", "a name to show").
@@ -65,16 +124,24 @@ func UnquoteDescription(flag *Flag) (name string, usage string) {
 	}
 	// No explicit name, so use type if we can find one.
 	name = "value"
-	switch flag.Value.(type) {
-	case boolFlag:
-		name = ""
+	if flag.Value.IsBool() {
+		return "", usage
+	}
+	if elems := elements(flag.Value); elems != "" {
+		return elems, usage
+	}
+	switch unwrap(flag.Value).(type) {
 	case *durationValue:
 		name = "duration"
+	case *timeWindowValue:
+		name = "window"
+	case *fileModeValue:
+		name = "mode"
 	case *float64Value:
 		name = "float"
 	case *intValue, *int64Value:
 		name = "int"
-	case *stringValue:
+	case *stringValue, *secretValue:
 		name = "string"
 	case *uintValue, *uint64Value:
 		name = "uint"
@@ -84,8 +151,8 @@ func UnquoteDescription(flag *Flag) (name string, usage string) {
 
 func (f Flag) help() flagHelp {
 	return flagHelp{
-		desc:  f.Description,
-		def:   f.DefValue,
+		desc:  f.description(),
+		def:   f.redact(f.DefValue),
 		short: f.Short,
 		name:  f.Name,
 	}
@@ -95,16 +162,67 @@ func (f Flag) help() flagHelp {
 	// )
 }
 
-func (f Flag) usage() (out string) {
+func (f Flag) usage(style DefaultStyle) (out string) {
+	long := "--" + f.Name
+	if f.negatable && f.Value.IsBool() {
+		long = "--[" + NegationPrefix + "]" + f.Name
+	} else if f.NoOptDefVal != "" && !f.Value.IsBool() {
+		long += "[=" + f.NoOptDefVal + "]"
+	}
 	if f.Short {
-		out += fmt.Sprintf("-%c, --%s", f.Name[0], f.Name)
+		out += fmt.Sprintf("-%c, %s", f.Name[0], long)
 	} else {
-		out += "--" + f.Name
+		out += long
+	}
+	out += "\t" + f.description()
+	if def := f.defaultText(style); def != "" {
+		out += " " + def
 	}
-	out += fmt.Sprintf("\t%s [default: %s]", f.Description, f.DefValue)
 	return
 }
 
+// description is the flag's Description followed by any conventions its value follows
+func (f Flag) description() string {
+	desc := f.Description
+	if bv, ok := f.Value.(boundedValue); ok && bv.bounds() != "" {
+		desc += " (" + bv.bounds() + ")"
+	}
+	if conv := f.convention(); conv != "" {
+		desc += " " + conv
+	}
+	if f.env != "" {
+		desc += " [env: " + f.env + "]"
+	}
+	if f.IsDerived() {
+		desc += " (derived: " + f.Value.String() + ")"
+	}
+	if f.fromFile {
+		desc += " (or " + ValueFilePrefix + "file)"
+	}
+	if f.required {
+		desc += " (required)"
+	}
+	return desc
+}
+
+// defaultText renders the flag's default value in the given style
+// returns an empty string if the default should be omitted
+func (f Flag) defaultText(style DefaultStyle) string {
+	if f.hideDefault || f.required {
+		return "" // a required flag's default is never used
+	}
+	def := f.redact(f.DefValue)
+	switch style {
+	case DefaultParenthesized:
+		return fmt.Sprintf("(default %s)", def)
+	case DefaultNonZero:
+		if isZeroValue(&f, f.DefValue) {
+			return ""
+		}
+	}
+	return fmt.Sprintf("[default: %s]", def)
+}
+
 // UnquoteUsage extracts a back-quoted name from the usage
 // string for a flag and returns it and the un-quoted usage.
 // Given "a `name` to show" it returns ("name", "a name to show").
@@ -127,16 +245,24 @@ func UnquoteUsage(flag *Flag) (name string, usage string) {
 	}
 	// No explicit name, so use type if we can find one.
 	name = "value"
-	switch flag.Value.(type) {
-	case boolFlag:
-		name = ""
+	if flag.Value.IsBool() {
+		return "", usage
+	}
+	if elems := elements(flag.Value); elems != "" {
+		return elems, usage
+	}
+	switch unwrap(flag.Value).(type) {
 	case *durationValue:
 		name = "duration"
+	case *timeWindowValue:
+		name = "window"
+	case *fileModeValue:
+		name = "mode"
 	case *float64Value:
 		name = "float"
 	case *intValue, *int64Value:
 		name = "int"
-	case *stringValue:
+	case *stringValue, *secretValue:
 		name = "string"
 	case *uintValue, *uint64Value:
 		name = "uint"
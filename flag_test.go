@@ -0,0 +1,67 @@
+package mandy
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDefaultStyle(t *testing.T) {
+	var (
+		n    int
+		name string
+	)
+	c := NewCommand("test", ContinueOnError)
+	zero := c.Int(&n, "num", 0, "a number", false)
+	full := c.String(&name, "name", "gopher", "a name", false)
+	hex := c.Int(new(int), "mask", 0, "a mask", false).Base(16)
+	grouped := c.Int(new(int), "size", 0, "a size", false).Numbers(GroupedDigits)
+
+	tests := []struct {
+		flag  *Flag
+		style DefaultStyle
+		want  string
+	}{
+		{zero, DefaultInline, "--num\ta number [default: 0]"},
+		{zero, DefaultParenthesized, "--num\ta number (default 0)"},
+		{zero, DefaultNonZero, "--num\ta number"},
+		{full, DefaultNonZero, "--name\ta name [default: gopher]"},
+		{hex, DefaultNonZero, "--mask\ta mask"},
+		{grouped, DefaultNonZero, "--size\ta size"},
+	}
+	for _, test := range tests {
+		if got := test.flag.usage(test.style); got != test.want {
+			t.Errorf("usage(%d) = %q, want %q", test.style, got, test.want)
+		}
+	}
+
+	full.HideDefault()
+	if got, want := full.usage(DefaultInline), "--name\ta name"; got != want {
+		t.Errorf("usage after HideDefault = %q, want %q", got, want)
+	}
+}
+
+func TestOnce(t *testing.T) {
+	var (
+		config  string
+		verbose bool
+	)
+	c := NewCommand("test", ContinueOnError)
+	c.String(&config, "config", "", "a config file", false).Once()
+	c.Bool(&verbose, "verbose", false, "talk more", true).Once()
+
+	if err := c.Parse("--config", "a.json"); err != nil {
+		t.Fatalf("first assignment: %v", err)
+	}
+	if err := c.Parse("--config", "a.json", "--config=b.json"); !errors.Is(err, ErrOnce) {
+		t.Errorf("repeated --config: err = %v, want ErrOnce", err)
+	}
+	if config != "a.json" {
+		t.Errorf("config = %q after rejected override, want a.json", config)
+	}
+	if err := c.Parse("-vv"); !errors.Is(err, ErrOnce) {
+		t.Errorf("repeated -v: err = %v, want ErrOnce", err)
+	}
+	if err := c.Set("config", "c.json"); !errors.Is(err, ErrOnce) {
+		t.Errorf("Set after parse: err = %v, want ErrOnce", err)
+	}
+}
@@ -1,6 +1,6 @@
 module github.com/kendfss/mandy
 
-go 1.22.2
+go 1.23
 
 require (
 	github.com/kendfss/but v1.0.0
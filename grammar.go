@@ -0,0 +1,69 @@
+package mandy
+
+import "strings"
+
+// A FlagKind is a class of flags that share the forms they may be given in
+type FlagKind uint8
+
+const (
+	KindBool  FlagKind = iota // flags whose values report IsBool, which may omit their value
+	KindValue                 // all other flags, which must be given a value
+)
+
+func (k FlagKind) String() string {
+	if k == KindBool {
+		return "bool"
+	}
+	return "value"
+}
+
+// The placeholders of a Form's tokens
+const (
+	FormName     = "{name}"     // the flag's full name
+	FormInitial  = "{initial}"  // the flag's initial
+	FormInitials = "{initials}" // the initials of any number of other short boolean flags
+	FormValue    = "{value}"    // the flag's value
+)
+
+// Terminator is the argument that ends flag parsing; the arguments following it are positional
+const Terminator = "--"
+
+// A Form is a way of writing a flag on the command line
+type Form struct {
+	Kind   FlagKind // the kind of flag the form applies to
+	Tokens []string // the arguments that make up the form, with placeholders for their variable parts
+	Short  bool     // the form names the flag by its initial, so the flag must be Short
+	Bare   bool     // the form is only recognised by commands with BareAssignments
+}
+
+// Expand substitutes a flag's name, initial, and value for the form's placeholders, omitting other initials
+func (f Form) Expand(name, value string) []string {
+	r := strings.NewReplacer(FormName, name, FormInitial, name[:1], FormInitials, "", FormValue, value)
+	out := make([]string, len(f.Tokens))
+	for i, tok := range f.Tokens {
+		out[i] = r.Replace(tok)
+	}
+	return out
+}
+
+// Grammar describes the forms Command.Parse accepts flags in.
+// Every flag may be given by any form of its kind, and Short flags by the Short forms too.
+// In all of them, one dash may be used in place of two, but "-name" is read as a cluster of initials.
+// Values are taken verbatim; a separate value argument may even begin with a dash.
+// Parsing stops at the Terminator, which is consumed, at "-", and at the first other free argument.
+func Grammar() []Form {
+	return []Form{
+		{Kind: KindBool, Tokens: []string{"--" + FormName}},
+		{Kind: KindBool, Tokens: []string{"--" + FormName + "=" + FormValue}},
+		{Kind: KindBool, Tokens: []string{"-" + FormInitial}, Short: true},
+		{Kind: KindBool, Tokens: []string{"-" + FormInitials + FormInitial + FormInitials}, Short: true},
+		{Kind: KindBool, Tokens: []string{"-" + FormInitial + "=" + FormValue}, Short: true},
+		{Kind: KindBool, Tokens: []string{FormName + "=" + FormValue}, Bare: true},
+		{Kind: KindValue, Tokens: []string{"--" + FormName, FormValue}},
+		{Kind: KindValue, Tokens: []string{"--" + FormName + "=" + FormValue}},
+		{Kind: KindValue, Tokens: []string{"-" + FormInitial, FormValue}, Short: true},
+		{Kind: KindValue, Tokens: []string{"-" + FormInitials + FormInitial, FormValue}, Short: true},
+		{Kind: KindValue, Tokens: []string{"-" + FormInitial + "=" + FormValue}, Short: true},
+		{Kind: KindValue, Tokens: []string{FormName + "=" + FormValue}, Bare: true},
+	}
+}
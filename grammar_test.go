@@ -0,0 +1,185 @@
+package mandy
+
+import (
+	"io"
+	"math/rand/v2"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestFormExpand(t *testing.T) {
+	form := Form{Kind: KindValue, Tokens: []string{"-" + FormInitials + FormInitial, FormValue}, Short: true}
+	if got, want := form.Expand("num", "3"), []string{"-n", "3"}; !slices.Equal(got, want) {
+		t.Errorf("Expand = %q, want %q", got, want)
+	}
+}
+
+// genFlag is a randomly generated flag, and the value it's expected to have after parsing
+type genFlag struct {
+	name  string
+	kind  FlagKind
+	short bool
+	value string
+}
+
+// genCommand is a randomly generated command line, and the flags and arguments it should parse into
+type genCommand struct {
+	flags [][]genFlag // the flags of the root and, if there are two sets, its child
+	argv  []string
+	args  []string
+}
+
+// build defines the generated flags on a new command tree
+func (g *genCommand) build() *Command {
+	root := NewCommand("tool", ContinueOnError)
+	root.SetOutput(io.Discard)
+	cmd := root
+	for i, flags := range g.flags {
+		if i > 0 {
+			cmd = cmd.NewChild("sub", "")
+		}
+		for _, f := range flags {
+			if f.kind == KindBool {
+				cmd.Bool(new(bool), f.name, false, "a switch", f.short)
+			} else if f.value == "" || strings.Trim(f.value, "-0123456789") != "" {
+				cmd.String(new(string), f.name, "", "some text", f.short)
+			} else {
+				cmd.Int(new(int), f.name, 0, "a number", f.short)
+			}
+		}
+	}
+	return root
+}
+
+const genRunes = "abcdefgijklmnopqrstuvwxyz -='\"$\\=,.;!*"
+
+// genValue returns a random value for a flag of the given kind
+func genValue(r *rand.Rand, kind FlagKind) string {
+	switch {
+	case kind == KindBool:
+		return strconv.FormatBool(r.IntN(2) == 0)
+	case r.IntN(3) == 0:
+		return strconv.Itoa(r.IntN(2000) - 1000)
+	}
+	var b strings.Builder
+	for range r.IntN(8) {
+		b.WriteByte(genRunes[r.IntN(len(genRunes))])
+	}
+	if v := b.String(); strings.Trim(v, "-0123456789") != "" {
+		return v
+	}
+	return "x" + b.String()
+}
+
+// genFlags returns a random set of flags with distinct names and initials, none clashing with help
+func genFlags(r *rand.Rand) []genFlag {
+	var flags []genFlag
+	seen := map[string]bool{"help": true, "sub": true}
+	initials := map[byte]bool{'h': true}
+	for range r.IntN(6) {
+		name := string(rune('a' + r.IntN(26)))
+		for range 1 + r.IntN(5) {
+			name += string(rune('a' + r.IntN(26)))
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		f := genFlag{name: name, kind: FlagKind(r.IntN(2))}
+		if !initials[name[0]] && r.IntN(2) == 0 {
+			f.short, initials[name[0]] = true, true
+		}
+		flags = append(flags, f)
+	}
+	return flags
+}
+
+// genLine generates a command line, giving each flag in a random form of its kind
+func genLine(r *rand.Rand) *genCommand {
+	g := &genCommand{flags: [][]genFlag{genFlags(r)}}
+	if r.IntN(2) == 0 {
+		g.flags = append(g.flags, genFlags(r))
+	}
+	var forms []Form
+	for _, form := range Grammar() {
+		if !form.Bare {
+			forms = append(forms, form)
+		}
+	}
+	for i, flags := range g.flags {
+		if i > 0 {
+			g.argv = append(g.argv, "sub")
+		}
+		for _, j := range r.Perm(len(flags)) {
+			f := &flags[j]
+			var usable []Form
+			for _, form := range forms {
+				if form.Kind == f.kind && (f.short || !form.Short) {
+					usable = append(usable, form)
+				}
+			}
+			form := usable[r.IntN(len(usable))]
+			f.value = genValue(r, f.kind)
+			if !slices.ContainsFunc(form.Tokens, func(tok string) bool { return strings.Contains(tok, FormValue) }) {
+				f.value = "true"
+			}
+			g.argv = append(g.argv, form.Expand(f.name, f.value)...)
+		}
+	}
+	for range r.IntN(3) {
+		g.args = append(g.args, genValue(r, KindValue))
+	}
+	if slices.ContainsFunc(g.args, func(arg string) bool { return strings.HasPrefix(arg, "-") || arg == "sub" }) {
+		g.argv = append(g.argv, Terminator)
+	}
+	g.argv = append(g.argv, g.args...)
+	if len(g.argv) == 0 {
+		g.argv = []string{Terminator} // Parse reads os.Args if it's given nothing
+	}
+	return g
+}
+
+func TestGrammarProperties(t *testing.T) {
+	r := rand.New(rand.NewPCG(2502, 1))
+	for i := range 500 {
+		g := genLine(r)
+		root := g.build()
+		if err := root.Parse(append([]string{}, g.argv...)...); err != nil {
+			t.Fatalf("case %d: Parse(%q): %v", i, g.argv, err)
+		}
+
+		cmd := root
+		for depth, flags := range g.flags {
+			if depth > 0 {
+				cmd = cmd.sub
+			}
+			for _, f := range flags {
+				if got := cmd.Lookup(f.name).Value.String(); got != f.value {
+					t.Errorf("case %d: Parse(%q): --%s = %q, want %q", i, g.argv, f.name, got, f.value)
+				}
+			}
+		}
+		if got := cmd.Args(); !slices.Equal(got, g.args) && len(got)+len(g.args) > 0 {
+			t.Errorf("case %d: Parse(%q): args = %q, want %q", i, g.argv, got, g.args)
+		}
+
+		inv := root.Invocation()
+		argv, err := SplitLine(inv.Quoted(PosixShell))
+		if err != nil || !slices.Equal(argv, inv.Argv()) {
+			t.Fatalf("case %d: SplitLine(%s) = %q, %v, want %q", i, inv.Quoted(PosixShell), argv, err, inv.Argv())
+		}
+		again := g.build()
+		if len(argv) == 1 {
+			argv = append(argv, Terminator)
+		}
+		if err := again.Parse(argv[1:]...); err != nil {
+			t.Fatalf("case %d: reparsing %q: %v", i, argv, err)
+		}
+		if got := again.Invocation(); !reflect.DeepEqual(got, inv) {
+			t.Errorf("case %d: invocation of %q changed on reparsing:\n got %+v\nwant %+v", i, argv, got, inv)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package mandy
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// A FlagGroup is a reusable bundle of related flags, such as a client's connection settings
+type FlagGroup interface {
+	// Register defines the group's flags on c, naming each with Prefixed(prefix, name)
+	Register(c *Command, prefix string)
+}
+
+// Mount registers the group's flags on the command, under names prefixed by prefix and a dash,
+// or under their own names if prefix is empty. It returns an error, rather than panicking,
+// if one of the names is taken or reserved, in which case none of the group's flags, nor the setups
+// and checks it registered, are kept, or if the command is sealed. Other panics raised by the group's
+// Register, which are bugs rather than conflicts, are left to propagate.
+func (c *Command) Mount(prefix string, group FlagGroup) (err error) {
+	if err := c.errSealed("flag group", prefix); err != nil {
+		return err
+	}
+	before := make(map[string]bool, len(c.formal))
+	for name := range c.formal {
+		before[name] = true
+	}
+	output, setups, checks := c.output, len(c.setups), len(c.checks)
+	c.refused = ""
+	defer func() {
+		c.output = output
+		r := recover()
+		if r == nil {
+			return
+		}
+		cause, _ := r.(error)
+		if msg, ok := r.(string); ok && msg != "" && msg == c.refused {
+			cause = fmt.Errorf("%w: %s", ErrConflict, msg)
+		}
+		if !errors.Is(cause, ErrConflict) && !errors.Is(cause, ErrSealed) {
+			panic(r)
+		}
+		for name := range c.formal {
+			if !before[name] {
+				delete(c.formal, name)
+			}
+		}
+		c.setups = c.setups[:setups]
+		c.checks = c.checks[:checks]
+		c.invalidate()
+		err = fmt.Errorf("mounting %q on %s: %w", prefix, c.name, cause)
+	}()
+	c.output = io.Discard // registration panics would otherwise print their message as they fail
+	group.Register(c, prefix)
+	return nil
+}
+
+// Prefixed joins a flag group's prefix to the name of one of its flags
+func Prefixed(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "-" + name
+}
@@ -0,0 +1,110 @@
+package mandy
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// GuessName is the name of the flag defined by GuessFlag
+const GuessName = "guess"
+
+// A GuessPolicy determines what a command without a Main function does with a free argument
+// that names none of its children but is close to the name of exactly one of them
+type GuessPolicy uint8
+
+const (
+	GuessOff GuessPolicy = iota // Leave the argument as it is.
+	GuessAsk                    // Ask whether the child was meant, and run it if so, or without asking given --guess.
+	GuessRun                    // Run the child, after saying so.
+)
+
+// GuessFlag defines a boolean flag, named GuessName, that lets commands, and their descendants,
+// whose Guess is GuessAsk run the child they guess without asking
+func (c *Command) GuessFlag(usage string, short bool) *Flag {
+	if usage == "" {
+		usage = "run misspelled commands as their closest match without asking"
+	}
+	return c.Bool(new(bool), GuessName, false, usage, short)
+}
+
+// guess returns the child that the first of the command's pending arguments is a misspelling of,
+// if the command's Guess policy, and the user, allow it and the command has no Main of its own
+func (c *Command) guess() (*Command, error) {
+	if c.Guess == GuessOff || c.Main != nil || len(c.args) == 0 || strings.HasPrefix(c.args[0], "-") {
+		return nil, nil
+	}
+	var names []string
+	for _, child := range c.children {
+		if !child.hidden() {
+			names = append(names, append([]string{child.name}, child.aliases...)...)
+		}
+	}
+	matches := closest(c.args[0], names)
+	if len(matches) != 1 {
+		return nil, nil
+	}
+	child := c.child(matches[0])
+	if c.Guess == GuessAsk && !c.guessing() {
+		ok, err := c.confirm(fmt.Sprintf("%s: unknown command %q; did you mean %q?", c.name_(), c.args[0], matches[0]))
+		if err != nil || !ok {
+			return nil, err
+		}
+	} else {
+		fmt.Fprintf(c.Output(), "%s: running %q as %q\n", c.name_(), c.args[0], matches[0])
+	}
+	return child, nil
+}
+
+// guessing reports whether the flag defined by GuessFlag was set on the command or an ancestor
+func (c *Command) guessing() bool {
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		if f, ok := cmd.actual[GuessName]; ok && f.Value.String() == "true" {
+			return true
+		}
+	}
+	return false
+}
+
+// closest returns those of the names nearest to name, if any are within a third of its length,
+// or one character, of it; a transposition counts as one change
+func closest(name string, names []string) (out []string) {
+	limit := max(1, len(name)/3)
+	best := limit + 1
+	for _, candidate := range names {
+		switch d := editDistance(name, candidate); {
+		case d > limit:
+		case d < best:
+			best, out = d, []string{candidate}
+		case d == best && !slices.Contains(out, candidate):
+			out = append(out, candidate)
+		}
+	}
+	return out
+}
+
+// editDistance is the optimal string alignment distance between a and b, by rune
+func editDistance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	rows := make([][]int, len(s)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(t)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			rows[i][j] = min(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				rows[i][j] = min(rows[i][j], rows[i-2][j-2]+1)
+			}
+		}
+	}
+	return rows[len(s)][len(t)]
+}
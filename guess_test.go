@@ -0,0 +1,67 @@
+package mandy
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGuess(t *testing.T) {
+	var ran string
+	c := NewCommand("tool", ContinueOnError)
+	c.GuessFlag("", false)
+	for _, name := range []string{"status", "stash", "start"} {
+		c.NewChild(name, "").Main = func(self *Command) error {
+			ran = self.name
+			return nil
+		}
+	}
+	var out bytes.Buffer
+	c.SetOutput(&out)
+	c.SetInput(strings.NewReader("y\n"))
+
+	c.Guess = GuessRun
+	if err := c.Clone().Execute("stauts"); err != nil || ran != "status" {
+		t.Errorf("under GuessRun, stauts ran %q: %v, want status", ran, err)
+	}
+	if !strings.Contains(out.String(), `running "stauts" as "status"`) {
+		t.Errorf("under GuessRun, printed %q", out.String())
+	}
+	ran = ""
+	if err := c.Clone().Execute("stast"); !errors.Is(err, ErrNilMain) || ran != "" {
+		t.Errorf("an ambiguous guess ran %q: %v, want nothing and ErrNilMain", ran, err)
+	}
+
+	c.Guess = GuessAsk
+	if err := c.Clone().Execute("stauts"); !errors.Is(err, ErrNilMain) || ran != "" {
+		t.Errorf("under GuessAsk, without a terminal to ask at, stauts ran %q: %v, want nothing", ran, err)
+	}
+	if err := c.Clone().Execute("--guess", "stauts"); err != nil || ran != "status" {
+		t.Errorf("under GuessAsk, --guess stauts ran %q: %v, want status", ran, err)
+	}
+
+	c.Guess = GuessOff
+	ran = ""
+	if err := c.Clone().Execute("stauts"); !errors.Is(err, ErrNilMain) || ran != "" {
+		t.Errorf("under GuessOff, stauts ran %q: %v", ran, err)
+	}
+}
+
+func TestClosest(t *testing.T) {
+	names := []string{"status", "stash", "start", "log"}
+	for name, want := range map[string][]string{
+		"stauts": {"status"},
+		"stsh":   {"stash"},
+		"lgo":    {"log"},
+		"sta":    nil,
+		"stat":   {"start"},
+		"stast":  {"stash", "start"},
+		"xyz":    nil,
+	} {
+		if got := closest(name, names); !reflect.DeepEqual(got, want) {
+			t.Errorf("closest(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -6,10 +6,15 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"text/template"
 )
 
 const (
 	defaultIndent = "\t"
+
+	// DefaultFooter is the Footer given to new commands.
+	// It expands to the command's URL, if it has one.
+	DefaultFooter = "{{with .URL}}more info @: {{.}}{{end}}"
 )
 
 var (
@@ -37,8 +42,33 @@ type (
 	}
 
 	helpMessage []helpNode
+
+	// footerData holds the variables available to a Command's Footer template
+	footerData struct {
+		URL     string
+		Name    string
+		Version string
+	}
 )
 
+// usageFooter renders the command's Footer template
+// the raw Footer is returned if it fails to parse or execute
+func (c *Command) usageFooter() string {
+	if c.Footer == "" {
+		return ""
+	}
+	tmpl, err := template.New("footer").Parse(c.Footer)
+	if err != nil {
+		return c.Footer
+	}
+	var buf strings.Builder
+	data := footerData{URL: c.url(), Name: c.name, Version: c.Version}
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return c.Footer
+	}
+	return buf.String()
+}
+
 func (i Item[T]) String() string {
 	out := i.Value.String()
 	for _, child := range i.Children {
@@ -118,3 +148,11 @@ func reindent(orig string, depth int) string {
 	}
 	return strings.Join(lines, "\n")
 }
+
+// helpHint suggests the command line printing the command's help, if HelpHint is set and the help flag is defined
+func (c *Command) helpHint() string {
+	if _, ok := c.formal[HelpName]; !c.HelpHint || !ok {
+		return ""
+	}
+	return fmt.Sprintf("run '%s --%s' for usage", c.path(), HelpName)
+}
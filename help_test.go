@@ -1,8 +1,11 @@
 package mandy
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -72,3 +75,239 @@ func TestHelpFile(t *testing.T) {
 		println(node.String())
 	}
 }
+
+func TestUsageChildren(t *testing.T) {
+	root := NewCommand("root", ContinueOnError)
+	root.NewChild("status", "show the working tree status")
+	root.NewChild("log")
+
+	usage := root.defaultUsage()
+	for _, want := range []string{"commands:\n", "\tstatus\tshow the working tree status\n", "\tlog\n"} {
+		if !strings.Contains(usage, want) {
+			t.Errorf("usage %q does not contain %q", usage, want)
+		}
+	}
+}
+
+func TestDisableHelpFlag(t *testing.T) {
+	c := NewCommand("quiet", ContinueOnError)
+	c.DisableHelpFlag()
+	if c.Lookup(HelpName) != nil {
+		t.Fatal("help flag should be removed")
+	}
+	for _, arg := range []string{"--help", "-h"} {
+		if err := c.Parse(arg); !errors.Is(err, ErrHelp) {
+			t.Errorf("%s: expected ErrHelp, got %v", arg, err)
+		}
+	}
+	if c.HelpNeeded() || c.HelpWorthy() || c.HelpWanted() {
+		t.Error("help predicates should be false without a help flag")
+	}
+}
+
+func TestHelpIf(t *testing.T) {
+	var out strings.Builder
+	code := -1
+	c := NewCommand("helpful", ContinueOnError)
+	c.SetOutput(&out)
+	c.SetExit(func(c int) { code = c })
+	child := c.NewChild("sub", "")
+
+	if child.HelpIf(false, "unused") || out.Len() != 0 || code != -1 {
+		t.Fatal("HelpIf(false) should do nothing")
+	}
+	if !child.HelpIf(true, "missing %s", "input") {
+		t.Fatal("HelpIf(true) should report that it triggered")
+	}
+	if got := out.String(); !strings.HasPrefix(got, "missing input\n") || !strings.Contains(got, "sub") {
+		t.Errorf("unexpected output: %q", got)
+	}
+	if code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+
+	out.Reset()
+	child.WarnIf(false, "careful")
+	if out.String() != "careful\n" {
+		t.Errorf("WarnIf wrote %q", out.String())
+	}
+}
+
+func TestCustomUsage(t *testing.T) {
+	c := NewCommand("custom", ContinueOnError)
+	var out strings.Builder
+	c.Usage = func(w io.Writer, c *Command) error {
+		_, err := fmt.Fprintf(w, "usage of %s\n", c.Name())
+		return err
+	}
+	if err := c.WriteUsage(&out); err != nil || out.String() != "usage of custom\n" {
+		t.Errorf("got %q, %v", out.String(), err)
+	}
+
+	c.Usage = UsageFunc(func() string { return "legacy\n" })
+	if got := c.UsageString(); got != "legacy\n" {
+		t.Errorf("shim wrote %q", got)
+	}
+
+	c.Usage = nil
+	if got := c.UsageString(); !strings.HasPrefix(got, "usage:") {
+		t.Errorf("expected the default usage, got %q", got)
+	}
+}
+
+func TestDefaultsOrder(t *testing.T) {
+	c := NewCommand("root", ContinueOnError)
+	for _, name := range []string{"zeta", "alpha", "mid", "beta"} {
+		c.Bool(new(bool), name, false, name, false)
+	}
+	want := c.Defaults()
+	for i := 0; i < 10; i++ {
+		if got := c.Defaults(); got != want {
+			t.Fatalf("Defaults is not deterministic:\n%s\n%s", want, got)
+		}
+	}
+	if strings.Index(want, "alpha") > strings.Index(want, "zeta") {
+		t.Errorf("flags are not sorted:\n%s", want)
+	}
+
+	c.GlobalOptions = true
+	child := c.NewChild("sub", "")
+	child.Bool(new(bool), "mid", false, "shadows the parent's", false)
+	got := child.Defaults()
+	globals := got[strings.Index(got, "global options:"):]
+	if !strings.Contains(globals, "alpha") || strings.Contains(globals, "mid") || strings.Contains(globals, HelpName) {
+		t.Errorf("unexpected global options:\n%s", got)
+	}
+}
+
+func TestSortFlags(t *testing.T) {
+	c := NewCommand("ordered", ContinueOnError)
+	c.SortFlags(false)
+	for _, name := range []string{"zeta", "alpha", "mid"} {
+		c.Bool(new(bool), name, false, name, false)
+	}
+	var names []string
+	c.VisitAll(func(f *Flag) { names = append(names, f.Name) })
+	if got := strings.Join(names, " "); got != HelpName+" zeta alpha mid" {
+		t.Errorf("registration order: got %q", got)
+	}
+	if usage := c.Defaults(); strings.Index(usage, "zeta") > strings.Index(usage, "alpha") {
+		t.Errorf("help should follow registration order:\n%s", usage)
+	}
+
+	c.SortFlags(true)
+	names = nil
+	c.VisitAll(func(f *Flag) { names = append(names, f.Name) })
+	if got := strings.Join(names, " "); got != "alpha "+HelpName+" mid zeta" {
+		t.Errorf("sorted order: got %q", got)
+	}
+}
+
+func TestHelpHint(t *testing.T) {
+	for _, hint := range []bool{false, true} {
+		var out strings.Builder
+		c := NewCommand("tool", ExitOnError)
+		c.SetOutput(&out)
+		c.SetExit(func(int) {})
+		c.HelpHint = hint
+		c.NewChild("serve", "")
+		c.Parse("serve", "--bogus")
+		const want = "run 'tool serve --help' for usage\n"
+		if strings.HasSuffix(out.String(), want) != hint {
+			t.Errorf("HelpHint = %t: output = %q", hint, out.String())
+		}
+	}
+}
+
+func TestGlobalOptionsParse(t *testing.T) {
+	var verbose, quiet bool
+	var level int
+	root := NewCommand("root", ContinueOnError)
+	root.GlobalOptions = true
+	root.Bool(&verbose, "verbose", false, "be loud", true)
+	root.Int(&level, "level", 0, "how much", false)
+	root.Bool(&quiet, "quiet", true, "be quiet", false).Negatable()
+	child := root.NewChild("sub")
+	child.Main = func(*Command) error { return nil }
+
+	if err := root.Execute("sub", "-v", "--level", "3", "--no-quiet"); err != nil {
+		t.Fatal(err)
+	}
+	if !verbose || level != 3 || quiet {
+		t.Errorf("verbose = %v, level = %d, quiet = %v; want true, 3, false", verbose, level, quiet)
+	}
+	if _, set := root.actual["level"]; !set {
+		t.Error("the inherited flag was not recorded as set on its owner")
+	}
+
+	child.GlobalOptions = false
+	if err := root.Execute("sub", "--level=4"); err == nil {
+		t.Error("ancestors' flags were accepted without GlobalOptions")
+	}
+}
+
+func TestUsageFooter(t *testing.T) {
+	for _, tc := range []struct {
+		footer, url, version string
+		want                 string
+	}{
+		{DefaultFooter, "https://example.com/tool", "", "more info @: https://example.com/tool"},
+		{DefaultFooter, "", "", ""},
+		{"{{.Name}} {{.Version}}: {{.URL}}", "https://example.com/tool", "v1.2", "tool v1.2: https://example.com/tool"},
+		{"", "https://example.com/tool", "v1.2", ""},
+		{"{{.Name", "", "", "{{.Name"},
+		{"{{.Missing}}", "", "", "{{.Missing}}"},
+	} {
+		c := NewCommand("tool", ContinueOnError)
+		c.Footer, c.URL, c.Version = tc.footer, tc.url, tc.version
+		if got := c.usageFooter(); got != tc.want {
+			t.Errorf("footer %q: rendered %q, want %q", tc.footer, got, tc.want)
+		}
+		if tc.want != "" && !strings.HasSuffix(c.UsageString(), tc.want) {
+			t.Errorf("footer %q: usage doesn't end with it:\n%s", tc.footer, c.UsageString())
+		}
+	}
+
+	c := NewCommand("tool", ContinueOnError)
+	c.Footer, c.URL, c.Version = "{{.Name}} {{.Version}} {{.URL}}", "https://example.com/tool", "v1.2"
+	if got, want := c.NewChild("sub").usageFooter(), "sub v1.2 https://example.com/tool"; got != want {
+		t.Errorf("child rendered %q, want %q", got, want)
+	}
+}
+
+func TestEnvURL(t *testing.T) {
+	for _, tc := range []struct {
+		env     map[string]string
+		want    string
+		wantErr bool
+	}{
+		{map[string]string{}, "", false},
+		{map[string]string{"DEVELOPER": "kendfss"}, "", false},
+		{map[string]string{"REPO_HOST": "https://github.com", "DEVELOPER": "kendfss"}, "https://github.com/kendfss/tool", false},
+		{map[string]string{"REPO_HOST": "https://git.example.com"}, "https://git.example.com/tool", false},
+		{map[string]string{"REPO_HOST": "://bad", "DEVELOPER": "kendfss"}, "", true},
+	} {
+		got, err := envURL("tool", MapEnviron(tc.env))
+		if got != tc.want || (err != nil) != tc.wantErr {
+			t.Errorf("%v: got %q, %v; want %q, error %t", tc.env, got, err, tc.want, tc.wantErr)
+		}
+
+		c := NewCommand("tool", ContinueOnError)
+		c.SetEnviron(MapEnviron(tc.env))
+		if got := c.url(); got != tc.want {
+			t.Errorf("%v: command's URL is %q, want %q", tc.env, got, tc.want)
+		}
+	}
+
+	SetURLResolver(func(name string) string { return "https://example.com/" + name })
+	defer SetURLResolver(nil)
+	if got := NewCommand("tool", ContinueOnError).url(); got != "https://example.com/tool" {
+		t.Errorf("custom resolver gave %q", got)
+	}
+	SetURLResolver(nil)
+	c := NewCommand("tool", ContinueOnError)
+	c.SetEnviron(MapEnviron(map[string]string{"REPO_HOST": "https://github.com"}))
+	if got := c.url(); got != "https://github.com/tool" {
+		t.Errorf("restored resolver gave %q", got)
+	}
+}
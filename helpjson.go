@@ -0,0 +1,100 @@
+package mandy
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+type (
+	// A HelpModel holds everything a command's help message is rendered from, as written by WriteHelp in HelpJSON,
+	// so that external renderers, such as web docs and TUIs, can present it natively. Hidden children are omitted,
+	// and secret defaults are redacted.
+	HelpModel struct {
+		Name     string      `json:"name"` // the command's path
+		Usage    string      `json:"usage"`
+		Summary  string      `json:"summary,omitempty"`
+		Version  string      `json:"version,omitempty"`
+		URL      string      `json:"url,omitempty"`
+		Aliases  []string    `json:"aliases,omitempty"`
+		Flags    []HelpFlag  `json:"flags"`
+		Globals  []HelpFlag  `json:"globals,omitempty"`  // the flags inherited from ancestors, if GlobalOptions is set
+		Children []HelpModel `json:"children,omitempty"` // the children's complete models
+		Examples []string    `json:"examples,omitempty"`
+		Footer   string      `json:"footer,omitempty"`
+	}
+
+	// A HelpFlag describes a flag in a HelpModel
+	HelpFlag struct {
+		Name        string `json:"name"`
+		Short       string `json:"short,omitempty"` // the one-letter abbreviation, if the flag has one
+		Arg         string `json:"arg,omitempty"`   // the name of the flag's value, as found by UnquoteDescription, empty for boolean flags
+		Description string `json:"description"`     // including the conventions the value follows, as in the usage message
+		Default     string `json:"default,omitempty"`
+		Env         string `json:"env,omitempty"`
+		Required    bool   `json:"required,omitempty"`
+		Negatable   bool   `json:"negatable,omitempty"`
+		Secret      bool   `json:"secret,omitempty"`
+	}
+)
+
+// HelpModel returns the data the command's help message is rendered from
+func (c *Command) HelpModel() HelpModel {
+	m := HelpModel{
+		Name:    c.path(),
+		Usage:   strings.TrimSpace(c.format()),
+		Summary: c.Summary,
+		Version: c.Version,
+		URL:     c.url(),
+		Aliases: c.aliases,
+		Flags:   []HelpFlag{},
+		Footer:  c.usageFooter(),
+	}
+	for _, flag := range c.orderFlags(c.formal) {
+		m.Flags = append(m.Flags, helpFlag(flag, flag.DefValue))
+	}
+	if c.GlobalOptions {
+		for _, flag := range c.globals() {
+			m.Globals = append(m.Globals, helpFlag(flag, c.inheritedDefault(flag)))
+		}
+	}
+	for _, child := range c.children {
+		if !child.hidden() {
+			m.Children = append(m.Children, child.HelpModel())
+		}
+	}
+	if example := strings.TrimRight(c.Example, "\n"); example != "" {
+		m.Examples = strings.Split(example, "\n")
+	}
+	return m
+}
+
+// helpFlag describes the flag, whose default is given, for a HelpModel
+func helpFlag(flag *Flag, def string) HelpFlag {
+	name, usage := UnquoteDescription(flag)
+	f := *flag
+	f.Description = usage
+	h := HelpFlag{
+		Name:        flag.Name,
+		Arg:         name,
+		Description: f.description(),
+		Env:         flag.env,
+		Required:    flag.required,
+		Negatable:   flag.negatable && flag.Value.IsBool(),
+		Secret:      flag.IsSecret(),
+	}
+	if flag.Short {
+		h.Short = flag.Name[:1]
+	}
+	if !flag.hideDefault && !flag.required {
+		h.Default = flag.redact(def)
+	}
+	return h
+}
+
+// writeHelpJSON writes the command's HelpModel to w as indented JSON
+func (c *Command) writeHelpJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(c.HelpModel())
+}
@@ -0,0 +1,97 @@
+package mandy
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// HTTPClient is a FlagGroup of the connection settings common to API clients:
+// --timeout, --proxy, --insecure-skip-verify, --ca-cert, and a repeatable --header.
+// Its zero value is ready to Mount; set fields before doing so to change the defaults.
+type HTTPClient struct {
+	Timeout            time.Duration
+	Proxy              string   // proxy url; if empty, the environment's proxy settings are used
+	InsecureSkipVerify bool     // skip TLS certificate verification
+	CACert             string   // path to a PEM file of extra certificate authorities
+	Headers            []string // "Name: value" pairs sent with every request
+}
+
+// Register defines the group's flags on c
+func (h *HTTPClient) Register(c *Command, prefix string) {
+	c.Duration(&h.Timeout, Prefixed(prefix, "timeout"), h.Timeout, "time limit for each request, 0 for none", false)
+	c.String(&h.Proxy, Prefixed(prefix, "proxy"), h.Proxy, "proxy `url`, instead of the environment's", false)
+	c.Bool(&h.InsecureSkipVerify, Prefixed(prefix, "insecure-skip-verify"), h.InsecureSkipVerify, "do not verify TLS certificates", false)
+	c.String(&h.CACert, Prefixed(prefix, "ca-cert"), h.CACert, "`file` of PEM encoded certificate authorities to trust", false)
+	c.Var(newSliceValue(h.Headers, &h.Headers, parseHeader, func(s string) string { return s }),
+		Prefixed(prefix, "header"), `"Name: value" header to send with every request`, false).Separator("")
+}
+
+// parseHeader checks that s is a "Name: value" pair
+func parseHeader(s string) (string, error) {
+	name, _, ok := strings.Cut(s, ":")
+	if !ok || strings.TrimSpace(name) == "" {
+		return "", fmt.Errorf("%w: header %q should look like \"Name: value\"", errParse, s)
+	}
+	return s, nil
+}
+
+// Client returns an *http.Client configured by the group's settings
+func (h *HTTPClient) Client() (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if h.Proxy != "" {
+		proxy, err := url.Parse(h.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("%w: proxy: %v", errParse, err)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+	if h.InsecureSkipVerify || h.CACert != "" {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: h.InsecureSkipVerify}
+	}
+	if h.CACert != "" {
+		pem, err := os.ReadFile(h.CACert)
+		if err != nil {
+			return nil, err
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%w: no certificates found in %s", errParse, h.CACert)
+		}
+		transport.TLSClientConfig.RootCAs = pool
+	}
+	header := make(http.Header)
+	for _, pair := range h.Headers {
+		name, value, _ := strings.Cut(pair, ":")
+		header.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+	var rt http.RoundTripper = transport
+	if len(header) > 0 {
+		rt = headerTransport{header: header, next: transport}
+	}
+	return &http.Client{Timeout: h.Timeout, Transport: rt}, nil
+}
+
+// headerTransport adds headers to requests that do not already set them
+type headerTransport struct {
+	header http.Header
+	next   http.RoundTripper
+}
+
+func (t headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for name, values := range t.header {
+		if req.Header.Get(name) == "" {
+			req.Header[name] = values
+		}
+	}
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,77 @@
+package mandy
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHTTPClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Header.Get("X-Token") + "|" + r.Header.Get("Accept")))
+	}))
+	defer server.Close()
+
+	var settings HTTPClient
+	c := NewCommand("api", ContinueOnError)
+	if err := c.Mount("api", &settings); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Parse("--api-timeout", "5s", "--api-header", "X-Token: a,b", "--api-header", "Accept: text/plain"); err != nil {
+		t.Fatal(err)
+	}
+	client, err := settings.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.Timeout != 5*time.Second {
+		t.Errorf("timeout: %v", client.Timeout)
+	}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body := make([]byte, 64)
+	n, _ := resp.Body.Read(body)
+	if got := string(body[:n]); got != "a,b|text/plain" {
+		t.Errorf("headers received: %q", got)
+	}
+
+	if err := c.Set("api-header", "no colon"); err == nil {
+		t.Error("malformed headers should be rejected")
+	}
+}
+
+func TestMountConflict(t *testing.T) {
+	c := NewCommand("api", ContinueOnError)
+	c.String(new(string), "proxy", "", "", false)
+	if err := c.Mount("", new(HTTPClient)); !errors.Is(err, ErrConflict) {
+		t.Errorf("expected ErrConflict, got %v", err)
+	}
+	if c.Lookup("timeout") != nil {
+		t.Error("a failed mount should leave no flags behind")
+	}
+
+	c.Reserve("api-timeout")
+	if err := c.Mount("api", new(HTTPClient)); !errors.Is(err, ErrConflict) {
+		t.Errorf("mounting on a reserved name: expected ErrConflict, got %v", err)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("a group's bug was turned into an error")
+		}
+	}()
+	c.Mount("buggy", buggyGroup{})
+}
+
+// buggyGroup registers a flag, then dereferences nil
+type buggyGroup struct{ p *string }
+
+func (g buggyGroup) Register(c *Command, prefix string) {
+	c.String(new(string), Prefixed(prefix, "name"), "", "", false)
+	c.String(g.p, Prefixed(prefix, "other"), *g.p, "", false)
+}
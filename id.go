@@ -0,0 +1,208 @@
+package mandy
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type (
+	// A UUID is an RFC 4122 universally unique identifier
+	UUID [16]byte
+
+	// A ULID is a universally unique lexicographically sortable identifier
+	ULID [16]byte
+)
+
+// ParseUUID parses UUIDs in their canonical form, as well as wrapped in braces,
+// prefixed by "urn:uuid:", or without hyphens. Other than the nil UUID, only
+// UUIDs with the RFC 4122 variant are accepted.
+func ParseUUID(s string) (u UUID, err error) {
+	orig := s
+	s = strings.TrimPrefix(strings.ToLower(s), "urn:uuid:")
+	if strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") {
+		s = s[1 : len(s)-1]
+	}
+	if len(s) == 36 {
+		if s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+			return UUID{}, fmt.Errorf("%w: malformed uuid %q", errParse, orig)
+		}
+		s = strings.ReplaceAll(s, "-", "")
+	}
+	if len(s) != 32 {
+		return UUID{}, fmt.Errorf("%w: uuid %q should have 32 hex digits", errParse, orig)
+	}
+	if _, err := hex.Decode(u[:], []byte(s)); err != nil {
+		return UUID{}, fmt.Errorf("%w: malformed uuid %q", errParse, orig)
+	}
+	if u != (UUID{}) && (u[8]&0xc0 != 0x80 || u.Version() == 0) {
+		return UUID{}, fmt.Errorf("%w: %q is not an RFC 4122 uuid", errParse, orig)
+	}
+	return u, nil
+}
+
+// Version reports the uuid's version number
+func (u UUID) Version() int {
+	return int(u[6] >> 4)
+}
+
+// String formats the uuid canonically, eg "123e4567-e89b-12d3-a456-426614174000"
+func (u UUID) String() string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return string(buf[:])
+}
+
+// crockford is the alphabet used to encode ULIDs
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ParseULID parses a 26 character ulid, ignoring case and reading I and L as 1 and O as 0
+func ParseULID(s string) (u ULID, err error) {
+	if len(s) != 26 {
+		return ULID{}, fmt.Errorf("%w: ulid %q should have 26 characters", errParse, s)
+	}
+	// the first character only carries 3 bits
+	if s[0] > '7' {
+		return ULID{}, fmt.Errorf("%w: ulid %q overflows 128 bits", errRange, s)
+	}
+	var acc uint64
+	bits, n := 0, len(u)-1
+	for i := len(s) - 1; i >= 0; i-- {
+		d := strings.IndexByte(crockford, crockfordNormal(s[i]))
+		if d < 0 {
+			return ULID{}, fmt.Errorf("%w: %q is not a valid ulid character", errParse, s[i])
+		}
+		acc |= uint64(d) << bits
+		for bits += 5; bits >= 8 && n >= 0; bits -= 8 {
+			u[n] = byte(acc)
+			acc >>= 8
+			n--
+		}
+	}
+	if n >= 0 {
+		u[n] = byte(acc)
+	}
+	return u, nil
+}
+
+// crockfordNormal maps alternative spellings onto the crockford alphabet
+func crockfordNormal(c byte) byte {
+	if 'a' <= c && c <= 'z' {
+		c -= 'a' - 'A'
+	}
+	switch c {
+	case 'I', 'L':
+		return '1'
+	case 'O':
+		return '0'
+	}
+	return c
+}
+
+// String formats the ulid as 26 upper case characters
+func (u ULID) String() string {
+	var buf [26]byte
+	var acc uint64
+	bits, n := 0, len(u)-1
+	for i := len(buf) - 1; i >= 0; i-- {
+		for bits < 5 && n >= 0 {
+			acc |= uint64(u[n]) << bits
+			bits += 8
+			n--
+		}
+		buf[i] = crockford[acc&31]
+		acc >>= 5
+		bits -= 5
+	}
+	return string(buf[:])
+}
+
+// Time reports the timestamp encoded in the ulid's first 48 bits
+func (u ULID) Time() time.Time {
+	var ms int64
+	for _, b := range u[:6] {
+		ms = ms<<8 | int64(b)
+	}
+	return time.UnixMilli(ms)
+}
+
+// -- UUID Value
+type uuidValue UUID
+
+func newUUIDValue(val UUID, p *UUID) *uuidValue {
+	*p = val
+	return (*uuidValue)(p)
+}
+
+func (u *uuidValue) Set(s string) error {
+	v, err := ParseUUID(s)
+	if err != nil {
+		return err
+	}
+	*u = uuidValue(v)
+	return nil
+}
+
+func (u *uuidValue) Get() any       { return UUID(*u) }
+func (u *uuidValue) String() string { return UUID(*u).String() }
+func (u *uuidValue) IsBool() bool   { return false }
+
+// Complete suggests the canonical form of a complete, but unconventionally written, uuid
+func (u *uuidValue) Complete(prefix string) []string {
+	if v, err := ParseUUID(prefix); err == nil && v.String() != prefix {
+		return []string{v.String()}
+	}
+	return nil
+}
+
+// -- ULID Value
+type ulidValue ULID
+
+func newULIDValue(val ULID, p *ULID) *ulidValue {
+	*p = val
+	return (*ulidValue)(p)
+}
+
+func (u *ulidValue) Set(s string) error {
+	v, err := ParseULID(s)
+	if err != nil {
+		return err
+	}
+	*u = ulidValue(v)
+	return nil
+}
+
+func (u *ulidValue) Get() any       { return ULID(*u) }
+func (u *ulidValue) String() string { return ULID(*u).String() }
+func (u *ulidValue) IsBool() bool   { return false }
+
+// Complete suggests the canonical form of a complete, but unconventionally written, ulid
+func (u *ulidValue) Complete(prefix string) []string {
+	if v, err := ParseULID(prefix); err == nil && v.String() != prefix {
+		return []string{v.String()}
+	}
+	return nil
+}
+
+// UUID defines a UUID flag with specified name, default value, and usage string.
+// The argument p points to a UUID variable in which to store the value of the flag.
+// The flag accepts a value acceptable to ParseUUID.
+func (c *Command) UUID(p *UUID, name string, value UUID, usage string, short bool) *Flag {
+	return c.Var(newUUIDValue(value, p), name, usage, short)
+}
+
+// ULID defines a ULID flag with specified name, default value, and usage string.
+// The argument p points to a ULID variable in which to store the value of the flag.
+// The flag accepts a value acceptable to ParseULID.
+func (c *Command) ULID(p *ULID, name string, value ULID, usage string, short bool) *Flag {
+	return c.Var(newULIDValue(value, p), name, usage, short)
+}
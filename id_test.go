@@ -0,0 +1,59 @@
+package mandy
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseUUID(t *testing.T) {
+	const canon = "123e4567-e89b-12d3-a456-426614174000"
+	for _, in := range []string{canon, "{123E4567-E89B-12D3-A456-426614174000}", "urn:uuid:" + canon, "123e4567e89b12d3a456426614174000"} {
+		u, err := ParseUUID(in)
+		if err != nil {
+			t.Errorf("ParseUUID(%q): %v", in, err)
+			continue
+		}
+		if u.String() != canon {
+			t.Errorf("ParseUUID(%q) = %s, want %s", in, u, canon)
+		}
+	}
+	for _, bad := range []string{"", "123e4567-e89b-12d3-c456-426614174000", "123e4567-e89b-02d3-a456-426614174000", "123e4567_e89b_12d3_a456_426614174000"} {
+		if _, err := ParseUUID(bad); err == nil {
+			t.Errorf("ParseUUID(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestParseULID(t *testing.T) {
+	const canon = "01ARZ3NDEKTSV4RRFFQ69G5FAV"
+	u, err := ParseULID("01arz3ndektsv4rrffq69g5fav")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.String() != canon {
+		t.Errorf("got %s, want %s", u, canon)
+	}
+	if want := time.UnixMilli(1469922850259); !u.Time().Equal(want) {
+		t.Errorf("Time() = %v, want %v", u.Time(), want)
+	}
+	for _, bad := range []string{"", "81ARZ3NDEKTSV4RRFFQ69G5FAV", "01ARZ3NDEKTSV4RRFFQ69G5FAU!"[:26] + "!"} {
+		if _, err := ParseULID(bad); err == nil {
+			t.Errorf("ParseULID(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestUUIDFlag(t *testing.T) {
+	var id UUID
+	c := NewCommand("test", ContinueOnError)
+	f := c.UUID(&id, "id", UUID{}, "resource id", false)
+	if err := c.Parse("--id", "123E4567E89B12D3A456426614174000"); err != nil {
+		t.Fatal(err)
+	}
+	if got := f.Value.Get().(UUID).String(); got != "123e4567-e89b-12d3-a456-426614174000" {
+		t.Errorf("got %s", got)
+	}
+	if got := f.Completions("123E4567E89B12D3A456426614174000"); len(got) != 1 || got[0] != id.String() {
+		t.Errorf("unexpected completions %q", got)
+	}
+}
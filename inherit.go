@@ -0,0 +1,69 @@
+package mandy
+
+// OverrideDefault gives the named flag of an ancestor another default where the command, or one of its
+// descendants, is dispatched to, as when a root's --format defaults to table but its export child's to json.
+// The flag's definition is unchanged: it is still given before the command's name, and parsed by the ancestor,
+// whose value holds the overriding default, unless it was set, once the command is dispatched to.
+// The command's usage shows the default it gives. It panics if no ancestor defines the flag,
+// or if the value is invalid for it.
+func (c *Command) OverrideDefault(name, value string) *Command {
+	_, flag := c.ancestorFlag(name)
+	if flag == nil {
+		panic(c.sprintf("cannot override the default of --%s, which no ancestor of %s defines", name, c.name_()))
+	}
+	v := cloneValue(flag.Value)
+	if s, ok := unwrap(v).(settler); ok {
+		s.settle()
+	}
+	if err := v.Set(value); err != nil {
+		panic(c.sprintf("invalid default for --%s in %s: %s: %v", name, c.name_(), value, err))
+	}
+	if c.defaults == nil {
+		c.defaults = make(map[string]string)
+	}
+	c.defaults[name] = value
+	c.invalidate()
+	return c
+}
+
+// ancestorFlag returns the nearest of the command's ancestors defining the named flag, and the flag
+func (c *Command) ancestorFlag(name string) (*Command, *Flag) {
+	for cmd := c.parent; cmd != nil; cmd = cmd.parent {
+		if flag, ok := cmd.formal[name]; ok {
+			return cmd, flag
+		}
+	}
+	return nil, nil
+}
+
+// inheritedDefault returns the default of an ancestor's flag where the command is dispatched to,
+// which is the one given by the nearest command overriding it, if any does
+func (c *Command) inheritedDefault(flag *Flag) string {
+	for cmd := c; cmd != nil && cmd.formal[flag.Name] != flag; cmd = cmd.parent {
+		if value, ok := cmd.defaults[flag.Name]; ok {
+			return value
+		}
+	}
+	return flag.DefValue
+}
+
+// applyDefaults gives the ancestors' flags that have not been set the defaults the command overrides
+func (c *Command) applyDefaults() error {
+	for name, value := range c.defaults {
+		owner, flag := c.ancestorFlag(name)
+		if _, set := owner.actual[name]; set {
+			continue
+		}
+		s, settles := unwrap(flag.Value).(settler)
+		if settles {
+			s.settle()
+		}
+		if err := flag.Value.Set(value); err != nil {
+			return err
+		}
+		if settles {
+			s.settle()
+		}
+	}
+	return nil
+}
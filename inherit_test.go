@@ -0,0 +1,62 @@
+package mandy
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOverrideDefault(t *testing.T) {
+	var format string
+	var ran string
+	c := NewCommand("tool", ContinueOnError)
+	c.GlobalOptions = true
+	c.Enum(&format, "format", "table", "output format", false, "table", "json", "yaml")
+	main := func(self *Command) error {
+		_, flag := self.ancestorFlag("format")
+		ran = self.name + " " + flag.Value.String()
+		return nil
+	}
+	c.NewChild("list", "").Main = main
+	export := c.NewChild("export", "").OverrideDefault("format", "json")
+	export.Main = main
+	export.NewChild("all", "").Main = main
+	export.NewChild("raw", "").OverrideDefault("format", "yaml").Main = main
+
+	if !strings.Contains(export.UsageString(), "[default: json]") || !strings.Contains(c.UsageString(), "[default: table]") {
+		t.Errorf("usages show the wrong defaults:\n%s\n%s", export.UsageString(), c.UsageString())
+	}
+	for _, tc := range []struct{ args, want string }{
+		{"list", "list table"},
+		{"export", "export json"},
+		{"--format yaml export", "export yaml"},
+	} {
+		if err := c.Clone().Execute(strings.Fields(tc.args)...); err != nil || ran != tc.want {
+			t.Errorf("%s ran %q: %v, want %q", tc.args, ran, err, tc.want)
+		}
+	}
+	clone := c.Clone()
+	if err := clone.Execute("export", "raw"); err != nil || clone.Lookup("format").Value.String() != "yaml" {
+		t.Errorf("export raw left --format %s: %v, want yaml", clone.Lookup("format").Value, err)
+	}
+	clone = c.Clone()
+	if err := clone.Execute("export", "all"); err != nil || clone.Lookup("format").Value.String() != "json" {
+		t.Errorf("export all left --format %s: %v, want json", clone.Lookup("format").Value, err)
+	}
+	if c.Lookup("format").DefValue != "table" {
+		t.Errorf("the flag's own default became %q", c.Lookup("format").DefValue)
+	}
+
+	for name, fn := range map[string]func(){
+		"unknown flag":  func() { export.OverrideDefault("missing", "x") },
+		"invalid value": func() { export.OverrideDefault("format", "xml") },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("OverrideDefault with an %s didn't panic", name)
+				}
+			}()
+			fn()
+		}()
+	}
+}
@@ -0,0 +1,207 @@
+package mandy
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// A Decoder wraps a compressed stream in a reader of its decompressed contents
+type Decoder func(r io.Reader) (io.ReadCloser, error)
+
+// decoding associates a compression format's file extension and magic number with its Decoder
+type decoding struct {
+	ext     string
+	magic   []byte
+	decoder Decoder
+}
+
+var (
+	decodingsMu sync.RWMutex
+	// decodings are the formats Inputs decompress; zstd, which has no decoder in the standard library,
+	// is left to RegisterDecoder
+	decodings = []decoding{
+		{".gz", []byte{0x1f, 0x8b}, func(r io.Reader) (io.ReadCloser, error) { return gzip.NewReader(r) }},
+		{".bz2", []byte("BZh"), func(r io.Reader) (io.ReadCloser, error) { return io.NopCloser(bzip2.NewReader(r)), nil }},
+	}
+)
+
+// RegisterDecoder makes Inputs decompress files with the given extension, or starting with
+// the given magic number, using dec. It replaces any decoder registered for the extension,
+// and a nil dec unregisters it; this is how zstd support, for which the standard library
+// has no decoder, is added.
+func RegisterDecoder(ext string, magic []byte, dec Decoder) {
+	decodingsMu.Lock()
+	defer decodingsMu.Unlock()
+	for i, d := range decodings {
+		if d.ext == ext {
+			if dec == nil {
+				decodings = append(decodings[:i], decodings[i+1:]...)
+			} else {
+				decodings[i] = decoding{ext, magic, dec}
+			}
+			return
+		}
+	}
+	if dec != nil {
+		decodings = append(decodings, decoding{ext, magic, dec})
+	}
+}
+
+// Input is the source named by an InFile flag: a file path, or "-" for the command's Input,
+// or os.Stdin if the Input belongs to no flag.
+// It is an io.ReadCloser that opens its source when first read from, or when Open is called,
+// and transparently decompresses gzip and bzip2 sources, along with those of registered Decoders.
+// The format is chosen by the path's extension or, failing that, the source's magic number.
+type Input struct {
+	Path string
+	Raw  bool // read the source as is, without decompressing it
+	r    io.Reader
+	c    []io.Closer // closed in order by Close
+	cmd  *Command    // the command of the flag naming the input, whose Input is "-"
+}
+
+// IsStdin reports whether the input comes from stdin
+func (in *Input) IsStdin() bool {
+	return in.Path == "-"
+}
+
+// Open opens and, if need be, sets up the decompression of the source, if it is not already open
+func (in *Input) Open() error {
+	if in.r != nil {
+		return nil
+	}
+	var src io.Reader = os.Stdin
+	if in.cmd != nil {
+		src = in.cmd.Input()
+	}
+	if !in.IsStdin() {
+		if in.Path == "" {
+			return fmt.Errorf("%w: no input path", errParse)
+		}
+		f, err := os.Open(in.Path)
+		if err != nil {
+			return err
+		}
+		src, in.c = f, []io.Closer{f}
+	}
+	if in.Raw {
+		in.r = src
+		return nil
+	}
+	buffered := bufio.NewReader(src)
+	d, ok := in.decoding(buffered)
+	if !ok {
+		in.r = buffered
+		return nil
+	}
+	r, err := d.decoder(buffered)
+	if err != nil {
+		in.Close()
+		return fmt.Errorf("%s: %w", in.Path, err)
+	}
+	in.r, in.c = r, append([]io.Closer{r}, in.c...)
+	return nil
+}
+
+// decoding finds the format of the source by its path's extension or, if no format claims the extension,
+// by peeking at its magic number
+func (in *Input) decoding(src *bufio.Reader) (decoding, bool) {
+	decodingsMu.RLock()
+	defer decodingsMu.RUnlock()
+	ext := strings.ToLower(filepath.Ext(in.Path))
+	for _, d := range decodings {
+		if d.ext == ext {
+			return d, true
+		}
+	}
+	for _, d := range decodings {
+		if d.sniffs(src) {
+			return d, true
+		}
+	}
+	return decoding{}, false
+}
+
+// sniffs reports whether the source starts with the format's magic number
+func (d decoding) sniffs(src *bufio.Reader) bool {
+	if len(d.magic) == 0 {
+		return false
+	}
+	if head, _ := src.Peek(len(d.magic)); !bytes.Equal(head, d.magic) {
+		return false
+	}
+	if d.ext != ".bz2" {
+		return true
+	}
+	// "BZh" is short enough to begin text, so the block size, 1 to 9, and the first block's magic must follow
+	head, _ := src.Peek(10)
+	return len(head) == 10 && '1' <= head[3] && head[3] <= '9' && string(head[4:]) == "1AY&SY"
+}
+
+func (in *Input) Read(p []byte) (int, error) {
+	if err := in.Open(); err != nil {
+		return 0, err
+	}
+	return in.r.Read(p)
+}
+
+// Close closes the source and its decompressor. Stdin is left open.
+func (in *Input) Close() (err error) {
+	for _, c := range in.c {
+		if cerr := c.Close(); err == nil {
+			err = cerr
+		}
+	}
+	in.r, in.c = nil, nil
+	return
+}
+
+// -- Input Value
+type inputValue struct {
+	p *Input
+}
+
+func newInputValue(val string, p *Input, c *Command) *inputValue {
+	p.Path, p.cmd = val, c
+	return &inputValue{p: p}
+}
+
+func (i *inputValue) Set(s string) error {
+	if s == "" {
+		return fmt.Errorf("%w: empty input path", errParse)
+	}
+	i.p.Path = s
+	return nil
+}
+
+func (i *inputValue) Get() any { return i.p }
+
+func (i *inputValue) String() string {
+	if i.p == nil {
+		return ""
+	}
+	return i.p.Path
+}
+
+func (i *inputValue) IsBool() bool { return false }
+
+func (i *inputValue) clone() Getter {
+	p := *i.p
+	p.r, p.c = nil, nil
+	return &inputValue{p: &p}
+}
+
+// InFile defines an Input flag with specified name, default path, and usage string.
+// The argument p points to an Input, whose options are kept, in which to store the flag's path.
+// A path of "-" means the command's Input. Nothing is opened until the Input is read from or opened.
+func (c *Command) InFile(p *Input, name string, value string, usage string, short bool) *Flag {
+	return c.Var(newInputValue(value, p, c), name, usage, short)
+}
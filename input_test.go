@@ -0,0 +1,115 @@
+package mandy
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, compress bool) string {
+		path := filepath.Join(dir, name)
+		f, err := os.Create(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		var w io.WriteCloser = f
+		if compress {
+			w = gzip.NewWriter(f)
+			defer w.Close()
+		}
+		io.WriteString(w, "contents")
+		return path
+	}
+
+	for _, test := range []struct {
+		path string
+		raw  bool
+		want string
+	}{
+		{write("plain.txt", false), false, "contents"},
+		{write("data.gz", true), false, "contents"},
+		{write("sniffed.bin", true), false, "contents"},
+	} {
+		in := Input{Raw: test.raw}
+		c := NewCommand("cat", ContinueOnError)
+		c.InFile(&in, "in", "-", "file to read", false)
+		if !in.IsStdin() {
+			t.Errorf("default should be stdin, got %q", in.Path)
+		}
+		if err := c.Parse("--in", test.path); err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(&in)
+		if err != nil {
+			t.Errorf("%s: %v", test.path, err)
+		} else if string(data) != test.want {
+			t.Errorf("%s: got %q", test.path, data)
+		}
+		if err := in.Close(); err != nil {
+			t.Error(err)
+		}
+	}
+
+	zst := filepath.Join(dir, "data.zst")
+	os.WriteFile(zst, []byte{0x28, 0xb5, 0x2f, 0xfd}, 0o600)
+	if data, err := io.ReadAll(&Input{Path: zst}); err != nil || len(data) != 4 {
+		t.Errorf("unregistered formats should be read as they are, got %q, %v", data, err)
+	}
+	RegisterDecoder(".zst", []byte{0x28, 0xb5, 0x2f, 0xfd}, func(r io.Reader) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader("decoded")), nil
+	})
+	defer RegisterDecoder(".zst", nil, nil)
+	if data, err := io.ReadAll(&Input{Path: zst}); err != nil || string(data) != "decoded" {
+		t.Errorf("registered decoder: got %q, %v", data, err)
+	}
+}
+
+func TestSniffBzip2(t *testing.T) {
+	// bzip2 -9 of "contents"
+	compressed := []byte{
+		0x42, 0x5a, 0x68, 0x39, 0x31, 0x41, 0x59, 0x26, 0x53, 0x59, 0xc4, 0xaa,
+		0x16, 0x4c, 0x00, 0x00, 0x00, 0x01, 0x80, 0x0a, 0x01, 0x8c, 0x00, 0x20,
+		0x00, 0x30, 0xc0, 0x08, 0x34, 0xf2, 0x0a, 0x1e, 0xe5, 0x17, 0x72, 0x45,
+		0x38, 0x50, 0x90, 0xc4, 0xaa, 0x16, 0x4c,
+	}
+	dir := t.TempDir()
+	for _, test := range []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"sniffed.bin", compressed, "contents"},
+		{"notes.txt", []byte("BZh, said the bee"), "BZh, said the bee"},
+		{"sizes.txt", []byte("BZh91AY&SY"[:4] + " blocks"), "BZh9 blocks"},
+	} {
+		path := filepath.Join(dir, test.name)
+		if err := os.WriteFile(path, test.data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if data, err := io.ReadAll(&Input{Path: path}); err != nil || string(data) != test.want {
+			t.Errorf("%s: got %q, %v, want %q", test.name, data, err, test.want)
+		}
+	}
+}
+
+func TestInFileStdin(t *testing.T) {
+	var in Input
+	c := NewCommand("cat", ContinueOnError)
+	c.SetInput(strings.NewReader("from the command"))
+	c.InFile(&in, "in", "-", "what to read", true)
+	if data, err := io.ReadAll(&in); err != nil || string(data) != "from the command" {
+		t.Errorf("read %q from the command's input, %v", data, err)
+	}
+
+	clone := c.Clone()
+	clone.SetInput(strings.NewReader("from the clone"))
+	if data, _ := io.ReadAll(clone.Lookup("in").Value.Get().(*Input)); string(data) != "from the clone" {
+		t.Errorf("read %q from the clone's input", data)
+	}
+}
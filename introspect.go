@@ -0,0 +1,76 @@
+package mandy
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"text/tabwriter"
+)
+
+// FlagsName is the name of the child added by FlagsCommand
+const FlagsName = "flags"
+
+// A FlagRow describes the resolved state of a flag, as printed by the child added by FlagsCommand.
+// Secret values and defaults are redacted.
+type FlagRow struct {
+	Command string `json:"command"` // the path of the command defining the flag
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Value   string `json:"value"`
+	Source  string `json:"source"` // as described by Dump
+	Default string `json:"default"`
+}
+
+// flagRows describes the flags of c and its ancestors, the root's first, omitting help flags
+func (c *Command) flagRows() (rows []FlagRow) {
+	var path []*Command
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		path = append(path, cmd)
+	}
+	slices.Reverse(path)
+	for _, cmd := range path {
+		cmd.VisitAll(func(f *Flag) {
+			if f.Name == HelpName {
+				return
+			}
+			rows = append(rows, FlagRow{
+				Command: cmd.path(),
+				Name:    f.Name,
+				Type:    fmt.Sprintf("%T", f.Value.Get()),
+				Value:   f.redact(f.Value.String()),
+				Source:  cmd.source(f),
+				Default: f.redact(c.inheritedDefault(f)),
+			})
+		})
+	}
+	return rows
+}
+
+// FlagsCommand adds a Hidden child, named FlagsName, printing the resolved flags of the command and its
+// ancestors as parsed from the rest of the command line, as a table or, given --format=json, as a JSON
+// array of FlagRows; so "tool --verbose flags" shows how tool's flags were resolved. It is Dump as a command,
+// for debugging layered configuration.
+func (c *Command) FlagsCommand() *Command {
+	child := c.NewChild(FlagsName, "print the resolved flags")
+	child.Hidden = true
+	var format string
+	child.Enum(&format, "format", "table", "output format", false, "table", "json")
+	child.Main = func(self *Command) error {
+		rows := self.parent.flagRows()
+		if self.formal["format"].Value.String() == "json" {
+			enc := json.NewEncoder(self.Stdout())
+			enc.SetIndent("", "\t")
+			if rows == nil {
+				rows = []FlagRow{}
+			}
+			return enc.Encode(rows)
+		}
+		w := tabwriter.NewWriter(self.Stdout(), 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "COMMAND\tFLAG\tTYPE\tVALUE\tSOURCE\tDEFAULT")
+		for _, row := range rows {
+			fmt.Fprintf(w, "%s\t--%s\t%s\t%s\t%s\t%s\n", row.Command, row.Name, row.Type, row.Value, row.Source, row.Default)
+		}
+		return w.Flush()
+	}
+	return child
+}
@@ -0,0 +1,47 @@
+package mandy
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestFlagsCommand(t *testing.T) {
+	var (
+		verbose bool
+		port    int
+	)
+	c := NewCommand("tool", ContinueOnError)
+	var out bytes.Buffer
+	c.SetStdout(&out)
+	c.Bool(&verbose, "verbose", false, "", false)
+	c.Int(&port, "port", 8080, "", false)
+	c.FlagsCommand()
+
+	if strings.Contains(c.UsageString(), FlagsName) {
+		t.Errorf("usage lists the hidden %s child:\n%s", FlagsName, c.UsageString())
+	}
+	if err := c.Clone().Execute("--port", "80", FlagsName, "--format=json"); err != nil {
+		t.Fatal(err)
+	}
+	var rows []FlagRow
+	if err := json.Unmarshal(out.Bytes(), &rows); err != nil {
+		t.Fatal(err)
+	}
+	want := []FlagRow{
+		{Command: "tool", Name: "port", Type: "int", Value: "80", Source: "set", Default: "8080"},
+		{Command: "tool", Name: "verbose", Type: "bool", Value: "false", Source: "default", Default: "false"},
+	}
+	if len(rows) != len(want) || rows[0] != want[0] || rows[1] != want[1] {
+		t.Errorf("rows = %+v, want %+v", rows, want)
+	}
+
+	out.Reset()
+	if err := c.Clone().Execute(FlagsName); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(out.String()), "\n"); len(lines) != 3 || !strings.HasPrefix(lines[0], "COMMAND") {
+		t.Errorf("table = %q, want a header and a row per flag", out.String())
+	}
+}
@@ -0,0 +1,168 @@
+package mandy
+
+import (
+	"runtime"
+	"strings"
+)
+
+// Shell selects the quoting rules used to reconstruct command lines
+type Shell uint8
+
+const (
+	PosixShell Shell = iota // sh, bash, zsh, etc
+	PowerShell
+)
+
+type (
+	// An Invocation records how a command line was parsed,
+	// from the command it was taken from down to the child that was dispatched to.
+	Invocation struct {
+		Commands []InvokedCommand `json:"commands"`
+		Args     []string         `json:"args"`          // the leaf's positional arguments
+		Dir      string           `json:"dir,omitempty"` // the directory requested by a Chdir flag, if any
+	}
+
+	// An InvokedCommand is a command on an invocation's path
+	// along with the flags it was given
+	InvokedCommand struct {
+		Name  string        `json:"name"`
+		Flags []InvokedFlag `json:"flags"`
+	}
+
+	// An InvokedFlag is a flag that was set during an invocation
+	// Secret values are redacted. Flags that take one element per occurrence, like StringArray,
+	// are recorded once per element.
+	InvokedFlag struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+		Bool  bool   `json:"bool"`
+	}
+)
+
+// Invocation records the command line most recently parsed by c
+func (c *Command) Invocation() Invocation {
+	var inv Invocation
+	for cmd := c; cmd != nil; cmd = cmd.sub {
+		ic := InvokedCommand{Name: cmd.name}
+		cmd.VisitSet(func(f *Flag) {
+			if rv, ok := f.Value.(repeatedValue); ok && rv.separator() == "" {
+				for _, elem := range rv.occurrences() {
+					ic.Flags = append(ic.Flags, InvokedFlag{Name: f.Name, Value: f.redact(elem)})
+				}
+				return // each element was set by an occurrence of its own
+			}
+			ic.Flags = append(ic.Flags, InvokedFlag{
+				Name:  f.Name,
+				Value: f.redact(f.Value.String()),
+				Bool:  f.Value.IsBool(),
+			})
+		})
+		inv.Commands = append(inv.Commands, ic)
+		if cmd.sub == nil {
+			inv.Args = append([]string(nil), cmd.args...)
+		}
+	}
+	inv.Dir = c.workdir()
+	return inv
+}
+
+// Argv reconstructs the invocation's command line as a list of unquoted arguments
+func (inv Invocation) Argv() (out []string) {
+	for _, cmd := range inv.Commands {
+		out = append(out, cmd.Name)
+		for _, f := range cmd.Flags {
+			if f.Bool && f.Value == "true" {
+				out = append(out, "--"+f.Name)
+			} else {
+				out = append(out, "--"+f.Name+"="+f.Value)
+			}
+		}
+	}
+	for _, arg := range inv.Args {
+		if strings.HasPrefix(arg, "-") {
+			out = append(out, "--")
+			break
+		}
+	}
+	return append(out, inv.Args...)
+}
+
+// String reconstructs a copy-pasteable command line for the host's default shell
+func (inv Invocation) String() string {
+	shell := PosixShell
+	if runtime.GOOS == "windows" {
+		shell = PowerShell
+	}
+	return inv.Quoted(shell)
+}
+
+// Quoted reconstructs the command line, quoting arguments for the given shell
+func (inv Invocation) Quoted(shell Shell) string {
+	argv := inv.Argv()
+	for i, arg := range argv {
+		argv[i] = Quote(arg, shell)
+	}
+	return strings.Join(argv, " ")
+}
+
+// Hint suggests re-running the invocation, for use in error messages
+func (inv Invocation) Hint() string {
+	return "re-run with: " + inv.String()
+}
+
+// rerunHint suggests re-running the command line parsed by the command's root, if RerunHint is set and
+// parsing has finished, since the arguments left over from a parse that failed part way aren't positional
+func (c *Command) rerunHint() string {
+	if !c.RerunHint || !c.parsed {
+		return ""
+	}
+	root := c
+	for root.parent != nil {
+		root = root.parent
+	}
+	return root.Invocation().Hint()
+}
+
+// Quote quotes s, if necessary, so the given shell reads it as a single word
+func Quote(s string, shell Shell) string {
+	switch shell {
+	case PowerShell:
+		if s != "" && !powerShellOperator(s) && strings.IndexFunc(s, unsafePowerShellRune) < 0 {
+			return s
+		}
+		return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+	default:
+		if s != "" && strings.IndexFunc(s, unsafeShellRune) < 0 {
+			return s
+		}
+		return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+	}
+}
+
+// unsafeShellRune reports whether r may need quoting in a POSIX shell
+func unsafeShellRune(r rune) bool {
+	switch {
+	case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+		return false
+	}
+	return !strings.ContainsRune("_-+=:,./%@", r)
+}
+
+// unsafePowerShellRune reports whether r may need quoting in PowerShell,
+// where commas build arrays and @ splats
+func unsafePowerShellRune(r rune) bool {
+	return r == ',' || r == '@' || unsafeShellRune(r)
+}
+
+// powerShellOperator reports whether PowerShell could read s, unquoted, as a parameter name
+// or the --% stop-parsing token; long options, like --name=x, are read as they are
+func powerShellOperator(s string) bool {
+	if !strings.HasPrefix(s, "-") {
+		return false
+	}
+	if len(s) < 3 || s[1] != '-' {
+		return true
+	}
+	r := rune(s[2])
+	return !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9')
+}
@@ -0,0 +1,86 @@
+package mandy
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		in          string
+		posix, pwsh string
+	}{
+		{"plain", "plain", "plain"},
+		{"", "''", "''"},
+		{"a b", "'a b'", "'a b'"},
+		{"it's", `'it'\''s'`, "'it''s'"},
+		{"$HOME", "'$HOME'", "'$HOME'"},
+		{"--name=x", "--name=x", "--name=x"},
+		{"a,b", "a,b", "'a,b'"},
+		{"@x", "@x", "'@x'"},
+		{"user@host", "user@host", "'user@host'"},
+		{"-x", "-x", "'-x'"},
+		{"--%", "--%", "'--%'"},
+		{"-", "-", "'-'"},
+	}
+	for _, test := range tests {
+		if got := Quote(test.in, PosixShell); got != test.posix {
+			t.Errorf("Quote(%q, PosixShell) = %s, want %s", test.in, got, test.posix)
+		}
+		if got := Quote(test.in, PowerShell); got != test.pwsh {
+			t.Errorf("Quote(%q, PowerShell) = %s, want %s", test.in, got, test.pwsh)
+		}
+	}
+}
+
+func TestInvocation(t *testing.T) {
+	var (
+		verbose     bool
+		token, name string
+	)
+	root := NewCommand("tool", ContinueOnError)
+	root.Bool(&verbose, "verbose", false, "be loud", true)
+	child := root.NewChild("greet", "say hello")
+	child.Secret(&token, "token", "", "api token", false)
+	child.String(&name, "name", "", "who to greet", false)
+	child.Main = func(*Command) error { return nil }
+
+	if err := root.Execute("-v", "greet", "--token=hunter2", "--name", "the world", "--", "-x"); err != nil {
+		t.Fatal(err)
+	}
+	want := `tool --verbose greet '--name=the world' '--token=***' -- -x`
+	if got := root.Invocation().Quoted(PosixShell); got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
+
+func TestRerunHint(t *testing.T) {
+	for _, rerun := range []bool{false, true} {
+		var out strings.Builder
+		root := NewCommand("tool", ExitOnError)
+		root.SetOutput(&out)
+		root.SetExit(func(int) {})
+		root.RerunHint = rerun
+		child := root.NewChild("greet", "say hello")
+		child.Secret(new(string), "token", "", "api token", false)
+		child.Main = func(self *Command) error {
+			self.Handle(errors.New("no greeting"))
+			return nil
+		}
+		if err := root.Execute("greet", "--token=hunter2", "world"); err != nil {
+			t.Fatal(err)
+		}
+		const hint = "re-run with: tool greet '--token=***' world\n"
+		if got := out.String(); strings.HasSuffix(got, "no greeting\n"+hint) != rerun || strings.Contains(got, "hunter2") {
+			t.Errorf("RerunHint = %t: output = %q", rerun, got)
+		}
+
+		// a parse that fails part way leaves no command line worth re-running
+		out.Reset()
+		root.Execute("greet", "--bogus")
+		if strings.Contains(out.String(), "re-run with") {
+			t.Errorf("RerunHint = %t: hinted after a failed parse: %q", rerun, out.String())
+		}
+	}
+}
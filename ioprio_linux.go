@@ -0,0 +1,25 @@
+package mandy
+
+import (
+	"syscall"
+)
+
+const ioClassSupported = true
+
+// ioprioWhoProcess and ioprioClassShift are from linux's include/uapi/linux/ioprio.h
+const (
+	ioprioWhoProcess = 1
+	ioprioClassShift = 13
+)
+
+// setIOClass sets the IO scheduling class of the process, at the default level of 4 for classes with levels
+func setIOClass(class int) error {
+	prio := class << ioprioClassShift
+	if class == ioClasses["realtime"] || class == ioClasses["best-effort"] {
+		prio |= 4
+	}
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOPRIO_SET, ioprioWhoProcess, 0, uintptr(prio)); errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+
+package mandy
+
+import (
+	"errors"
+)
+
+const ioClassSupported = false
+
+// setIOClass reports errors.ErrUnsupported; the platform has no IO scheduling classes
+func setIOClass(class int) error {
+	return errors.ErrUnsupported
+}
@@ -0,0 +1,54 @@
+package mandy
+
+import (
+	"iter"
+)
+
+// Flags returns an iterator over all of the command's flags, set or not,
+// in lexicographical order or, if SortFlags(false) was called, registration order.
+// The order is computed once and reused until flags are added or removed.
+func (c *Command) Flags() iter.Seq[*Flag] {
+	return func(yield func(*Flag) bool) {
+		for _, flag := range c.ordered() {
+			if !yield(flag) {
+				return
+			}
+		}
+	}
+}
+
+// SetFlags returns an iterator over the flags that have been set, in the same order as Flags.
+func (c *Command) SetFlags() iter.Seq[*Flag] {
+	return func(yield func(*Flag) bool) {
+		for _, flag := range c.ordered() {
+			if c.actual[flag.Name] != flag {
+				continue
+			}
+			if !yield(flag) {
+				return
+			}
+		}
+	}
+}
+
+// ordered returns the formal flags in the command's chosen order, caching the result
+func (c *Command) ordered() []*Flag {
+	caches.Lock()
+	defer caches.Unlock()
+	if c.order == nil {
+		c.order = c.orderFlags(c.formal)
+	}
+	return c.order
+}
+
+// ArgSeq returns an iterator over the non-flag arguments.
+// Unlike Argch, it holds no resources when the consumer stops early.
+func (c *Command) ArgSeq() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for _, arg := range c.args {
+			if !yield(arg) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package mandy
+
+import (
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestFlagIterators(t *testing.T) {
+	c := NewCommand("iter", ContinueOnError)
+	c.Bool(new(bool), "beta", false, "", false)
+	c.Int(new(int), "alpha", 0, "", false)
+	if err := c.Parse("--beta"); err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for f := range c.Flags() {
+		names = append(names, f.Name)
+	}
+	if want := []string{"alpha", "beta", HelpName}; !slices.Equal(names, want) {
+		t.Errorf("Flags: got %v, want %v", names, want)
+	}
+
+	names = nil
+	for f := range c.SetFlags() {
+		names = append(names, f.Name)
+	}
+	if want := []string{"beta"}; !slices.Equal(names, want) {
+		t.Errorf("SetFlags: got %v, want %v", names, want)
+	}
+
+	c.String(new(string), "gamma", "", "", false)
+	c.SortFlags(false)
+	names = nil
+	for f := range c.Flags() {
+		names = append(names, f.Name)
+		if f.Name == "alpha" {
+			break
+		}
+	}
+	if want := []string{HelpName, "beta", "alpha"}; !slices.Equal(names, want) {
+		t.Errorf("registration order after adding a flag: got %v, want %v", names, want)
+	}
+}
+
+// TestConcurrentReads reads a command's flags from several goroutines, for go test -race
+func TestConcurrentReads(t *testing.T) {
+	c := NewCommand("iter", ContinueOnError)
+	c.Bool(new(bool), "beta", false, "", true)
+	c.Int(new(int), "alpha", 0, "", false)
+	c.NewChild("sub")
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range c.Flags() {
+			}
+			c.VisitAll(func(*Flag) {})
+			c.accepts("b")
+			c.UsageString()
+		}()
+	}
+	wg.Wait()
+}
+
+func TestArgSeq(t *testing.T) {
+	c := NewCommand("args", ContinueOnError)
+	if err := c.Parse("a", "b", "c"); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for arg := range c.ArgSeq() {
+		got = append(got, arg)
+		if arg == "b" {
+			break
+		}
+	}
+	if want := []string{"a", "b"}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
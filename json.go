@@ -0,0 +1,95 @@
+package mandy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// -- json.RawMessage Value
+// arguments must be valid json, and satisfy each of the schema functions
+type jsonValue struct {
+	p      *json.RawMessage
+	schema []func(json.RawMessage) error
+}
+
+func newJSONValue(val json.RawMessage, p *json.RawMessage, schema []func(json.RawMessage) error) *jsonValue {
+	*p = val
+	return &jsonValue{p: p, schema: schema}
+}
+
+func (j *jsonValue) Set(s string) error {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, []byte(s)); err != nil {
+		return fmt.Errorf("%w: %v", errParse, err)
+	}
+	msg := json.RawMessage(buf.Bytes())
+	for _, check := range j.schema {
+		if err := check(msg); err != nil {
+			return err
+		}
+	}
+	*j.p = msg
+	return nil
+}
+
+func (j *jsonValue) Get() any {
+	if j.p == nil {
+		return json.RawMessage(nil)
+	}
+	return append(json.RawMessage(nil), *j.p...)
+}
+func (j *jsonValue) String() string {
+	if j.p == nil {
+		return ""
+	}
+	return string(*j.p)
+}
+func (j *jsonValue) IsBool() bool { return false }
+
+// -- generic json Value
+// arguments are strictly unmarshalled into a T
+type jsonIntoValue[T any] struct {
+	p *T
+}
+
+func (j *jsonIntoValue[T]) Set(s string) error {
+	var v T
+	dec := json.NewDecoder(bytes.NewReader([]byte(s)))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&v); err != nil {
+		return fmt.Errorf("%w: %v", errParse, err)
+	}
+	if dec.More() {
+		return fmt.Errorf("%w: trailing data after json value", errParse)
+	}
+	*j.p = v
+	return nil
+}
+
+func (j *jsonIntoValue[T]) Get() any { return *j.p }
+func (j *jsonIntoValue[T]) String() string {
+	if j.p == nil {
+		return ""
+	}
+	out, err := json.Marshal(*j.p)
+	if err != nil {
+		return ""
+	}
+	return string(out)
+}
+func (j *jsonIntoValue[T]) IsBool() bool { return false }
+
+// JSON defines a json.RawMessage flag with specified name, default value, and usage string.
+// The argument p points to a json.RawMessage variable in which to store the compacted value of the flag.
+// Arguments must be valid json, and are rejected if any of the schema functions returns an error.
+func (c *Command) JSON(p *json.RawMessage, name string, value json.RawMessage, usage string, short bool, schema ...func(json.RawMessage) error) *Flag {
+	return c.Var(newJSONValue(value, p, schema), name, usage, short)
+}
+
+// JSONInto defines a flag whose arguments are json documents unmarshalled into the T pointed to by p.
+// Documents with fields that T does not have are rejected.
+func JSONInto[T any](c *Command, p *T, name string, value T, usage string, short bool) *Flag {
+	*p = value
+	return c.Var(&jsonIntoValue[T]{p: p}, name, usage, short)
+}
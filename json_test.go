@@ -0,0 +1,53 @@
+package mandy
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestJSON(t *testing.T) {
+	var raw json.RawMessage
+	c := NewCommand("test", ContinueOnError)
+	errNotObject := errors.New("not an object")
+	c.JSON(&raw, "resources", json.RawMessage(`{}`), "resource limits", false, func(msg json.RawMessage) error {
+		if len(msg) == 0 || msg[0] != '{' {
+			return errNotObject
+		}
+		return nil
+	})
+	if err := c.Set("resources", `{ "cpu": 2 }`); err != nil {
+		t.Fatal(err)
+	}
+	if string(raw) != `{"cpu":2}` {
+		t.Errorf("got %s", raw)
+	}
+	if err := c.Set("resources", `{"cpu":`); !errors.Is(err, errParse) {
+		t.Errorf("expected a parse error, got %v", err)
+	}
+	if err := c.Set("resources", `[1]`); !errors.Is(err, errNotObject) {
+		t.Errorf("expected the schema's error, got %v", err)
+	}
+}
+
+func TestJSONInto(t *testing.T) {
+	type resources struct {
+		CPU    int    `json:"cpu"`
+		Memory string `json:"memory"`
+	}
+	var res resources
+	c := NewCommand("test", ContinueOnError)
+	f := JSONInto(c, &res, "resources", resources{CPU: 1}, "resource limits", false)
+	if f.DefValue != `{"cpu":1,"memory":""}` {
+		t.Errorf("DefValue = %s", f.DefValue)
+	}
+	if err := c.Parse(`--resources={"cpu":2,"memory":"1Gi"}`); err != nil {
+		t.Fatal(err)
+	}
+	if res != (resources{2, "1Gi"}) {
+		t.Errorf("got %+v", res)
+	}
+	if err := c.Set("resources", `{"gpu":1}`); err == nil {
+		t.Error("expected an error for an unknown field")
+	}
+}
@@ -0,0 +1,74 @@
+package mandy
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SplitLine splits a command line into arguments as a POSIX shell would, without expanding anything:
+// arguments are separated by unquoted whitespace, single quotes preserve everything they enclose,
+// double quotes preserve all but the backslash escapes \", \\, \$, and \`, and an unquoted backslash
+// preserves the character that follows it. So `--msg="a b" --filter=name=foo` yields two arguments.
+func SplitLine(line string) (args []string, err error) {
+	var (
+		arg    strings.Builder
+		inArg  bool // whether an argument, possibly empty, has begun
+		quote  rune // the quote being read, if any
+		escape bool // whether the previous character was an unquoted, or double quoted, backslash
+	)
+	for _, r := range line {
+		switch {
+		case escape:
+			if quote == '"' && !strings.ContainsRune("\"\\$`", r) {
+				arg.WriteRune('\\')
+			}
+			arg.WriteRune(r)
+			escape = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				arg.WriteRune(r)
+			}
+		case r == '\\':
+			escape, inArg = true, true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				arg.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inArg = r, true
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteRune(r)
+			inArg = true
+		}
+	}
+	switch {
+	case quote != 0:
+		return nil, fmt.Errorf("%w: unterminated %c quote in %q", errParse, quote, line)
+	case escape:
+		return nil, fmt.Errorf("%w: trailing backslash in %q", errParse, line)
+	case inArg:
+		args = append(args, arg.String())
+	}
+	return args, nil
+}
+
+// ParseLine splits the line with SplitLine and parses the resulting arguments, as Parse would.
+// Unlike Parse, an empty line parses no arguments, rather than os.Args.
+func (c *Command) ParseLine(line string) error {
+	args, err := SplitLine(line)
+	if err != nil {
+		return err
+	}
+	c.args = args
+	return c.parse()
+}
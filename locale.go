@@ -0,0 +1,146 @@
+package mandy
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// -- *time.Location Value
+type locationValue struct {
+	p **time.Location
+}
+
+func newLocationValue(val *time.Location, p **time.Location) *locationValue {
+	*p = val
+	return &locationValue{p: p}
+}
+
+func (l *locationValue) Set(s string) error {
+	loc, err := time.LoadLocation(s)
+	if err != nil {
+		return fmt.Errorf("%w: unknown time zone %q, expected an IANA name like Europe/London, UTC, or Local", errParse, s)
+	}
+	*l.p = loc
+	return nil
+}
+
+func (l *locationValue) Get() any {
+	if l.p == nil {
+		return (*time.Location)(nil)
+	}
+	return *l.p
+}
+func (l *locationValue) String() string {
+	if l.p == nil || *l.p == nil {
+		return ""
+	}
+	return (*l.p).String()
+}
+func (l *locationValue) IsBool() bool { return false }
+
+// -- BCP 47 language tag Value
+type languageValue string
+
+func newLanguageValue(val string, p *string) *languageValue {
+	*p = val
+	return (*languageValue)(p)
+}
+
+func (l *languageValue) Set(s string) error {
+	tag, err := ParseLanguageTag(s)
+	if err != nil {
+		return err
+	}
+	*l = languageValue(tag)
+	return nil
+}
+
+func (l *languageValue) Get() any       { return string(*l) }
+func (l *languageValue) String() string { return string(*l) }
+func (l *languageValue) IsBool() bool   { return false }
+
+// ParseLanguageTag checks that s is a well formed BCP 47 language tag, like "en-GB" or "zh-Hant-TW",
+// and returns it with conventional casing. Underscores are accepted in place of hyphens.
+// Whether the subtags are registered is not checked.
+func ParseLanguageTag(s string) (string, error) {
+	subtags := strings.Split(strings.ReplaceAll(s, "_", "-"), "-")
+	fail := func(why string, args ...any) (string, error) {
+		return "", fmt.Errorf("%w: language tag %q %s", errParse, s, fmt.Sprintf(why, args...))
+	}
+	for _, sub := range subtags {
+		if sub == "" || len(sub) > 8 || !isAlnum(sub) {
+			return fail("has a malformed subtag %q", sub)
+		}
+	}
+	i := 0
+	if lang := subtags[0]; strings.EqualFold(lang, "x") {
+		// an entirely private use tag
+	} else if (len(lang) < 2 || len(lang) == 4) || !isAlpha(lang) {
+		return fail("should start with a 2-3 or 5-8 letter language subtag")
+	} else {
+		subtags[0] = strings.ToLower(lang)
+		i = 1
+		// up to 3 extended languages follow 2-3 letter languages
+		for n := 0; n < 3 && len(lang) <= 3 && i < len(subtags) && len(subtags[i]) == 3 && isAlpha(subtags[i]); n++ {
+			subtags[i] = strings.ToLower(subtags[i])
+			i++
+		}
+		if i < len(subtags) && len(subtags[i]) == 4 && isAlpha(subtags[i]) {
+			subtags[i] = strings.ToUpper(subtags[i][:1]) + strings.ToLower(subtags[i][1:])
+			i++
+		}
+		if i < len(subtags) && ((len(subtags[i]) == 2 && isAlpha(subtags[i])) || (len(subtags[i]) == 3 && isDigits(subtags[i]))) {
+			subtags[i] = strings.ToUpper(subtags[i])
+			i++
+		}
+		for ; i < len(subtags) && (len(subtags[i]) >= 5 || (len(subtags[i]) == 4 && subtags[i][0] >= '0' && subtags[i][0] <= '9')); i++ {
+			subtags[i] = strings.ToLower(subtags[i])
+		}
+	}
+	// extensions and private use, each introduced by a singleton
+	for i < len(subtags) {
+		singleton := strings.ToLower(subtags[i])
+		if len(singleton) != 1 {
+			return fail("has an unexpected subtag %q", subtags[i])
+		}
+		subtags[i] = singleton
+		i++
+		start := i
+		for ; i < len(subtags) && (singleton == "x" || len(subtags[i]) > 1); i++ {
+			subtags[i] = strings.ToLower(subtags[i])
+		}
+		if i == start {
+			return fail("has an empty %q extension", singleton)
+		}
+	}
+	return strings.Join(subtags, "-"), nil
+}
+
+func isAlpha(s string) bool {
+	return strings.IndexFunc(s, func(r rune) bool { return !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z') }) < 0
+}
+
+func isDigits(s string) bool {
+	return strings.IndexFunc(s, func(r rune) bool { return r < '0' || r > '9' }) < 0
+}
+
+func isAlnum(s string) bool {
+	return strings.IndexFunc(s, func(r rune) bool {
+		return !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9')
+	}) < 0
+}
+
+// Timezone defines a *time.Location flag with specified name, default value, and usage string.
+// The argument p points to a *time.Location variable in which to store the value of the flag.
+// The flag accepts a value acceptable to time.LoadLocation.
+func (c *Command) Timezone(p **time.Location, name string, value *time.Location, usage string, short bool) *Flag {
+	return c.Var(newLocationValue(value, p), name, usage, short)
+}
+
+// Language defines a BCP 47 language tag flag with specified name, default value, and usage string.
+// The argument p points to a string variable in which to store the value of the flag.
+// The flag accepts a value acceptable to ParseLanguageTag.
+func (c *Command) Language(p *string, name string, value string, usage string, short bool) *Flag {
+	return c.Var(newLanguageValue(value, p), name, usage, short)
+}
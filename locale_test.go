@@ -0,0 +1,50 @@
+package mandy
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseLanguageTag(t *testing.T) {
+	tests := map[string]string{
+		"en":                 "en",
+		"EN_gb":              "en-GB",
+		"zh-hant-tw":         "zh-Hant-TW",
+		"es-419":             "es-419",
+		"sl-rozaj-biske":     "sl-rozaj-biske",
+		"de-DE-u-co-phonebk": "de-DE-u-co-phonebk",
+		"en-x-twain":         "en-x-twain",
+		"x-whatever":         "x-whatever",
+	}
+	for in, want := range tests {
+		got, err := ParseLanguageTag(in)
+		if err != nil {
+			t.Errorf("ParseLanguageTag(%q): %v", in, err)
+		} else if got != want {
+			t.Errorf("ParseLanguageTag(%q) = %q, want %q", in, got, want)
+		}
+	}
+	for _, bad := range []string{"", "e", "engl", "en--GB", "en-ü", "en-GB-u", "toolongtag"} {
+		if _, err := ParseLanguageTag(bad); err == nil {
+			t.Errorf("ParseLanguageTag(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestTimezone(t *testing.T) {
+	var loc *time.Location
+	c := NewCommand("test", ContinueOnError)
+	f := c.Timezone(&loc, "tz", time.UTC, "display time zone", false)
+	if f.DefValue != "UTC" {
+		t.Errorf("DefValue = %q", f.DefValue)
+	}
+	if err := c.Set("tz", "Not/AZone"); err == nil {
+		t.Error("expected an error")
+	}
+	if err := c.Set("tz", "Local"); err != nil {
+		t.Fatal(err)
+	}
+	if loc != time.Local {
+		t.Errorf("got %v", loc)
+	}
+}
@@ -0,0 +1,53 @@
+package mandy
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// logLevels are the names suggested for LogLevel flags
+var logLevels = []string{"debug", "info", "warn", "error"}
+
+// -- slog.Level Value
+type logLevelValue slog.Level
+
+func newLogLevelValue(val slog.Level, p *slog.Level) *logLevelValue {
+	*p = val
+	return (*logLevelValue)(p)
+}
+
+func (l *logLevelValue) Set(s string) error {
+	if n, err := strconv.Atoi(s); err == nil {
+		*l = logLevelValue(n)
+		return nil
+	}
+	var v slog.Level
+	if err := v.UnmarshalText([]byte(s)); err != nil {
+		return fmt.Errorf("%w: log level %q should be one of %s, optionally with an offset like info+2", errParse, s, strings.Join(logLevels, ", "))
+	}
+	*l = logLevelValue(v)
+	return nil
+}
+
+func (l *logLevelValue) Get() any       { return slog.Level(*l) }
+func (l *logLevelValue) String() string { return strings.ToLower(slog.Level(*l).String()) }
+func (l *logLevelValue) IsBool() bool   { return false }
+
+func (l *logLevelValue) Complete(prefix string) (out []string) {
+	for _, name := range logLevels {
+		if strings.HasPrefix(name, strings.ToLower(prefix)) {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+// LogLevel defines a slog.Level flag with specified name, default value, and usage string.
+// The argument p points to a slog.Level variable in which to store the value of the flag.
+// The flag accepts debug, info, warn, and error, case insensitively, optionally
+// followed by an offset like "info+2", as well as plain integers.
+func (c *Command) LogLevel(p *slog.Level, name string, value slog.Level, usage string, short bool) *Flag {
+	return c.Var(newLogLevelValue(value, p), name, usage, short)
+}
@@ -0,0 +1,35 @@
+package mandy
+
+import (
+	"log/slog"
+	"reflect"
+	"testing"
+)
+
+func TestLogLevel(t *testing.T) {
+	var level slog.Level
+	c := NewCommand("test", ContinueOnError)
+	f := c.LogLevel(&level, "log-level", slog.LevelInfo, "minimum level to log", false)
+	if f.DefValue != "info" {
+		t.Errorf("DefValue = %q", f.DefValue)
+	}
+	tests := map[string]slog.Level{
+		"debug":  slog.LevelDebug,
+		"WARN":   slog.LevelWarn,
+		"info+2": slog.LevelInfo + 2,
+		"-8":     -8,
+	}
+	for arg, want := range tests {
+		if err := c.Set("log-level", arg); err != nil {
+			t.Errorf("%s: %v", arg, err)
+		} else if level != want {
+			t.Errorf("%s gave %v, want %v", arg, level, want)
+		}
+	}
+	if err := c.Set("log-level", "loud"); err == nil {
+		t.Error("expected an error")
+	}
+	if got := f.Completions("de"); !reflect.DeepEqual(got, []string{"debug"}) {
+		t.Errorf("Completions(de) = %q", got)
+	}
+}
@@ -0,0 +1,161 @@
+package mandy
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ErrDuplicateKey is returned when a map flag is given a key twice under DuplicateError
+var ErrDuplicateKey = errors.New("mandy: duplicate key")
+
+// DuplicatePolicy determines how map flags treat keys given more than once
+type DuplicatePolicy uint8
+
+const (
+	DuplicateReplace DuplicatePolicy = iota // the last value wins
+	DuplicateKeep                           // the first value wins
+	DuplicateError                          // repeating a key is an error
+)
+
+// keyedValue is implemented by Values holding maps
+type keyedValue interface {
+	Getter
+	setDuplicates(policy DuplicatePolicy)
+}
+
+// Duplicates sets how the flag treats keys given more than once; by default, the last value wins.
+// Duplicates panics if the flag's value is not a map.
+func (f *Flag) Duplicates(policy DuplicatePolicy) *Flag {
+	kv, ok := f.Value.(keyedValue)
+	if !ok {
+		panic(fmt.Sprintf("flag %q does not hold keys", f.Name))
+	}
+	kv.setDuplicates(policy)
+	return f
+}
+
+// -- map Value
+// arguments are separated lists of key=value pairs
+// the first argument replaces the default, later ones add to it
+type mapValue[K comparable, V any] struct {
+	p         *map[K]V
+	parseKey  func(string) (K, error)
+	formatKey func(K) string
+	parse     func(string) (V, error)
+	format    func(V) string
+	sep       string
+	dup       DuplicatePolicy
+	changed   bool
+}
+
+func newMapValue[K comparable, V any](val map[K]V, p *map[K]V, key codec[K], elem codec[V]) *mapValue[K, V] {
+	*p = maps.Clone(val)
+	return &mapValue[K, V]{
+		p:         p,
+		parseKey:  key.parse,
+		formatKey: key.format,
+		parse:     elem.parse,
+		format:    elem.format,
+		sep:       DefaultSeparator,
+	}
+}
+
+func (m *mapValue[K, V]) Set(arg string) error {
+	next := make(map[K]V)
+	if m.changed {
+		maps.Copy(next, *m.p)
+	}
+	for _, part := range splitEscaped(arg, m.sep) {
+		keyText, text, ok := strings.Cut(part, "=")
+		if !ok || keyText == "" {
+			return fmt.Errorf("%w: %q should look like key=value", errParse, part)
+		}
+		key, err := m.parseKey(keyText)
+		if err != nil {
+			return err
+		}
+		v, err := m.parse(text)
+		if err != nil {
+			return err
+		}
+		if _, dup := next[key]; dup {
+			switch m.dup {
+			case DuplicateKeep:
+				continue
+			case DuplicateError:
+				return fmt.Errorf("%w: %q", ErrDuplicateKey, keyText)
+			}
+		}
+		next[key] = v
+	}
+	*m.p, m.changed = next, true
+	return nil
+}
+
+func (m *mapValue[K, V]) settle() { m.changed = false }
+
+func (m *mapValue[K, V]) String() string {
+	if m.p == nil {
+		return ""
+	}
+	sep := m.sep
+	if sep == "" {
+		sep = DefaultSeparator
+	}
+	parts := make([]string, 0, len(*m.p))
+	for key, v := range *m.p {
+		parts = append(parts, escapeSeparator(m.formatKey(key)+"="+m.format(v), sep))
+	}
+	slices.Sort(parts)
+	return strings.Join(parts, sep)
+}
+
+func (m *mapValue[K, V]) Get() any                             { return maps.Clone(*m.p) }
+func (m *mapValue[K, V]) IsBool() bool                         { return false }
+func (m *mapValue[K, V]) separator() string                    { return m.sep }
+func (m *mapValue[K, V]) setSeparator(sep string)              { m.sep = sep }
+func (m *mapValue[K, V]) setDuplicates(policy DuplicatePolicy) { m.dup = policy }
+
+func (m *mapValue[K, V]) clone() Getter {
+	cp := *m
+	p := maps.Clone(*m.p)
+	cp.p = &p
+	return &cp
+}
+
+func (m *mapValue[K, V]) snapshot() func() {
+	saved, changed := maps.Clone(*m.p), m.changed
+	return func() { *m.p, m.changed = maps.Clone(saved), changed }
+}
+
+// StringToString defines a map[string]string flag with specified name, default value, and usage string.
+// The argument p points to a map[string]string variable in which to store the value of the flag.
+// Each occurrence of the flag accepts a comma separated list of key=value pairs; see Flag.Duplicates.
+func (c *Command) StringToString(p *map[string]string, name string, value map[string]string, usage string, short bool) *Flag {
+	return c.Var(newMapValue(value, p, stringCodec, stringCodec), name, usage, short)
+}
+
+// StringToInt defines a map[string]int flag with specified name, default value, and usage string.
+// The argument p points to a map[string]int variable in which to store the value of the flag.
+// Each occurrence of the flag accepts a comma separated list of key=integer pairs; see Flag.Duplicates.
+func (c *Command) StringToInt(p *map[string]int, name string, value map[string]int, usage string, short bool) *Flag {
+	return c.Var(newMapValue(value, p, stringCodec, intCodec), name, usage, short)
+}
+
+// MapVar defines a map flag, whose keys and values may be of any type SliceVar accepts, bound to p.
+// Each occurrence of the flag accepts a comma separated list of key=value pairs; see Flag.Duplicates.
+// MapVar panics if the type of the keys or the values is unsupported.
+func MapVar[K comparable, V any](c *Command, p *map[K]V, name string, value map[K]V, usage string, short bool) *Flag {
+	key, ok := codecFor[K]()
+	if !ok {
+		panic(c.sprintf("flag %q has unsupported key type %T", name, *new(K)))
+	}
+	elem, ok := codecFor[V]()
+	if !ok {
+		panic(c.sprintf("flag %q has unsupported value type %T", name, *new(V)))
+	}
+	return c.Var(newMapValue(value, p, key, elem), name, usage, short)
+}
@@ -0,0 +1,77 @@
+package mandy
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStringToString(t *testing.T) {
+	var labels map[string]string
+	c := NewCommand("test", ContinueOnError)
+	f := c.StringToString(&labels, "label", map[string]string{"env": "dev"}, "labels to apply", false)
+
+	if f.DefValue != "env=dev" {
+		t.Errorf("DefValue = %q, want env=dev", f.DefValue)
+	}
+	if err := c.Parse("--label", "env=prod", "--label=team=core,tier=web", "--label", "env=qa"); err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"env": "qa", "team": "core", "tier": "web"}; !reflect.DeepEqual(labels, want) {
+		t.Errorf("labels = %v, want %v", labels, want)
+	}
+	if got, want := f.Value.String(), "env=qa,team=core,tier=web"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if err := c.Parse("--label", "nokey"); !errors.Is(err, errParse) {
+		t.Errorf("pair without =: err = %v, want errParse", err)
+	}
+}
+
+func TestStringToIntDuplicates(t *testing.T) {
+	tests := []struct {
+		policy DuplicatePolicy
+		want   map[string]int
+		err    error
+	}{
+		{DuplicateReplace, map[string]int{"a": 3, "b": 2}, nil},
+		{DuplicateKeep, map[string]int{"a": 1, "b": 2}, nil},
+		{DuplicateError, map[string]int{"a": 1, "b": 2}, ErrDuplicateKey},
+	}
+	for _, test := range tests {
+		var limits map[string]int
+		c := NewCommand("test", ContinueOnError)
+		c.StringToInt(&limits, "limit", map[string]int{"a": 9}, "limits", false).Duplicates(test.policy)
+		err := c.Parse("--limit", "a=1,b=2", "--limit", "a=3")
+		if !errors.Is(err, test.err) {
+			t.Errorf("policy %d: err = %v, want %v", test.policy, err, test.err)
+		}
+		if !reflect.DeepEqual(limits, test.want) {
+			t.Errorf("policy %d: limits = %v, want %v", test.policy, limits, test.want)
+		}
+	}
+}
+
+func TestMapVar(t *testing.T) {
+	var (
+		ports   map[uint]string
+		timeout map[string]time.Duration
+	)
+	c := NewCommand("test", ContinueOnError)
+	MapVar(c, &ports, "port", map[uint]string{80: "http"}, "port names", false)
+	f := MapVar(c, &timeout, "timeout", nil, "timeouts by host", false)
+
+	if err := c.Parse("--port", "443=https,22=ssh", "--timeout=a=1s,b=1m"); err != nil {
+		t.Fatal(err)
+	}
+	if want := map[uint]string{443: "https", 22: "ssh"}; !reflect.DeepEqual(ports, want) {
+		t.Errorf("ports = %v, want %v", ports, want)
+	}
+	if got, want := f.Value.String(), "a=1s,b=1m0s"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if err := c.Parse("--port", "http=80"); !errors.Is(err, errParse) {
+		t.Errorf("invalid key: err = %v, want errParse", err)
+	}
+}
@@ -0,0 +1,63 @@
+package mandy
+
+import (
+	"slices"
+	"sync"
+)
+
+// matcher indexes the names a command's arguments may use to refer to its flags and children,
+// so that commands with hundreds of them resolve each argument without scanning them all.
+// It's built on first use and discarded whenever flags or children are added or removed.
+type matcher struct {
+	initials map[string][]*Flag  // flags by initial, in registration order
+	children map[string]*Command // children by name and alias, earlier children first
+}
+
+// caches guards the flag orders, indices and usage messages commands build lazily,
+// so that commands may be read from several goroutines
+var caches sync.Mutex
+
+// matcher returns the command's index, building it if it's stale
+func (c *Command) matcher() *matcher {
+	caches.Lock()
+	defer caches.Unlock()
+	if c.match != nil {
+		return c.match
+	}
+	m := &matcher{
+		initials: make(map[string][]*Flag),
+		children: make(map[string]*Command),
+	}
+	for _, flag := range c.ordinalFlags() {
+		initial := flag.Name[:1]
+		m.initials[initial] = append(m.initials[initial], flag)
+	}
+	for _, child := range c.children {
+		for _, name := range append([]string{child.name}, child.aliases...) {
+			if _, ok := m.children[name]; !ok {
+				m.children[name] = child
+			}
+		}
+	}
+	c.match = m
+	return m
+}
+
+// ordinalFlags returns the command's flags in registration order
+func (c *Command) ordinalFlags() []*Flag {
+	flags := make([]*Flag, 0, len(c.formal))
+	for _, flag := range c.formal {
+		flags = append(flags, flag)
+	}
+	slices.SortFunc(flags, func(a, b *Flag) int { return a.ordinal - b.ordinal })
+	return flags
+}
+
+// invalidate discards the command's cached flag order and index, and every cached usage message
+func (c *Command) invalidate() {
+	caches.Lock()
+	defer caches.Unlock()
+	c.order = nil
+	c.match = nil
+	generation.Add(1)
+}
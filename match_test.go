@@ -0,0 +1,74 @@
+package mandy
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestMatcher(t *testing.T) {
+	var x, xray, xerox bool
+	c := NewCommand("test", ContinueOnError)
+	c.Bool(&xray, "xray", false, "see through", true)
+	c.Bool(&xerox, "xerox", false, "copy", false)
+	c.Bool(&x, "x", false, "mark the spot", false)
+
+	if got := c.accepts("x"); got != "x" {
+		t.Errorf("accepts(x) = %q, want the exact name", got)
+	}
+	c.DisableHelpFlag()
+	if got := c.accepts("h"); got != "" {
+		t.Errorf("accepts(h) = %q after the help flag was removed", got)
+	}
+
+	var yank bool
+	c.Bool(&yank, "yank", false, "pull", true)
+	if got := c.accepts("y"); got != "yank" {
+		t.Errorf("accepts(y) = %q after registering yank, want yank", got)
+	}
+
+	run := c.NewChild("run", "")
+	if c.child("go") != nil {
+		t.Fatal("child(go) found before the alias was added")
+	}
+	if err := run.AddAlias("go"); err != nil {
+		t.Fatal(err)
+	}
+	if c.child("go") != run {
+		t.Error("child(go) didn't find the alias added after the index was built")
+	}
+}
+
+// wideCommand returns a command with n short flags, as generated from an API specification
+func wideCommand(n int) *Command {
+	c := NewCommand("wide", ContinueOnError)
+	for i := range n {
+		c.String(new(string), "field"+strconv.Itoa(i), "", "a field", i == n-1)
+	}
+	for i := range n / 10 {
+		c.NewChild("op"+strconv.Itoa(i), "")
+	}
+	return c
+}
+
+func BenchmarkAccepts(b *testing.B) {
+	c := wideCommand(500)
+	c.accepts("f")
+	b.ResetTimer()
+	for range b.N {
+		c.accepts("field499")
+		c.accepts("f")
+		c.child("op49")
+	}
+}
+
+func BenchmarkParseWide(b *testing.B) {
+	c := wideCommand(500)
+	args := []string{"--field0", "a", "--field250=b", "-f", "c", "op49"}
+	b.ResetTimer()
+	for range b.N {
+		c.forget()
+		if err := c.Parse(args...); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
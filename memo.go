@@ -0,0 +1,73 @@
+package mandy
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// generation counts changes to the flags and children of every command, so that cached usage
+// messages, which may list the flags of ancestors and the children of the command, can tell they're stale
+var generation atomic.Uint64
+
+// usageMemo is a rendered default usage message, along with what it was rendered from
+type usageMemo struct {
+	key  usageKey
+	text string
+}
+
+// usageKey holds the inputs of the default usage message, besides the commands' flags and children,
+// that can change without invalidating the command
+type usageKey struct {
+	generation              uint64
+	format, example, footer string
+	version, url            string
+	state                   string // the state of the flags and children listed, which may change in place
+	style                   DefaultStyle
+	globalOptions           bool
+}
+
+// memoUsage returns the default usage message, rendering it only if the command has changed since it last was
+func (c *Command) memoUsage() string {
+	key := usageKey{
+		generation:    generation.Load(),
+		format:        c.Format,
+		example:       c.Example,
+		footer:        c.Footer,
+		version:       c.Version,
+		url:           c.url(),
+		style:         c.DefaultStyle,
+		globalOptions: c.GlobalOptions,
+		state:         c.usageState(),
+	}
+	caches.Lock()
+	memo := c.usageMemo
+	caches.Unlock()
+	if memo == nil || memo.key != key {
+		memo = &usageMemo{key: key, text: c.defaultUsage()}
+		caches.Lock()
+		c.usageMemo = memo
+		caches.Unlock()
+	}
+	return memo.text
+}
+
+// usageState describes the flags of the command and its ancestors, and the command's children, as far as
+// usage messages show them, since Flag modifiers, and assignments to their fields, don't invalidate the command.
+// The flags are visited in order, rather than as they're kept in formal, so that the state is the same between calls.
+func (c *Command) usageState() string {
+	var b strings.Builder
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		for _, f := range cmd.ordered() {
+			fmt.Fprintf(&b, "%q %q %q %q %t %t %t %t %t %t %q %t\x00", f.Name, f.Description, f.DefValue, f.NoOptDefVal,
+				f.Short, f.hideDefault, f.required, f.negatable, f.fromFile, f.redactor != nil, f.env, f.defaultSet)
+			if f.IsDerived() {
+				b.WriteString(f.Value.String() + "\x00")
+			}
+		}
+	}
+	for _, child := range c.children {
+		fmt.Fprintf(&b, "%q %q %t %q\x00", child.name, child.Summary, child.hidden(), child.addedBy)
+	}
+	return b.String()
+}
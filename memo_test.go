@@ -0,0 +1,87 @@
+package mandy
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMemoUsage(t *testing.T) {
+	c := NewCommand("test", ContinueOnError)
+	c.Footer = ""
+	first := c.UsageString()
+	if c.UsageString() != first {
+		t.Fatal("usage changed without the command changing")
+	}
+	for _, name := range []string{"alpha", "beta", "gamma", "delta"} {
+		c.String(new(string), name, "", "", false)
+	}
+	c.UsageString()
+	memo := c.usageMemo
+	if c.UsageString(); c.usageMemo != memo {
+		t.Error("the usage of a command with several flags was rendered again without the command changing")
+	}
+
+	c.String(new(string), "name", "", "a name", false)
+	if got := c.UsageString(); got == first || !strings.Contains(got, "--name") {
+		t.Errorf("usage after adding a flag:\n%s", got)
+	}
+	c.Example = "test --name gopher"
+	if got := c.UsageString(); !strings.Contains(got, "test --name gopher") {
+		t.Errorf("usage after setting the example:\n%s", got)
+	}
+	name := c.Lookup("name")
+	port := c.Int(new(int), "port", 80, "", false)
+	for _, tc := range []struct {
+		change func()
+		text   string
+		shown  bool
+	}{
+		{func() { name.Env("TEST_NAME") }, "[env: TEST_NAME]", true},
+		{func() { name.Description = "who to greet" }, "who to greet", true},
+		{func() { name.Required() }, "(required)", true},
+		{func() { port.HideDefault() }, "[default: 80]", false},
+	} {
+		c.UsageString()
+		tc.change()
+		if got := c.UsageString(); strings.Contains(got, tc.text) != tc.shown {
+			t.Errorf("stale usage: %q shown = %t, want %t:\n%s", tc.text, !tc.shown, tc.shown, got)
+		}
+	}
+
+	child := c.NewChild("child", "")
+	child.GlobalOptions = true
+	child.UsageString()
+	c.Int(new(int), "count", 0, "a count", false)
+	if got := child.UsageString(); !strings.Contains(got, "--count") {
+		t.Errorf("child's usage doesn't list the parent's new flag:\n%s", got)
+	}
+}
+
+func TestLazyURL(t *testing.T) {
+	var resolved []string
+	SetURLResolver(func(name string) string {
+		resolved = append(resolved, name)
+		return "https://example.com/" + name
+	})
+	defer SetURLResolver(nil)
+
+	c := NewCommand("tool", ContinueOnError)
+	child := c.NewChild("sub", "")
+	if len(resolved) != 0 {
+		t.Fatalf("URLs resolved before help was rendered: %q", resolved)
+	}
+	if got := child.UsageString(); !strings.Contains(got, "https://example.com/tool") {
+		t.Errorf("child's usage lacks the inherited URL:\n%s", got)
+	}
+	c.UsageString()
+	if len(resolved) != 1 || resolved[0] != "tool" {
+		t.Errorf("resolved %q, want just the root's URL, once", resolved)
+	}
+
+	set := NewCommand("set", ContinueOnError)
+	set.URL = "https://example.org"
+	set.UsageString()
+	if len(resolved) != 1 {
+		t.Error("resolved the URL of a command whose URL was set")
+	}
+}
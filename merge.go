@@ -0,0 +1,64 @@
+package mandy
+
+import (
+	"fmt"
+)
+
+// ConflictPolicy determines how Command.Merge treats flags whose names are already taken.
+type ConflictPolicy uint8
+
+const (
+	ConflictError  ConflictPolicy = iota // Merge nothing and return an error wrapping ErrConflict.
+	ConflictPrefix                       // Rename the incoming flag to "<other's name>-<flag name>".
+	ConflictKeep                         // Keep the existing flag and drop the incoming one.
+)
+
+// Merge adds other's flags, except its help flag, to the command. Reserved names count as taken.
+// The merged flags share their values with other, so variables bound by other's flag constructors
+// are set by parsing the command. A merged flag loses its short form if its initial is already taken.
+// Nothing is merged if an error is returned.
+func (c *Command) Merge(other *Command, resolve ConflictPolicy) error {
+	if err := c.errSealed("the flags of", other.name); err != nil {
+		return err
+	}
+	incoming := make(map[string]*Flag, len(other.formal))
+	for _, flag := range sortFlags(other.formal) {
+		if flag.Name == HelpName {
+			continue
+		}
+		f := *flag
+		if c.taken(f.Name) {
+			if resolve == ConflictKeep {
+				continue
+			}
+			if resolve == ConflictPrefix {
+				f.Name = other.name + "-" + f.Name
+				f.Short = false
+			}
+			if c.taken(f.Name) || resolve != ConflictPrefix || other.formal[f.Name] != nil {
+				if c.isReserved(f.Name) {
+					return fmt.Errorf("%w: %s reserves %q, requested by %s", ErrConflict, c.name, f.Name, other.name)
+				}
+				return fmt.Errorf("%w: %s cannot merge %q from %s", ErrConflict, c.name, f.Name, other.name)
+			}
+		}
+		incoming[f.Name] = &f
+	}
+
+	for _, flag := range other.orderFlags(incoming) {
+		if flag.Short && c.shortFlag(flag.Name[0]) != nil {
+			flag.Short = false
+		}
+		if help := c.formal[HelpName]; flag.Short && help != nil && help.Short && HelpName[0] == flag.Name[0] {
+			help.Short = false
+		}
+		c.register(flag)
+	}
+	return nil
+}
+
+// taken reports whether a flag is already registered, or reserved, by the given name
+func (c *Command) taken(name string) bool {
+	_, ok := c.formal[name]
+	return ok || c.isReserved(name)
+}
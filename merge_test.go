@@ -0,0 +1,51 @@
+package mandy
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	newCommands := func() (*Command, *Command, *string) {
+		c := NewCommand("app", ContinueOnError)
+		c.String(new(string), "addr", "", "the app's address", true)
+		db := NewCommand("db", ContinueOnError)
+		var addr string
+		db.String(&addr, "addr", "localhost", "the database's address", true)
+		db.Int(new(int), "pool", 4, "connection pool size", true)
+		return c, db, &addr
+	}
+
+	c, db, _ := newCommands()
+	if err := c.Merge(db, ConflictError); !errors.Is(err, ErrConflict) {
+		t.Errorf("expected ErrConflict, got %v", err)
+	}
+	if c.Lookup("pool") != nil {
+		t.Error("a failed merge should add nothing")
+	}
+
+	c, db, _ = newCommands()
+	if err := c.Merge(db, ConflictKeep); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Lookup("addr").Description; got != "the app's address" {
+		t.Errorf("ConflictKeep replaced the existing flag: %q", got)
+	}
+
+	c, db, addr := newCommands()
+	if err := c.Merge(db, ConflictPrefix); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Parse("--db-addr", "db.internal", "-p", "8"); err != nil {
+		t.Fatal(err)
+	}
+	if *addr != "db.internal" {
+		t.Errorf("merged flag did not set the contributor's variable: %q", *addr)
+	}
+	if db.Lookup("addr").Name != "addr" {
+		t.Error("renaming a merged flag should not affect the contributor")
+	}
+	if c.Lookup(HelpName).Description != helpUsage || c.Lookup(HelpName) == db.Lookup(HelpName) {
+		t.Error("the contributor's help flag should not be merged")
+	}
+}
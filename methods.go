@@ -0,0 +1,144 @@
+//go:build !tinygo
+
+package mandy
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+var (
+	contextType = reflect.TypeFor[context.Context]()
+	argsType    = reflect.TypeFor[[]string]()
+	errorType   = reflect.TypeFor[error]()
+)
+
+// Commands returns a command, named after the running program, with a child for each of the receiver's
+// methods that AddCommands recognises. Its error policy is ExitOnError.
+func Commands(receiver any) *Command {
+	c := NewCommand(programName(), ExitOnError)
+	c.AddCommands(receiver)
+	return c
+}
+
+// AddCommands adds a child to the command for each exported method of receiver with one of the forms
+//
+//	func(ctx context.Context, args []string) error
+//	func(ctx context.Context, opts *T, args []string) error
+//
+// where T is a struct whose exported fields become the child's flags. Children are named after their
+// methods in kebab case, so ListUsers becomes list-users, and are run with a background context and
+// the child's positional arguments. Other methods are ignored.
+//
+// Options are named after their fields in the same way unless a field has a `flag:"name"` tag;
+// `flag:"name,short"` also allows the flag's initial, `flag:"-"` skips the field, `usage:"..."`
+// describes the flag, and `default:"..."` is parsed as its default value.
+// Fields may be of any type Add accepts, such as the basic types, time.Duration, or os.FileMode;
+// pointers to other fields must implement Getter, and fields whose pointers implement FlagGroup are mounted
+// under their flag name. If the receiver has a Summaries() map[string]string method, its map,
+// keyed by method name, supplies the children's summaries.
+//
+// It panics if receiver has no such methods, or if an option can't be made into a flag.
+func (c *Command) AddCommands(receiver any) []*Command {
+	rv := reflect.ValueOf(receiver)
+	var summaries map[string]string
+	if s, ok := receiver.(interface{ Summaries() map[string]string }); ok {
+		summaries = s.Summaries()
+	}
+	var children []*Command
+	for i := 0; i < rv.NumMethod(); i++ {
+		method, fn := rv.Type().Method(i), rv.Method(i)
+		opts, ok := commandMethod(fn.Type())
+		if !ok {
+			continue
+		}
+		child := c.NewChild(kebab(method.Name), summaries[method.Name])
+		var in []reflect.Value
+		if opts != nil {
+			p := reflect.New(opts)
+			child.optionFlags(p.Elem())
+			in = append(in, p)
+		}
+		child.Main = func(self *Command) error {
+			args := append([]reflect.Value{reflect.ValueOf(context.Background())}, in...)
+			out := fn.Call(append(args, reflect.ValueOf(self.Args())))
+			err, _ := out[0].Interface().(error)
+			return err
+		}
+		children = append(children, child)
+	}
+	if len(children) == 0 {
+		panic(c.sprintf("%T has no methods of the form func(context.Context, [*options,] []string) error", receiver))
+	}
+	return children
+}
+
+// commandMethod reports whether a method of type t can be run as a command,
+// and the type of its options struct, if it takes one
+func commandMethod(t reflect.Type) (opts reflect.Type, ok bool) {
+	if t.NumOut() != 1 || t.Out(0) != errorType || t.IsVariadic() {
+		return nil, false
+	}
+	switch t.NumIn() {
+	case 2:
+		return nil, t.In(0) == contextType && t.In(1) == argsType
+	case 3:
+		o := t.In(1)
+		if t.In(0) != contextType || t.In(2) != argsType || o.Kind() != reflect.Pointer || o.Elem().Kind() != reflect.Struct {
+			return nil, false
+		}
+		return o.Elem(), true
+	}
+	return nil, false
+}
+
+// optionFlags defines a flag for each exported field of the struct v
+func (c *Command) optionFlags(v reflect.Value) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		tag := field.Tag.Get("flag")
+		if !field.IsExported() || tag == "-" {
+			continue
+		}
+		name, opt, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = kebab(field.Name)
+		}
+		short, usage := opt == "short", field.Tag.Get("usage")
+		if group, ok := v.Field(i).Addr().Interface().(FlagGroup); ok {
+			if err := c.Mount(name, group); err != nil {
+				panic(c.sprintf("%v", err))
+			}
+			continue
+		}
+		flag := c.pointerVar(v.Field(i).Addr().Interface(), name, usage, short)
+		if flag == nil {
+			panic(c.sprintf("option %s has unsupported type %s", field.Name, field.Type))
+		}
+		if def, ok := field.Tag.Lookup("default"); ok {
+			if err := flag.Value.Set(def); err != nil {
+				panic(c.sprintf("option %s has invalid default %q: %v", field.Name, def, err))
+			}
+			flag.DefValue = flag.Value.String()
+		}
+	}
+}
+
+// kebab converts a Go identifier to kebab case, keeping initialisms together: HTTPGet becomes http-get
+func kebab(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if !unicode.IsUpper(prev) || nextLower {
+				b.WriteByte('-')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
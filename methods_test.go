@@ -0,0 +1,96 @@
+//go:build !tinygo
+
+package mandy
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+	"time"
+)
+
+type toolOptions struct {
+	Limit   int           `flag:"limit,short" usage:"most users to list" default:"10"`
+	Timeout time.Duration `usage:"how long to wait"`
+	Skipped string        `flag:"-"`
+	hidden  bool
+}
+
+type tool struct {
+	listed  []string
+	options toolOptions
+}
+
+func (t *tool) ListUsers(ctx context.Context, opts *toolOptions, args []string) error {
+	t.listed, t.options = args, *opts
+	return nil
+}
+
+func (t *tool) Fail(ctx context.Context, args []string) error { return errors.New("failed") }
+func (t *tool) Helper() string                                { return "not a command" }
+func (t *tool) Summaries() map[string]string {
+	return map[string]string{"ListUsers": "list the known users"}
+}
+
+func TestAddCommands(t *testing.T) {
+	var tl tool
+	c := NewCommand("tool", ContinueOnError)
+	children := c.AddCommands(&tl)
+
+	var names []string
+	for _, child := range children {
+		names = append(names, child.name)
+	}
+	if want := []string{"fail", "list-users"}; !slices.Equal(names, want) {
+		t.Fatalf("children = %q, want %q", names, want)
+	}
+	if got := children[1].Summary; got != "list the known users" {
+		t.Errorf("summary = %q", got)
+	}
+	if children[1].Lookup("skipped") != nil || children[1].Lookup("hidden") != nil {
+		t.Error("skipped or unexported fields became flags")
+	}
+	if got := children[1].Lookup("limit").DefValue; got != "10" {
+		t.Errorf("limit default = %q, want 10", got)
+	}
+
+	if err := c.Execute("list-users", "-l", "3", "--timeout", "1s", "ann", "bob"); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(tl.listed, []string{"ann", "bob"}) {
+		t.Errorf("args = %q", tl.listed)
+	}
+	if tl.options.Limit != 3 || tl.options.Timeout != time.Second {
+		t.Errorf("options = %+v", tl.options)
+	}
+	if err := c.Execute("fail"); err == nil || err.Error() != "failed" {
+		t.Errorf("fail: err = %v", err)
+	}
+}
+
+func TestAddCommandsPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("AddCommands accepted a receiver without command methods")
+		}
+	}()
+	c := NewCommand("test", ContinueOnError)
+	c.SetOutput(io.Discard)
+	c.AddCommands(struct{}{})
+}
+
+func TestKebab(t *testing.T) {
+	for in, want := range map[string]string{
+		"List":      "list",
+		"ListUsers": "list-users",
+		"HTTPGet":   "http-get",
+		"GetURL":    "get-url",
+		"V2Sync":    "v2-sync",
+	} {
+		if got := kebab(in); got != want {
+			t.Errorf("kebab(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package mandy
+
+import (
+	"strings"
+)
+
+// NegationPrefix prefixes the names of negatable boolean flags to set them to false
+const NegationPrefix = "no-"
+
+// Negatable lets the flag, if it is boolean, be set to false with --no-<name> as well as --<name>=false.
+// A flag actually named no-<name> takes precedence over the negated form.
+func (f *Flag) Negatable() *Flag {
+	f.negatable = true
+	return f
+}
+
+// NegateBools makes the command's boolean flags, other than its help flag, Negatable,
+// including those registered later and those of children created after the call
+func (c *Command) NegateBools() *Command {
+	c.negateBools = true
+	for _, flag := range c.formal {
+		c.markNegatable(flag)
+	}
+	c.invalidate()
+	return c
+}
+
+// markNegatable makes the flag Negatable if the command negates its boolean flags
+func (c *Command) markNegatable(flag *Flag) {
+	if c.negateBools && flag.Name != HelpName && flag.Value.IsBool() {
+		flag.negatable = true
+	}
+}
+
+// negated returns the negatable boolean flag that a --no-<name> argument refers to, or nil
+func (c *Command) negated(name string) *Flag {
+	flag := c.formal[strings.TrimPrefix(name, NegationPrefix)]
+	if !strings.HasPrefix(name, NegationPrefix) || flag == nil || !flag.negatable || !flag.Value.IsBool() {
+		return nil
+	}
+	return flag
+}
@@ -0,0 +1,54 @@
+package mandy
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNegatable(t *testing.T) {
+	var color, cache bool
+	c := NewCommand("test", ContinueOnError)
+	c.Bool(&color, "color", true, "colorize output", false).Negatable()
+	c.Bool(&cache, "cache", true, "use the cache", false)
+
+	if err := c.Parse("--no-color"); err != nil || color {
+		t.Errorf("Parse(--no-color) = %v, color = %t", err, color)
+	}
+	if err := c.Parse("--no-cache"); err == nil {
+		t.Error("Parse(--no-cache) succeeded for a flag that isn't negatable")
+	}
+	if err := c.Parse("--no-color=true"); err == nil {
+		t.Error("Parse(--no-color=true) succeeded")
+	}
+	if want := "--[no-]color\tcolorize output"; !strings.Contains(c.Defaults(), want) {
+		t.Errorf("Defaults() = %q, want it to contain %q", c.Defaults(), want)
+	}
+}
+
+func TestNegateBools(t *testing.T) {
+	var before, after, child bool
+	var name string
+	c := NewCommand("test", ContinueOnError)
+	c.Bool(&before, "before", true, "", false)
+	c.NegateBools()
+	c.Bool(&after, "after", true, "", true)
+	c.String(&name, "name", "", "", false)
+	sub := c.NewChild("sub", "")
+	sub.Bool(&child, "child", true, "", false)
+
+	if err := c.Parse("--no-before", "--no-after", "sub", "--no-child"); err != nil {
+		t.Fatal(err)
+	}
+	if before || after || child {
+		t.Errorf("before, after, child = %t, %t, %t, want all false", before, after, child)
+	}
+	if err := c.Parse("--no-name"); err == nil {
+		t.Error("Parse(--no-name) succeeded for a string flag")
+	}
+	if err := c.Parse("--no-help"); err == nil {
+		t.Error("Parse(--no-help) succeeded")
+	}
+	if want := "-a, --[no-]after"; !strings.Contains(c.Defaults(), want) {
+		t.Errorf("Defaults() = %q, want it to contain %q", c.Defaults(), want)
+	}
+}
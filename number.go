@@ -0,0 +1,250 @@
+package mandy
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// NumberFormat selects the notations, beyond Go's literal syntax, accepted by a numeric flag
+type NumberFormat uint8
+
+const (
+	GroupedDigits NumberFormat = 1 << iota // accept digits grouped by "_" or, in threes, by ","
+	DecimalUnits                           // accept the suffixes k, M, G, T, P, and E as powers of 1000, and Ki, Mi, ... as powers of 1024
+	BinaryUnits                            // as DecimalUnits, but k, M, G, ... are powers of 1024 too
+)
+
+// Numbers makes the flag accept the given notations in addition to those it already does,
+// so that "1,000", "1_000", and "1k" may all set a flag to 1000.
+// Numbers panics if the flag is not an int, int64, uint, uint64, or float64 flag.
+func (f *Flag) Numbers(format NumberFormat) *Flag {
+	f.number().format |= format
+	return f
+}
+
+// Base makes the integer flag display its default, and its value in Dump, in the given base:
+// 2, 8, and 16 render as 0b101, 0o644, and 0x1F, respectively. Parsing is unaffected.
+// Base panics if the flag is not an int, int64, uint, or uint64 flag, or base is not 2, 8, 10, or 16.
+func (f *Flag) Base(base int) *Flag {
+	nv := f.number()
+	if !nv.integral {
+		panic(fmt.Sprintf("flag %q is not an integer", f.Name))
+	}
+	if _, ok := basePrefixes[base]; !ok {
+		panic(fmt.Sprintf("flag %q cannot be displayed in base %d", f.Name, base))
+	}
+	nv.base = base
+	def := cloneValue(nv.Getter)
+	if def.Set(f.DefValue) == nil {
+		f.DefValue = formatInteger(def.Get(), base)
+	}
+	return f
+}
+
+// basePrefixes maps the bases integers may be displayed in to their literal prefixes
+var basePrefixes = map[int]string{2: "0b", 8: "0o", 10: "", 16: "0x"}
+
+// formatInteger renders an int, int64, uint, or uint64 as a Go literal in the given base
+func formatInteger(v any, base int) string {
+	var sign, digits string
+	switch x := v.(type) {
+	case int:
+		sign, digits = signed(int64(x), base)
+	case int64:
+		sign, digits = signed(x, base)
+	case uint:
+		digits = strconv.FormatUint(uint64(x), base)
+	case uint64:
+		digits = strconv.FormatUint(x, base)
+	default:
+		return fmt.Sprint(v)
+	}
+	return sign + basePrefixes[base] + strings.ToUpper(digits)
+}
+
+// signed splits an integer's sign from its digits
+func signed(x int64, base int) (string, string) {
+	digits := strconv.FormatInt(x, base)
+	if strings.HasPrefix(digits, "-") {
+		return "-", digits[1:]
+	}
+	return "", digits
+}
+
+// number returns the flag's value wrapped as a numberValue, wrapping it if it is not already
+func (f *Flag) number() *numberValue {
+	if nv, ok := f.Value.(*numberValue); ok {
+		return nv
+	}
+	nv := &numberValue{Getter: f.Value}
+	switch f.Value.(type) {
+	case *intValue, *int64Value, *uintValue, *uint64Value:
+		nv.integral = true
+	case *float64Value:
+	default:
+		panic(fmt.Sprintf("flag %q is not numeric", f.Name))
+	}
+	f.Value = nv
+	return nv
+}
+
+// wrappedValue is implemented by values that decorate other values
+type wrappedValue interface {
+	unwrap() Getter
+}
+
+// unwrap returns the innermost of a series of wrapped values
+func unwrap(v Getter) Getter {
+	for {
+		w, ok := v.(wrappedValue)
+		if !ok {
+			return v
+		}
+		v = w.unwrap()
+	}
+}
+
+// -- numberValue
+// normalises the notations chosen by its format before passing them to the value it wraps
+// and, if it is bounded, rejects values outside of [min, max]
+type numberValue struct {
+	Getter
+	integral bool
+	format   NumberFormat
+	base     int              // the base integers are displayed in, decimal if zero
+	min, max any              // the bounds, as rendered in usage messages, if inRange is set
+	inRange  func(v any) bool // reports whether the value got from a wrapped value is in bounds
+}
+
+func (n *numberValue) Set(s string) error {
+	s, err := n.normalise(s)
+	if err != nil {
+		return err
+	}
+	if n.inRange != nil {
+		probe := cloneValue(n.Getter)
+		if err := probe.Set(s); err != nil {
+			return err
+		}
+		if !n.inRange(probe.Get()) {
+			return fmt.Errorf("%w: %v is not between %v and %v", errRange, probe.Get(), n.min, n.max)
+		}
+	}
+	return n.Getter.Set(s)
+}
+
+func (n *numberValue) String() string {
+	switch {
+	case n.Getter == nil:
+		return ""
+	case n.base == 0:
+		return n.Getter.String()
+	}
+	return formatInteger(n.Get(), n.base)
+}
+
+func (n *numberValue) IsBool() bool   { return false }
+func (n *numberValue) unwrap() Getter { return n.Getter }
+
+func (n *numberValue) clone() Getter {
+	cp := *n
+	cp.Getter = cloneValue(n.Getter)
+	return &cp
+}
+
+// normalise rewrites s in the syntax accepted by strconv
+func (n *numberValue) normalise(s string) (string, error) {
+	if n.format&GroupedDigits != 0 {
+		var ok bool
+		if s, ok = ungroup(s); !ok {
+			return "", errParse
+		}
+	}
+	if n.format&(DecimalUnits|BinaryUnits) == 0 {
+		return s, nil
+	}
+	mantissa, scale := n.unit(s)
+	if scale == nil {
+		return s, nil
+	}
+	r, ok := new(big.Rat).SetString(mantissa)
+	if !ok {
+		return "", errParse
+	}
+	r.Mul(r, new(big.Rat).SetInt(scale))
+	if n.integral {
+		if !r.IsInt() {
+			return "", errParse
+		}
+		return r.Num().String(), nil
+	}
+	f, _ := r.Float64()
+	return strconv.FormatFloat(f, 'g', -1, 64), nil
+}
+
+// unit splits a unit suffix from s, returning the power it stands for, or nil if there is none
+func (n *numberValue) unit(s string) (string, *big.Int) {
+	unsigned := strings.TrimLeft(s, "+-")
+	if strings.HasPrefix(unsigned, "0x") || strings.HasPrefix(unsigned, "0X") {
+		return s, nil // hexadecimal digits would be mistaken for suffixes
+	}
+	base := int64(1000)
+	if n.format&BinaryUnits != 0 {
+		base = 1024
+	}
+	if strings.HasSuffix(s, "i") {
+		base, s = 1024, s[:len(s)-1]
+	}
+	if s == "" {
+		return s, nil
+	}
+	exp := strings.IndexByte("kMGTPE", s[len(s)-1]) + 1
+	if s[len(s)-1] == 'K' {
+		exp = 1
+	}
+	if exp == 0 {
+		return s, nil
+	}
+	return s[:len(s)-1], new(big.Int).Exp(big.NewInt(base), big.NewInt(int64(exp)), nil)
+}
+
+// ungroup removes digit group separators from s, reporting false if they are misplaced:
+// underscores must sit between digits, and commas must separate the integer part into threes
+func ungroup(s string) (string, bool) {
+	var b strings.Builder
+	isDigit := func(i int) bool { return i >= 0 && i < len(s) && '0' <= s[i] && s[i] <= '9' }
+	run, commas := 0, false // digits since the last comma, and whether one has been seen
+	integer := true         // whether the integer part is being read
+	for i := 0; i < len(s); i++ {
+		switch ch := s[i]; {
+		case i == 0 && (ch == '+' || ch == '-'):
+		case ch == '_':
+			if !isDigit(i-1) || !isDigit(i+1) {
+				return "", false
+			}
+			continue
+		case ch == ',':
+			if !integer || !isDigit(i-1) || !isDigit(i+1) || (commas && run != 3) || (!commas && run > 3) {
+				return "", false
+			}
+			commas, run = true, 0
+			continue
+		case '0' <= ch && ch <= '9':
+			if integer {
+				run++
+			}
+		default:
+			if integer && commas && run != 3 {
+				return "", false
+			}
+			integer = false
+		}
+		b.WriteByte(s[i])
+	}
+	if integer && commas && run != 3 {
+		return "", false
+	}
+	return b.String(), true
+}
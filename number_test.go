@@ -0,0 +1,85 @@
+package mandy
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNumbers(t *testing.T) {
+	var (
+		i int
+		u uint64
+		f float64
+	)
+	c := NewCommand("numbers", ContinueOnError)
+	c.Int(&i, "int", 0, "", false).Numbers(GroupedDigits | DecimalUnits)
+	c.Uint64(&u, "bytes", 0, "", false).Numbers(BinaryUnits)
+	c.Float64(&f, "rate", 0, "", false).Numbers(GroupedDigits | DecimalUnits)
+
+	for _, test := range []struct {
+		flag, arg string
+		want      any
+	}{
+		{"int", "1_000_000", 1000000},
+		{"int", "1,000,000", 1000000},
+		{"int", "-12,345", -12345},
+		{"int", "4k", 4000},
+		{"int", "1.5M", 1500000},
+		{"int", "2Ki", 2048},
+		{"int", "0x1E", 30},
+		{"bytes", "4k", uint64(4096)},
+		{"bytes", "1G", uint64(1 << 30)},
+		{"rate", "1,234.5", 1234.5},
+		{"rate", "2.5k", 2500.0},
+	} {
+		if err := c.Set(test.flag, test.arg); err != nil {
+			t.Errorf("%s=%s: %v", test.flag, test.arg, err)
+			continue
+		}
+		if got := c.Lookup(test.flag).Value.Get(); got != test.want {
+			t.Errorf("%s=%s: got %v, want %v", test.flag, test.arg, got, test.want)
+		}
+	}
+
+	for _, arg := range []string{"1,00", "10,0000", "1__0", "_1", "1.5k0", "1.5"} {
+		if err := c.Set("int", arg); err == nil {
+			t.Errorf("%q should be rejected, got %d", arg, i)
+		}
+	}
+
+	if name, _ := UnquoteUsage(c.Lookup("rate")); name != "float" {
+		t.Errorf("wrapped flags should keep their type name, got %q", name)
+	}
+}
+
+func TestNumbersPanicsOnNonNumbers(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic")
+		}
+	}()
+	NewCommand("numbers", ContinueOnError).String(new(string), "name", "", "", false).Numbers(GroupedDigits)
+}
+
+func TestBase(t *testing.T) {
+	var mode uint
+	var mask int64
+	c := NewCommand("based", ContinueOnError)
+	perm := c.Uint(&mode, "mode", 0644, "file mode", false).Base(8)
+	bits := c.Int64(&mask, "mask", -31, "bit mask", false).Base(16)
+	if perm.DefValue != "0o644" || bits.DefValue != "-0x1F" {
+		t.Errorf("defaults: %q %q", perm.DefValue, bits.DefValue)
+	}
+	if err := c.Set("mode", "0x1ff"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Set("mask", "0b101"); err != nil {
+		t.Fatal(err)
+	}
+	if mode != 0777 || mask != 5 {
+		t.Errorf("values: %o %d", mode, mask)
+	}
+	if dump := c.Dump(); !strings.Contains(dump, "mode=0o777\t") || !strings.Contains(dump, "mask=0x5\t") {
+		t.Errorf("dump:\n%s", dump)
+	}
+}
@@ -0,0 +1,168 @@
+package mandy
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Output is the destination named by an OutFile flag: a file path, or "-" for the command's Stdout,
+// or os.Stdout if the Output belongs to no flag.
+// It is an io.WriteCloser that opens its destination when first written to, or when Open is called.
+// Set its fields before defining the flag to choose how the destination is opened.
+type Output struct {
+	Path     string
+	Perm     os.FileMode // permissions of created files, 0o666 less the umask if zero
+	MkdirAll bool        // create missing parent directories
+	// Atomic makes writes go to a temporary file beside Path, which Close renames over Path,
+	// so that readers never see a partial file. Abort discards the temporary file instead.
+	Atomic bool
+	w      io.Writer
+	file   *os.File // the file being written, nil for stdout
+	cmd    *Command // the command of the flag naming the output, whose Stdout is "-"
+}
+
+// IsStdout reports whether the output goes to stdout
+func (o *Output) IsStdout() bool {
+	return o.Path == "-"
+}
+
+// Open opens the destination, if it is not already open
+func (o *Output) Open() error {
+	if o.w != nil {
+		return nil
+	}
+	if o.IsStdout() {
+		o.w = os.Stdout
+		if o.cmd != nil {
+			o.w = o.cmd.Stdout()
+		}
+		return nil
+	}
+	if o.Path == "" {
+		return fmt.Errorf("%w: no output path", errParse)
+	}
+	perm := o.Perm
+	if perm == 0 {
+		perm = 0o666
+	}
+	if o.MkdirAll {
+		if err := os.MkdirAll(filepath.Dir(o.Path), 0o777); err != nil {
+			return err
+		}
+	}
+	var (
+		f   *os.File
+		err error
+	)
+	if o.Atomic {
+		// the temporary file's mode is set explicitly, bypassing the umask, so keep
+		// the mode of the file being replaced, or settle for 0o644, unless Perm is set
+		if o.Perm == 0 {
+			perm = 0o644
+			if info, err := os.Stat(o.Path); err == nil {
+				perm = info.Mode().Perm()
+			}
+		}
+		if f, err = os.CreateTemp(filepath.Dir(o.Path), "."+filepath.Base(o.Path)+".*"); err == nil {
+			err = f.Chmod(perm)
+		}
+	} else {
+		f, err = os.OpenFile(o.Path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	}
+	if err != nil {
+		if f != nil {
+			f.Close()
+			os.Remove(f.Name())
+		}
+		return err
+	}
+	o.file, o.w = f, f
+	return nil
+}
+
+func (o *Output) Write(p []byte) (int, error) {
+	if err := o.Open(); err != nil {
+		return 0, err
+	}
+	return o.w.Write(p)
+}
+
+// Close closes the destination, renaming an atomic output's temporary file over its path.
+// Stdout is left open. Closing an output that was never opened does nothing.
+func (o *Output) Close() error {
+	f := o.file
+	o.w, o.file = nil, nil
+	if f == nil {
+		return nil
+	}
+	err := f.Close()
+	if o.Atomic {
+		if err == nil {
+			err = os.Rename(f.Name(), o.Path)
+		}
+		if err != nil {
+			os.Remove(f.Name())
+		}
+	}
+	return err
+}
+
+// Abort closes the destination, discarding an atomic output's temporary file
+// so that the file at its path is left untouched.
+func (o *Output) Abort() error {
+	f := o.file
+	o.w, o.file = nil, nil
+	if f == nil {
+		return nil
+	}
+	err := f.Close()
+	if o.Atomic {
+		err = errors.Join(err, os.Remove(f.Name()))
+	}
+	return err
+}
+
+// -- Output Value
+type outputValue struct {
+	p *Output
+}
+
+func newOutputValue(val string, p *Output, c *Command) *outputValue {
+	p.Path, p.cmd = val, c
+	return &outputValue{p: p}
+}
+
+func (o *outputValue) Set(s string) error {
+	if s == "" {
+		return fmt.Errorf("%w: empty output path", errParse)
+	}
+	o.p.Path = s
+	return nil
+}
+
+func (o *outputValue) Get() any { return o.p }
+
+func (o *outputValue) String() string {
+	if o.p == nil {
+		return ""
+	}
+	return o.p.Path
+}
+
+func (o *outputValue) IsBool() bool { return false }
+
+func (o *outputValue) clone() Getter {
+	p := *o.p
+	p.w, p.file = nil, nil
+	return &outputValue{p: &p}
+}
+
+// OutFile defines an Output flag with specified name, default path, and usage string.
+// The argument p points to an Output, whose options are kept, in which to store the flag's path.
+// A path of "-" means the command's Stdout. Nothing is opened until the Output is written to or opened.
+func (c *Command) OutFile(p *Output, name string, value string, usage string, short bool) *Flag {
+	return c.Var(newOutputValue(value, p, c), name, usage, short)
+}
@@ -0,0 +1,68 @@
+package mandy
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOutFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "nested", "report.txt")
+
+	out := Output{MkdirAll: true, Atomic: true, Perm: 0o640}
+	c := NewCommand("report", ContinueOnError)
+	c.OutFile(&out, "out", "-", "where to write the report", true)
+	if !out.IsStdout() {
+		t.Errorf("default should be stdout, got %q", out.Path)
+	}
+	if err := c.Parse("-o", path); err != nil {
+		t.Fatal(err)
+	}
+	fmt.Fprint(&out, "partial")
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("atomic output should not appear before Close")
+	}
+	if err := out.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "partial" {
+		t.Errorf("got %q, %v", data, err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o640 {
+		t.Errorf("permissions: %v, %v", info.Mode(), err)
+	}
+
+	fmt.Fprint(&out, "replacement")
+	if err := out.Abort(); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "partial" {
+		t.Errorf("Abort should leave the original, got %q", data)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+		t.Errorf("temporary files were left behind: %v", entries)
+	}
+}
+
+func TestOutFileStdout(t *testing.T) {
+	var out Output
+	var stdout strings.Builder
+	c := NewCommand("report", ContinueOnError)
+	c.SetStdout(&stdout)
+	c.OutFile(&out, "out", "-", "where to write the report", true)
+	fmt.Fprint(&out, "to the command")
+	if err := out.Close(); err != nil || stdout.String() != "to the command" {
+		t.Errorf("wrote %q to the command's stdout, %v", stdout.String(), err)
+	}
+
+	var cloned strings.Builder
+	clone := c.Clone()
+	clone.SetStdout(&cloned)
+	fmt.Fprint(clone.Lookup("out").Value.Get().(*Output), "to the clone")
+	if cloned.String() != "to the clone" {
+		t.Errorf("wrote %q to the clone's stdout", cloned.String())
+	}
+}
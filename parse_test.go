@@ -0,0 +1,193 @@
+package mandy
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseForms(t *testing.T) {
+	var (
+		name    string
+		num     int
+		verbose bool
+		quiet   bool
+	)
+	c := NewCommand("test", ContinueOnError)
+	c.String(&name, "name", "", "a name", false)
+	c.Int(&num, "num", 0, "a number", true)
+	c.Bool(&verbose, "verbose", false, "be loud", true)
+	c.Bool(&quiet, "quiet", true, "be quiet", false)
+
+	err := c.Parse("--name=gopher", "-v", "--quiet=false", "--num", "3", "free", "--name=ignored")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "gopher" || num != 3 || !verbose || quiet {
+		t.Errorf("got name=%q num=%d verbose=%t quiet=%t", name, num, verbose, quiet)
+	}
+	if got := c.Args(); len(got) != 2 || got[0] != "free" {
+		t.Errorf("unexpected args %q", got)
+	}
+	if c.NFlag() != 4 {
+		t.Errorf("NFlag() = %d, want 4", c.NFlag())
+	}
+}
+
+func TestParseTerminator(t *testing.T) {
+	var verbose bool
+	c := NewCommand("test", ContinueOnError)
+	c.Bool(&verbose, "verbose", false, "be loud", true)
+
+	if err := c.Parse("--", "-v"); err != nil {
+		t.Fatal(err)
+	}
+	if verbose || c.NArg() != 1 || c.Arg(0) != "-v" {
+		t.Errorf("got verbose=%t args=%q", verbose, c.Args())
+	}
+}
+
+func TestReparseLeaf(t *testing.T) {
+	root := NewCommand("tool", ContinueOnError)
+	remote := root.NewChild("remote")
+	add := remote.NewChild("add")
+	for _, tc := range []struct {
+		args []string
+		want *Command
+	}{
+		{[]string{"remote", "add"}, add},
+		{[]string{"remote"}, remote},
+		{[]string{"--"}, root},
+	} {
+		if err := root.Parse(tc.args...); err != nil {
+			t.Fatal(err)
+		}
+		if got := root.leaf(); got != tc.want {
+			t.Errorf("Parse(%q) dispatched to %q, want %q", tc.args, got.name, tc.want.name)
+		}
+	}
+}
+
+func TestBareAssignments(t *testing.T) {
+	var (
+		cc    string
+		debug bool
+	)
+	c := NewCommand("build", ContinueOnError)
+	c.String(&cc, "cc", "gcc", "c compiler", false)
+	c.Bool(&debug, "debug", false, "debug build", false)
+
+	if err := c.Parse("cc=clang"); err != nil {
+		t.Fatal(err)
+	}
+	if cc != "gcc" || c.Arg(0) != "cc=clang" {
+		t.Errorf("assignment parsed without BareAssignments: cc=%q args=%q", cc, c.Args())
+	}
+
+	c.BareAssignments = true
+	if err := c.Parse("cc=clang", "debug=true", "other=1", "cc=tcc"); err != nil {
+		t.Fatal(err)
+	}
+	if cc != "clang" || !debug {
+		t.Errorf("got cc=%q debug=%t", cc, debug)
+	}
+	if got := c.Args(); len(got) != 2 || got[0] != "other=1" || got[1] != "cc=tcc" {
+		t.Errorf("unexpected args %q", got)
+	}
+
+	if err := c.Parse("--", "cc=icc"); err != nil {
+		t.Fatal(err)
+	}
+	if cc != "clang" || c.Arg(0) != "cc=icc" {
+		t.Errorf("assignment parsed after terminator: cc=%q args=%q", cc, c.Args())
+	}
+}
+
+func TestParseValuesWithEquals(t *testing.T) {
+	var filter, msg string
+	c := NewCommand("test", ContinueOnError)
+	c.BareAssignments = true
+	c.String(&filter, "filter", "", "", true)
+	c.String(&msg, "msg", "", "", false)
+
+	for _, args := range [][]string{
+		{"--filter=name=foo", "--msg=a b"},
+		{"-f=name=foo", "--msg", "a b"},
+		{"filter=name=foo", "msg=a b"},
+		{"-f", "name=foo", "--msg=a b"},
+	} {
+		filter, msg = "", ""
+		if err := c.Parse(args...); err != nil {
+			t.Errorf("%q: %v", args, err)
+		} else if filter != "name=foo" || msg != "a b" {
+			t.Errorf("%q: got filter=%q msg=%q", args, filter, msg)
+		}
+	}
+
+	filter, msg = "", ""
+	if err := c.ParseLine(`--filter=name=foo --msg="a b" rest`); err != nil {
+		t.Fatal(err)
+	}
+	if filter != "name=foo" || msg != "a b" || c.NArg() != 1 || c.Arg(0) != "rest" {
+		t.Errorf("ParseLine: got filter=%q msg=%q args=%q", filter, msg, c.Args())
+	}
+}
+
+func TestSplitLine(t *testing.T) {
+	for line, want := range map[string][]string{
+		``:                            nil,
+		`  a   b  `:                   {"a", "b"},
+		`--msg="a b"`:                 {"--msg=a b"},
+		`--msg='a "b" \c'`:            {`--msg=a "b" \c`},
+		`"say \"hi\" \n" x`:           {`say "hi" \n`, "x"},
+		`a\ b ''`:                     {"a b", ""},
+		`--filter=name=foo --x="=y="`: {"--filter=name=foo", "--x==y="},
+	} {
+		got, err := SplitLine(line)
+		if err != nil {
+			t.Errorf("%q: %v", line, err)
+		} else if !slices.Equal(got, want) {
+			t.Errorf("%q: got %q, want %q", line, got, want)
+		}
+	}
+	for _, line := range []string{`"open`, `'open`, `trailing\`} {
+		if _, err := SplitLine(line); err == nil {
+			t.Errorf("%q should not split", line)
+		}
+	}
+}
+
+func TestNoOptDefVal(t *testing.T) {
+	for _, test := range []struct {
+		args       []string
+		color      string
+		positional []string
+	}{
+		{[]string{"--"}, "never", nil},
+		{[]string{"--color"}, "auto", nil},
+		{[]string{"--color=always"}, "always", nil},
+		{[]string{"--color", "file"}, "auto", []string{"file"}},
+		{[]string{"-cv"}, "auto", nil},
+		{[]string{"-c=always"}, "always", nil},
+	} {
+		var color string
+		var verbose bool
+		c := NewCommand("test", ContinueOnError)
+		c.String(&color, "color", "never", "when to colorize output", true).NoOptDefVal = "auto"
+		c.Bool(&verbose, "verbose", false, "", true)
+		if err := c.Parse(test.args...); err != nil {
+			t.Errorf("Parse(%q): %v", test.args, err)
+			continue
+		}
+		if color != test.color || strings.Join(c.Args(), " ") != strings.Join(test.positional, " ") {
+			t.Errorf("Parse(%q): color = %q, args = %q, want %q, %q", test.args, color, c.Args(), test.color, test.positional)
+		}
+	}
+
+	var color string
+	c := NewCommand("test", ContinueOnError)
+	c.String(&color, "color", "never", "when to colorize output", false).NoOptDefVal = "auto"
+	if want := "--color[=auto]\twhen to colorize output"; !strings.Contains(c.Defaults(), want) {
+		t.Errorf("Defaults() = %q, want it to contain %q", c.Defaults(), want)
+	}
+}
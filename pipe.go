@@ -0,0 +1,119 @@
+package mandy
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+)
+
+// NullName is the name of the flag registered by NullDelimited, so that it reads as -0, as with xargs
+const NullName = "0"
+
+// Input returns the source from which ArgsOrStdin, and InFile flags given "-", read. The parent's input is returned if
+// input was not set or was set to nil, and os.Stdin if no ancestor's was set either.
+func (c *Command) Input() io.Reader {
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		if cmd.input != nil {
+			return cmd.input
+		}
+	}
+	return os.Stdin
+}
+
+// SetInput sets the source from which ArgsOrStdin, and InFile flags given "-", read.
+// If input is nil, the parent's input, or os.Stdin, is used.
+func (c *Command) SetInput(input io.Reader) {
+	c.input = input
+}
+
+// NullDelimited registers the -0 flag, which makes ArgsOrStdin split its input on NUL
+// rather than newline characters, for use with find -print0 and the like
+func (c *Command) NullDelimited() *Flag {
+	return c.Bool(new(bool), NullName, false, "input items are terminated by NUL, not newline, characters", false)
+}
+
+// ArgsOrStdin returns an iterator over the non-flag arguments or, if there are none,
+// the non-empty items read from the command's input, so that "cmd a b c" and "... | cmd" behave alike.
+// Items are newline delimited unless the -0 flag registered by NullDelimited is set.
+// Nothing is read if the input is a terminal. Read errors are handled as per the command's ErrorPolicy.
+func (c *Command) ArgsOrStdin() iter.Seq[string] {
+	if len(c.args) > 0 {
+		return c.ArgSeq()
+	}
+	return func(yield func(string) bool) {
+		input := c.Input()
+		if interactive(input) {
+			return
+		}
+		scanner := bufio.NewScanner(input)
+		if f := c.Lookup(NullName); f != nil && f.Value.Get() == true {
+			scanner.Split(scanNull)
+		}
+		for scanner.Scan() {
+			if item := scanner.Text(); item != "" && !yield(item) {
+				return
+			}
+		}
+		c.Handle(scanner.Err())
+	}
+}
+
+// scanNull is a bufio.SplitFunc yielding NUL terminated items
+func scanNull(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if i := bytes.IndexByte(data, 0); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+// Print0Name is the name of the flag registered by Print0, after find's -print0
+const Print0Name = "print0"
+
+// Stdout returns the destination of PrintPath, and of OutFile flags given "-". The parent's is returned if it was not set,
+// or was set to nil, and os.Stdout if no ancestor's was set either.
+func (c *Command) Stdout() io.Writer {
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		if cmd.stdout != nil {
+			return cmd.stdout
+		}
+	}
+	return os.Stdout
+}
+
+// SetStdout sets the destination of PrintPath, and of OutFile flags given "-".
+// If stdout is nil, the parent's, or os.Stdout, is used.
+func (c *Command) SetStdout(stdout io.Writer) {
+	c.stdout = stdout
+}
+
+// Print0 registers the --print0 flag, which makes PrintPath terminate paths with NUL
+// rather than newline characters, for use with xargs -0 and the like.
+// Unlike the help flag, it isn't registered by NewCommand: most commands print no paths,
+// and their usage messages, and the names free for their own flags, shouldn't change.
+// Call it on each command that uses PrintPath, or on an ancestor if GlobalOptions is set;
+// PrintPath fails on commands without it.
+func (c *Command) Print0() *Flag {
+	return c.Bool(new(bool), Print0Name, false, "terminate printed paths with NUL, not newline, characters", false)
+}
+
+// PrintPath writes p to the command's stdout followed by a newline or, if the --print0 flag registered by
+// Print0 is set, a NUL. It returns an error, writing nothing, if the command can't be given --print0,
+// since a pipeline expecting NUL terminated paths would otherwise silently receive newline terminated ones.
+func (c *Command) PrintPath(p string) error {
+	_, f := c.reach(func(cmd *Command) *Flag { return cmd.formal[Print0Name] })
+	if f == nil {
+		return fmt.Errorf("mandy: %s prints paths without a --%s flag, see Print0", c.name, Print0Name)
+	}
+	term := "\n"
+	if f.Value.Get() == true {
+		term = "\x00"
+	}
+	_, err := io.WriteString(c.Stdout(), p+term)
+	return err
+}
@@ -0,0 +1,84 @@
+package mandy
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestArgsOrStdin(t *testing.T) {
+	collect := func(c *Command) (out []string) {
+		for item := range c.ArgsOrStdin() {
+			out = append(out, item)
+		}
+		return
+	}
+
+	c := NewCommand("xargs", ContinueOnError)
+	c.NullDelimited()
+	c.SetInput(strings.NewReader("ignored\n"))
+	if err := c.Parse("a", "b"); err != nil {
+		t.Fatal(err)
+	}
+	if got := collect(c); !slices.Equal(got, []string{"a", "b"}) {
+		t.Errorf("positionals: got %q", got)
+	}
+
+	c = NewCommand("xargs", ContinueOnError)
+	c.NullDelimited()
+	c.SetInput(strings.NewReader("one\n\ntwo words\nthree"))
+	if err := c.Parse("--"); err != nil {
+		t.Fatal(err)
+	}
+	if got := collect(c); !slices.Equal(got, []string{"one", "two words", "three"}) {
+		t.Errorf("newline delimited: got %q", got)
+	}
+
+	c = NewCommand("xargs", ContinueOnError)
+	c.NullDelimited()
+	c.SetInput(strings.NewReader("new\nline\x00tab\tbed\x00"))
+	if err := c.Parse("-0"); err != nil {
+		t.Fatal(err)
+	}
+	if got := collect(c); !slices.Equal(got, []string{"new\nline", "tab\tbed"}) {
+		t.Errorf("NUL delimited: got %q", got)
+	}
+}
+
+func TestPrintPath(t *testing.T) {
+	var out strings.Builder
+	c := NewCommand("find", ContinueOnError)
+	c.SetStdout(&out)
+	c.Print0()
+	c.PrintPath("plain")
+	if err := c.Parse("--print0"); err != nil {
+		t.Fatal(err)
+	}
+	c.PrintPath("with\nnewline")
+	if got, want := out.String(), "plain\nwith\nnewline\x00"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	out.Reset()
+	bare := NewCommand("find", ContinueOnError)
+	bare.SetStdout(&out)
+	if err := bare.PrintPath("plain"); err == nil || out.Len() != 0 {
+		t.Errorf("printing a path without --print0 gave %v and wrote %q", err, out.String())
+	}
+}
+
+func TestPrintPathGlobal(t *testing.T) {
+	var out strings.Builder
+	root := NewCommand("tool", ContinueOnError)
+	root.GlobalOptions = true
+	root.Print0()
+	child := root.NewChild("ls")
+	child.SetStdout(&out)
+	child.Main = func(c *Command) error { return c.PrintPath("a b") }
+	if err := root.Execute("ls", "--print0"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := out.String(), "a b\x00"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,65 @@
+package mandy
+
+import "fmt"
+
+// A Registrar is the part of a Command that plugins are given to register commands and flags with
+type Registrar interface {
+	NewChild(name string, summary ...string) *Command
+	Mount(prefix string, group FlagGroup) error
+}
+
+// pluginRegistrar registers children and flags with a command on behalf of a plugin, recording their provenance
+type pluginRegistrar struct {
+	c      *Command
+	plugin string
+}
+
+// NewChild adds a child to the command, recording that the plugin added it, and all that is added to it
+func (r pluginRegistrar) NewChild(name string, summary ...string) *Command {
+	child := r.c.NewChild(name, summary...)
+	child.addedBy = r.plugin
+	return child
+}
+
+// Mount mounts the group on the command, recording that the plugin added its flags
+func (r pluginRegistrar) Mount(prefix string, group FlagGroup) error {
+	before := make(map[*Flag]bool, len(r.c.formal))
+	for _, flag := range r.c.formal {
+		before[flag] = true
+	}
+	if err := r.c.Mount(prefix, group); err != nil {
+		return err
+	}
+	for _, flag := range r.c.formal {
+		if !before[flag] {
+			flag.addedBy = r.plugin
+		}
+	}
+	return nil
+}
+
+// LoadPlugin calls the plugin's init function with a Registrar for the command, so that the plugin
+// can add children to it and mount flag groups on it, but nothing else. What the plugin adds is
+// recorded as added by it; see AddedBy. Panics raised by init, such as those registering a taken
+// name or registering on a sealed command, are returned as errors.
+func (c *Command) LoadPlugin(name string, init func(Registrar) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if e, ok := r.(error); ok {
+				err = fmt.Errorf("plugin %s: %w", name, e)
+			} else {
+				err = fmt.Errorf("plugin %s: %v", name, r)
+			}
+		}
+	}()
+	if err := init(pluginRegistrar{c, name}); err != nil {
+		return fmt.Errorf("plugin %s: %w", name, err)
+	}
+	return nil
+}
+
+// AddedBy names the plugin that added the command, or one of its ancestors, if any
+func (c *Command) AddedBy() string { return c.addedBy }
+
+// AddedBy names the plugin that added the flag, if any
+func (f *Flag) AddedBy() string { return f.addedBy }
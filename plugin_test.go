@@ -0,0 +1,46 @@
+package mandy
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestLoadPlugin(t *testing.T) {
+	root := NewCommand("host", ContinueOnError)
+	root.SetOutput(io.Discard)
+	root.NewChild("status", "show the status")
+
+	err := root.LoadPlugin("deployer", func(r Registrar) error {
+		deploy := r.NewChild("deploy", "ship it")
+		deploy.String(new(string), "target", "", "where to deploy", false)
+		deploy.NewChild("rollback", "undo it")
+		return r.Mount("http", &HTTPClient{})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	deploy := root.child("deploy")
+	if deploy.AddedBy() != "deployer" || deploy.child("rollback").AddedBy() != "deployer" {
+		t.Errorf("commands added by the plugin: %q, %q", deploy.AddedBy(), deploy.child("rollback").AddedBy())
+	}
+	if deploy.Lookup("target").AddedBy() != "deployer" || root.Lookup("http-timeout").AddedBy() != "deployer" {
+		t.Error("flags added by the plugin aren't attributed to it")
+	}
+	if root.child("status").AddedBy() != "" || root.Lookup(HelpName).AddedBy() != "" {
+		t.Error("the host's own commands and flags are attributed to a plugin")
+	}
+	if usage := root.UsageString(); !strings.Contains(usage, "\tdeploy\tship it (plugin deployer)\n") {
+		t.Errorf("usage doesn't attribute deploy to its plugin:\n%s", usage)
+	}
+
+	root.Seal()
+	err = root.LoadPlugin("late", func(r Registrar) error {
+		r.NewChild("late", "")
+		return nil
+	})
+	if !errors.Is(err, ErrSealed) {
+		t.Errorf("registering on a sealed command: err = %v, want ErrSealed", err)
+	}
+}
@@ -0,0 +1,51 @@
+package mandy
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A PrefixHandler interprets an argument beginning with a prefix registered by Command.HandlePrefix,
+// given the rest of the argument, as with +x toggles, or @name selections, in legacy command lines
+type PrefixHandler func(rest string) error
+
+// HandlePrefix makes Parse pass the arguments beginning with prefix, and something more, to fn, rather than
+// treating them as positional. Like flags, they are recognised only before the first positional argument and "--".
+// Where registered prefixes overlap, the longest wins. It panics if prefix is empty or begins with a dash.
+func (c *Command) HandlePrefix(prefix string, fn PrefixHandler) *Command {
+	if prefix == "" || strings.HasPrefix(prefix, "-") {
+		panic(c.sprintf("cannot handle prefix %q", prefix))
+	}
+	if c.prefixes == nil {
+		c.prefixes = make(map[string]PrefixHandler)
+	}
+	c.prefixes[prefix] = fn
+	return c
+}
+
+// prefixed returns the handler for the longest registered prefix of arg, and the rest of arg, if there is one
+func (c *Command) prefixed(arg string) (PrefixHandler, string) {
+	var longest string
+	for prefix := range c.prefixes {
+		if len(prefix) > len(longest) && len(arg) > len(prefix) && strings.HasPrefix(arg, prefix) {
+			longest = prefix
+		}
+	}
+	if longest == "" {
+		return nil, ""
+	}
+	return c.prefixes[longest], arg[len(longest):]
+}
+
+// handlePrefixed passes the pending argument to its prefix's handler, if it has one, reporting whether it did
+func (c *Command) handlePrefixed(arg string) (bool, error) {
+	fn, rest := c.prefixed(arg)
+	if fn == nil {
+		return false, nil
+	}
+	c.args = c.args[1:]
+	if err := fn(rest); err != nil {
+		return true, fmt.Errorf("invalid argument %s: %w", arg, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,44 @@
+package mandy
+
+import (
+	"errors"
+	"io"
+	"slices"
+	"testing"
+)
+
+func TestHandlePrefix(t *testing.T) {
+	var trace, verbose bool
+	var profile string
+	var toggled []string
+	c := NewCommand("test", ContinueOnError)
+	c.SetOutput(io.Discard)
+	c.Bool(&trace, "trace", true, "", true)
+	c.Bool(&verbose, "verbose", false, "", true)
+	c.HandlePrefix("+", func(rest string) error {
+		toggled = append(toggled, rest)
+		return c.Set(rest, "false")
+	})
+	c.HandlePrefix("@", func(rest string) error { profile = rest; return nil })
+	c.HandlePrefix("@@", func(rest string) error { return errors.New("doubled") })
+
+	if err := c.Parse("+trace", "-v", "@staging", "+", "+verbose"); err != nil {
+		t.Fatal(err)
+	}
+	if trace || !verbose || profile != "staging" || !slices.Equal(toggled, []string{"trace"}) {
+		t.Errorf("trace, verbose, profile, toggled = %t, %t, %q, %q", trace, verbose, profile, toggled)
+	}
+	if args := c.Args(); !slices.Equal(args, []string{"+", "+verbose"}) {
+		t.Errorf("Args() = %q, want prefixed arguments after positional ones left alone", args)
+	}
+	if err := c.Parse("@@x"); err == nil || err.Error() != "invalid argument @@x: doubled" {
+		t.Errorf("Parse(@@x) = %v, want the longest prefix's error", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("HandlePrefix(-) did not panic")
+		}
+	}()
+	c.HandlePrefix("-", nil)
+}
@@ -0,0 +1,106 @@
+package mandy
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// ioClasses maps the names accepted by --ionice-class to the linux IO scheduling classes
+var ioClasses = map[string]int{"none": 0, "realtime": 1, "best-effort": 2, "idle": 3}
+
+// Priority is a FlagGroup of the settings with which batch jobs yield to interactive work:
+// --nice and --umask where the platform has them, and --ionice-class where it has IO scheduling classes.
+// Execute applies the settings before running the Main of the command the group is mounted on, or of one
+// of its descendants, and restores the umask when Main returns. Its zero value leaves everything as it is;
+// set fields before mounting to be polite by default.
+type Priority struct {
+	Nice    int    // the niceness to run at, from -20 to 19; raising it may need privileges, 0 keeps the inherited one
+	IOClass string // "realtime", "best-effort", "idle", or "none"; "" keeps the inherited class
+	Umask   string // octal file mode creation mask, such as "022"; "" keeps the inherited one
+}
+
+// Register defines the group's flags on c
+func (p *Priority) Register(c *Command, prefix string) {
+	var names [3]string
+	if niceSupported {
+		names[0] = Prefixed(prefix, "nice")
+		c.IntRange(&p.Nice, names[0], p.Nice, -20, 19, "scheduling `niceness` to run at, 0 to inherit it", false)
+		names[2] = Prefixed(prefix, "umask")
+		c.Var((*umaskValue)(&p.Umask), names[2], "octal file mode creation `mask`, such as 022", false)
+	}
+	if ioClassSupported {
+		names[1] = Prefixed(prefix, "ionice-class")
+		c.Enum(&p.IOClass, names[1], p.IOClass, "IO scheduling `class`", false, "realtime", "best-effort", "idle", "none")
+	}
+	c.setups = append(c.setups, func(cmd *Command) (func(), error) {
+		return applyPriority(cmd, names)
+	})
+}
+
+// applyPriority applies the settings held by the named flags, read through cmd so that clones use their own values
+func applyPriority(cmd *Command, names [3]string) (restore func(), err error) {
+	restore = func() {}
+	value := func(name string) string {
+		if flag := cmd.formal[name]; name != "" && flag != nil {
+			return flag.Value.String()
+		}
+		return ""
+	}
+	if nice, _ := strconv.Atoi(value(names[0])); nice != 0 {
+		if err := setNice(nice); err != nil {
+			return restore, fmt.Errorf("setting niceness %d: %w", nice, err)
+		}
+	}
+	if class := value(names[1]); class != "" {
+		if err := setIOClass(ioClasses[class]); err != nil {
+			return restore, fmt.Errorf("setting IO class %s: %w", class, err)
+		}
+	}
+	if mask := value(names[2]); mask != "" {
+		m, err := strconv.ParseUint(mask, 8, 32)
+		if err != nil || os.FileMode(m) > os.ModePerm {
+			return restore, fmt.Errorf("%w: invalid umask %q", errParse, mask)
+		}
+		old := setUmask(int(m))
+		restore = func() { setUmask(old) }
+	}
+	return restore, nil
+}
+
+// -- umask Value
+// an octal file mode creation mask, kept as text so that "" can mean the inherited one
+type umaskValue string
+
+func (u *umaskValue) Set(s string) error {
+	if m, err := strconv.ParseUint(s, 8, 32); err != nil || os.FileMode(m) > os.ModePerm {
+		return fmt.Errorf("%w: invalid umask %q", errParse, s)
+	}
+	*u = umaskValue(s)
+	return nil
+}
+
+func (u *umaskValue) Get() any       { return string(*u) }
+func (u *umaskValue) String() string { return string(*u) }
+func (u *umaskValue) IsBool() bool   { return false }
+
+// prepare runs the setups registered on the path from c to the child dispatched to by the last parse,
+// returning a function undoing them in reverse
+func (c *Command) prepare() (undo func(), err error) {
+	var restores []func()
+	undo = func() {
+		for i := len(restores) - 1; i >= 0; i-- {
+			restores[i]()
+		}
+	}
+	for cmd := c; cmd != nil; cmd = cmd.sub {
+		for _, setup := range cmd.setups {
+			restore, err := setup(cmd)
+			restores = append(restores, restore)
+			if err != nil {
+				return undo, err
+			}
+		}
+	}
+	return undo, nil
+}
@@ -0,0 +1,19 @@
+//go:build !unix
+
+package mandy
+
+import (
+	"errors"
+)
+
+const niceSupported = false
+
+// setNice reports errors.ErrUnsupported; the platform has no niceness
+func setNice(nice int) error {
+	return errors.ErrUnsupported
+}
+
+// setUmask does nothing; the platform has no file mode creation mask
+func setUmask(mask int) int {
+	return mask
+}
@@ -0,0 +1,80 @@
+package mandy
+
+import (
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestPriority(t *testing.T) {
+	if !niceSupported {
+		t.Skip("the platform has no niceness or umask")
+	}
+	var p Priority
+	c := NewCommand("test", ContinueOnError)
+	c.SetOutput(io.Discard)
+	if err := c.Mount("", &p); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.formal["ionice-class"]; ok != ioClassSupported {
+		t.Errorf("ionice-class registered = %t, want %t", ok, ioClassSupported)
+	}
+	child := c.NewChild("child", "")
+	var during int
+	child.Main = func(self *Command) error {
+		during = setUmask(0o022)
+		setUmask(during)
+		return nil
+	}
+
+	before := setUmask(0o022)
+	defer setUmask(before)
+	if err := c.Execute("--umask", "077", "child"); err != nil {
+		t.Fatal(err)
+	}
+	if during != 0o077 {
+		t.Errorf("umask during Main = %#o, want 077", during)
+	}
+	if after := setUmask(0o022); after != 0o022 {
+		t.Errorf("umask after Execute = %#o, want 022", after)
+	}
+
+	for _, args := range [][]string{{"--umask", "999"}, {"--nice", "30"}, {"--ionice-class", "bogus"}} {
+		if err := c.Execute(append(args, "child")...); err == nil {
+			t.Errorf("Execute(%q) succeeded", args)
+		}
+	}
+}
+
+func TestPriorityRollback(t *testing.T) {
+	if !niceSupported {
+		t.Skip("the platform has no niceness or umask")
+	}
+	c := NewCommand("test", ContinueOnError)
+	c.SetOutput(io.Discard)
+	c.Bool(new(bool), "taken", false, "", false)
+	if err := c.Mount("", setupThenConflict{}); !errors.Is(err, ErrConflict) {
+		t.Fatalf("Mount = %v, want ErrConflict", err)
+	}
+	if len(c.setups) != 0 {
+		t.Errorf("a failed Mount left %d setups", len(c.setups))
+	}
+
+	c = NewCommand("test", ContinueOnError)
+	c.SetOutput(io.Discard)
+	if err := c.Mount("", &Priority{Umask: "999"}); err != nil {
+		t.Fatal(err)
+	}
+	c.Main = func(*Command) error { return nil }
+	if err := c.Execute("--"); !errors.Is(err, errParse) {
+		t.Errorf("an invalid default umask: err = %v, want a parse error", err)
+	}
+}
+
+// setupThenConflict is a FlagGroup whose registration fails after it has registered a setup
+type setupThenConflict struct{}
+
+func (setupThenConflict) Register(c *Command, prefix string) {
+	new(Priority).Register(c, prefix)
+	c.Bool(new(bool), "taken", false, "", false)
+}
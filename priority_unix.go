@@ -0,0 +1,19 @@
+//go:build unix
+
+package mandy
+
+import (
+	"syscall"
+)
+
+const niceSupported = true
+
+// setNice sets the niceness of the process
+func setNice(nice int) error {
+	return syscall.Setpriority(syscall.PRIO_PROCESS, 0, nice)
+}
+
+// setUmask sets the file mode creation mask of the process, returning the previous one
+func setUmask(mask int) int {
+	return syscall.Umask(mask)
+}
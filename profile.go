@@ -0,0 +1,127 @@
+package mandy
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ProfileEnv is the environment variable that, when set, makes mandy time the construction,
+// flag registration, and parsing of commands, for Profile to report
+const ProfileEnv = "MANDY_PROFILE"
+
+// profiler accumulates the time spent on each command while ProfileEnv is set
+var profiler = struct {
+	sync.Mutex
+	enabled bool
+	entries map[*Command]*profileEntry
+}{
+	enabled: os.Getenv(ProfileEnv) != "",
+	entries: make(map[*Command]*profileEntry),
+}
+
+// profileEntry is the time spent on a command in each phase
+type profileEntry struct {
+	construct, register, parse time.Duration
+	flags, parses              int
+}
+
+func (e *profileEntry) total() time.Duration { return e.construct + e.register + e.parse }
+
+// profilePhase names the phases of a command's life that Profile times
+type profilePhase uint8
+
+const (
+	phaseConstruct profilePhase = iota
+	phaseRegister
+	phaseParse
+)
+
+// profileStart returns the time a phase starts, or the zero time if profiling is disabled
+func profileStart() time.Time {
+	if !profiler.enabled {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+// profileRecord adds the time since start to the command's phase, unless profiling is disabled
+func profileRecord(c *Command, phase profilePhase, start time.Time) {
+	if start.IsZero() {
+		return
+	}
+	elapsed := time.Since(start)
+	profiler.Lock()
+	defer profiler.Unlock()
+	e := profiler.entries[c]
+	if e == nil {
+		e = new(profileEntry)
+		profiler.entries[c] = e
+	}
+	switch phase {
+	case phaseConstruct:
+		e.construct += elapsed
+	case phaseRegister:
+		e.register += elapsed
+		e.flags++
+	case phaseParse:
+		e.parse += elapsed
+		e.parses++
+	}
+}
+
+// Path returns the names of the command and its ancestors, from the root down, separated by spaces
+func (c *Command) Path() string {
+	return c.path()
+}
+
+// path is the names of the command and its ancestors, from the root down
+func (c *Command) path() string {
+	names := []string{c.name}
+	for p := c.parent; p != nil; p = p.parent {
+		names = append(names, p.name)
+	}
+	slices.Reverse(names)
+	return strings.Join(names, " ")
+}
+
+// Profile writes the time spent constructing, registering the flags of, and parsing each command
+// to w, slowest first, if the MANDY_PROFILE environment variable is set. It does nothing otherwise.
+// Construction includes the registration of the help flag, and parsing includes the children's.
+func Profile(w io.Writer) error {
+	if !profiler.enabled {
+		return nil
+	}
+	profiler.Lock()
+	type row struct {
+		path string
+		profileEntry
+	}
+	rows := make([]row, 0, len(profiler.entries))
+	var sum profileEntry
+	for c, e := range profiler.entries {
+		rows = append(rows, row{c.path(), *e})
+		sum.construct += e.construct
+		sum.register += e.register
+		sum.flags += e.flags
+	}
+	profiler.Unlock()
+	slices.SortFunc(rows, func(a, b row) int {
+		return cmp.Or(cmp.Compare(b.total(), a.total()), cmp.Compare(a.path, b.path))
+	})
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "mandy profile: %d commands, %d flags, %v constructing, %v registering\n",
+		len(rows), sum.flags, sum.construct, sum.register)
+	for _, r := range rows {
+		fmt.Fprintf(&out, "\t%s\tconstruct %v\tregister %v (%d flags)\tparse %v (%d times)\n",
+			r.path, r.construct, r.register, r.flags, r.parse, r.parses)
+	}
+	_, err := io.WriteString(w, out.String())
+	return err
+}
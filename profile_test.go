@@ -0,0 +1,42 @@
+package mandy
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProfile(t *testing.T) {
+	var b strings.Builder
+	if err := Profile(&b); err != nil || b.Len() != 0 {
+		t.Fatalf("Profile wrote %q, err %v, while disabled", b.String(), err)
+	}
+
+	profiler.enabled = true
+	defer func() {
+		profiler.enabled = false
+		clear(profiler.entries)
+	}()
+	c := NewCommand("tool", ContinueOnError)
+	c.Int(new(int), "n", 0, "a number", false)
+	child := c.NewChild("run", "")
+	child.Bool(new(bool), "fast", false, "go fast", false)
+	if err := c.Parse("-n", "1", "run", "--fast"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Profile(&b); err != nil {
+		t.Fatal(err)
+	}
+	got := b.String()
+	for _, want := range []string{
+		"mandy profile: 2 commands, 4 flags",
+		"\ttool\tconstruct ",
+		"\ttool run\tconstruct ",
+		"(2 flags)\tparse ",
+		"(1 times)",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("profile lacks %q:\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package mandy
+
+import (
+	"fmt"
+	"iter"
+)
+
+// An Origin describes where a flag's value came from
+type Origin struct {
+	Source     Source // the source that set the value, or zero if the value is the default
+	Path       string // the config file a SourceConfig value was loaded from
+	Env        string // the variable a SourceEnv value was read from
+	SetDefault bool   // whether a default was chosen by Command.SetDefault, rather than the flag's constructor
+	Untrusted  bool   // whether a SourceConfig value came from a project-local file that hasn't been trusted
+}
+
+// String describes the origin, as in "the environment ($PORT)" or "a config file (/etc/tool/config)"
+func (o Origin) String() string {
+	switch {
+	case o.Source == 0 && o.SetDefault:
+		return "the default, set by the program"
+	case o.Source == 0:
+		return "the default"
+	case o.Env != "":
+		return fmt.Sprintf("%s ($%s)", o.Source, o.Env)
+	case o.Path != "":
+		return fmt.Sprintf("%s (%s)", o.Source, o.Path)
+	}
+	return o.Source.String()
+}
+
+// trusted reports whether the value came from somewhere the user controls, the command line, the environment,
+// the program itself, or a config file other than an untrusted project-local one, so that the references
+// to credentials and files it holds may be followed
+func (o Origin) trusted() bool {
+	return o.Source&(SourceCommandLine|SourceEnv|SourceProgram) != 0 || o.Source == SourceConfig && !o.Untrusted
+}
+
+// Source reports where the flag's current value came from
+func (f *Flag) Source() Origin {
+	if f.origin.Source == 0 {
+		return Origin{SetDefault: f.defaultSet}
+	}
+	return f.origin
+}
+
+// Sources returns an iterator over the command's flags, in the same order as Flags, and their values' origins
+func (c *Command) Sources() iter.Seq2[*Flag, Origin] {
+	return func(yield func(*Flag, Origin) bool) {
+		for flag := range c.Flags() {
+			if !yield(flag, flag.Source()) {
+				return
+			}
+		}
+	}
+}
+
+// settler is implemented by values whose first Set replaces their contents and whose later ones add to them;
+// settle makes the next Set a replacement again
+type settler interface {
+	settle()
+}
+
+// SetDefault replaces the default of the named flag, as though value had been passed to its constructor.
+// The flag's value changes too, unless it has been set. Neither Once nor Restrict applies, as SetDefault
+// is not an assignment.
+func (c *Command) SetDefault(name, value string) error {
+	flag, ok := c.formal[name]
+	if !ok {
+		return fmt.Errorf("no such flag -%v", name)
+	}
+	v := flag.Value
+	if _, set := c.actual[name]; set {
+		v = cloneValue(v)
+	}
+	s, settles := unwrap(v).(settler)
+	if settles {
+		s.settle()
+	}
+	if err := v.Set(value); err != nil {
+		return err
+	}
+	if settles {
+		s.settle()
+	}
+	flag.DefValue = v.String()
+	flag.defaultSet = true
+	c.invalidate()
+	return nil
+}
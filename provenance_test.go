@@ -0,0 +1,85 @@
+package mandy
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSources(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"level": "3"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var (
+		addr, name, mode string
+		level, port      int
+		tags             []string
+	)
+	c := NewCommand("tool", ContinueOnError)
+	c.SetEnviron(MapEnviron(map[string]string{"TOOL_ADDR": ":7000"}))
+	c.String(&addr, "addr", ":8080", "", false).Env("TOOL_ADDR")
+	c.String(&name, "name", "default", "", false)
+	c.String(&mode, "mode", "fast", "", false)
+	c.Int(&level, "level", 1, "", false)
+	c.Int(&port, "port", 80, "", false)
+	c.StringSlice(&tags, "tag", []string{"a"}, "", false)
+	if err := c.LoadConfig(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SetDefault("port", "81"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SetDefault("tag", "b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Parse("--name", "cli", "--tag", "c"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Set("mode", "slow"); err != nil {
+		t.Fatal(err)
+	}
+	if port != 81 || c.Lookup("port").DefValue != "81" || !reflect.DeepEqual(tags, []string{"c"}) {
+		t.Errorf("port, its default, and tags = %d, %q, %q, want 81, 81, and [c]", port, c.Lookup("port").DefValue, tags)
+	}
+
+	want := map[string]Origin{
+		"addr":  {Source: SourceEnv, Env: "TOOL_ADDR"},
+		"name":  {Source: SourceCommandLine},
+		"mode":  {Source: SourceProgram},
+		"level": {Source: SourceConfig, Path: path},
+		"port":  {SetDefault: true},
+		"tag":   {Source: SourceCommandLine},
+		"help":  {},
+	}
+	got := make(map[string]Origin)
+	for flag, origin := range c.Sources() {
+		got[flag.Name] = origin
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Sources() = %v, want %v", got, want)
+	}
+	for origin, text := range map[Origin]string{
+		want["addr"]:  "the environment ($TOOL_ADDR)",
+		want["level"]: "a config file (" + path + ")",
+		want["port"]:  "the default, set by the program",
+		want["help"]:  "the default",
+	} {
+		if origin.String() != text {
+			t.Errorf("%#v.String() = %q, want %q", origin, origin, text)
+		}
+	}
+
+	clone := c.Clone()
+	clone.Main = func(*Command) error { return nil }
+	if err := clone.Execute("--"); err != nil {
+		t.Fatal(err)
+	}
+	if origin := clone.Lookup("name").Source(); origin != (Origin{}) {
+		t.Errorf("after reparsing, --name came from %v, want the default", origin)
+	}
+	if err := c.SetDefault("level", "high"); err == nil {
+		t.Error("SetDefault with an invalid value succeeded")
+	}
+}
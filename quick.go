@@ -0,0 +1,68 @@
+package mandy
+
+import (
+	"strconv"
+	"strings"
+)
+
+// quickError reports a failed QuickParse without formatting until it's printed
+type quickError struct {
+	reason, arg string
+	err         error
+}
+
+func (e *quickError) Error() string { return "mandy: " + e.reason + ": " + e.arg }
+func (e *quickError) Unwrap() error { return e.err }
+
+// QuickParse sets the boolean flags in spec from args, returning the arguments that follow them.
+// It's a fast path for tiny utilities, which makes a single pass over args and doesn't allocate
+// unless it fails. The syntax is that of Command.Parse: "--name", "--name=value", or "-name=value",
+// where value is anything strconv.ParseBool accepts, and clusters of one-letter names, such as "-xvf".
+// Parsing stops at the first free argument, "-", or the terminator "--", which is consumed.
+// Unknown flags, and clusters naming them, fail with ErrHelp if they're "help" or "h".
+func QuickParse(spec map[string]*bool, args []string) ([]string, error) {
+	for i, arg := range args {
+		if len(arg) < 2 || arg[0] != '-' {
+			return args[i:], nil
+		}
+		if arg == "--" {
+			return args[i+1:], nil
+		}
+		if name, value, ok := strings.Cut(trimDashes(arg), "="); ok {
+			p := spec[name]
+			if p == nil {
+				return args[i:], quickUnknown(name)
+			}
+			v, err := strconv.ParseBool(value)
+			if err != nil {
+				return args[i:], &quickError{"invalid value for flag " + name, value, errParse}
+			}
+			*p = v
+			continue
+		}
+		if strings.HasPrefix(arg, "--") {
+			p := spec[arg[2:]]
+			if p == nil {
+				return args[i:], quickUnknown(arg[2:])
+			}
+			*p = true
+			continue
+		}
+		for j := 1; j < len(arg); j++ {
+			p := spec[arg[j:j+1]]
+			if p == nil {
+				return args[i:], quickUnknown(arg[j : j+1])
+			}
+			*p = true
+		}
+	}
+	return nil, nil
+}
+
+// quickUnknown is the error for a flag missing from QuickParse's spec
+func quickUnknown(name string) error {
+	if name == "help" || name == "h" {
+		return ErrHelp
+	}
+	return &quickError{"flag provided but not defined", name, nil}
+}
@@ -0,0 +1,77 @@
+package mandy
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"slices"
+	"testing"
+)
+
+func TestQuickParse(t *testing.T) {
+	var verbose, force, extract bool
+	spec := map[string]*bool{"verbose": &verbose, "f": &force, "x": &extract}
+
+	rest, err := QuickParse(spec, []string{"--verbose", "-xf", "--f=false", "archive", "-x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !verbose || force || !extract {
+		t.Errorf("verbose, force, extract = %v, %v, %v, want true, false, true", verbose, force, extract)
+	}
+	if !slices.Equal(rest, []string{"archive", "-x"}) {
+		t.Errorf("rest = %q", rest)
+	}
+
+	if rest, _ := QuickParse(spec, []string{"-x", "--", "-f"}); !slices.Equal(rest, []string{"-f"}) {
+		t.Errorf("after --, rest = %q", rest)
+	}
+
+	for _, test := range []struct {
+		args []string
+		want error
+	}{
+		{[]string{"--quiet"}, nil},
+		{[]string{"-xq"}, nil},
+		{[]string{"--verbose=maybe"}, errParse},
+		{[]string{"-h"}, ErrHelp},
+	} {
+		_, err := QuickParse(spec, test.args)
+		if err == nil || (test.want != nil && !errors.Is(err, test.want)) {
+			t.Errorf("QuickParse(%q) = %v, want %v", test.args, err, test.want)
+		}
+	}
+}
+
+func TestQuickParseAllocs(t *testing.T) {
+	var a, b, c bool
+	spec := map[string]*bool{"all": &a, "b": &b, "c": &c}
+	args := []string{"--all", "-bc", "--all=false", "file"}
+	if n := testing.AllocsPerRun(100, func() { QuickParse(spec, args) }); n != 0 {
+		t.Errorf("QuickParse allocated %v times per run", n)
+	}
+}
+
+var quickArgs = []string{"--all", "-b", "-c", "--all=false", "file"}
+
+func BenchmarkQuickParse(b *testing.B) {
+	var all, bb, c bool
+	spec := map[string]*bool{"all": &all, "b": &bb, "c": &c}
+	b.ReportAllocs()
+	for range b.N {
+		QuickParse(spec, quickArgs)
+	}
+}
+
+func BenchmarkStdlibFlag(b *testing.B) {
+	var all, bb, c bool
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.BoolVar(&all, "all", false, "")
+	fs.BoolVar(&bb, "b", false, "")
+	fs.BoolVar(&c, "c", false, "")
+	b.ReportAllocs()
+	for range b.N {
+		fs.Parse(quickArgs)
+	}
+}
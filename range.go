@@ -0,0 +1,109 @@
+package mandy
+
+import (
+	"cmp"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// boundedValue is implemented by values restricted to a range
+type boundedValue interface {
+	bounds() string
+}
+
+// rangeVar defines a flag whose value, v, accepts only values within [min, max]
+// It panics if the range is empty or excludes the default.
+func rangeVar[T cmp.Ordered](c *Command, v Getter, integral bool, value, min, max T, name, usage string, short bool) *Flag {
+	if !(min <= max) {
+		panic(c.sprintf("flag %q has an empty range [%v, %v]", name, min, max))
+	}
+	if !(min <= value && value <= max) {
+		panic(c.sprintf("flag %q has a default, %v, outside of its range [%v, %v]", name, value, min, max))
+	}
+	return c.Var(newRangeValue(v, integral, min, max), name, usage, short)
+}
+
+// newRangeValue wraps a numeric value so that it accepts only values within [min, max]
+func newRangeValue[T cmp.Ordered](v Getter, integral bool, min, max T) *numberValue {
+	return &numberValue{
+		Getter:   v,
+		integral: integral,
+		min:      min,
+		max:      max,
+		inRange: func(v any) bool {
+			x := v.(T)
+			return min <= x && x <= max
+		},
+	}
+}
+
+func (n *numberValue) bounds() string {
+	if n.inRange == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v to %v", n.min, n.max)
+}
+
+// Complete suggests every integer in the range, if there are few enough, or its bounds otherwise
+func (n *numberValue) Complete(prefix string) (out []string) {
+	if n.inRange == nil {
+		return nil
+	}
+	candidates := []string{fmt.Sprint(n.min), fmt.Sprint(n.max)}
+	if lo, hi, ok := n.span(); ok && hi-lo < maxRangeCompletions {
+		candidates = candidates[:0]
+		for i := lo; i <= hi; i++ {
+			candidates = append(candidates, strconv.FormatInt(i, 10))
+		}
+	}
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, prefix) {
+			out = append(out, candidate)
+		}
+	}
+	return
+}
+
+// maxRangeCompletions is the most integers a range may hold for Complete to list them all
+const maxRangeCompletions = 32
+
+// span returns the bounds of an integral range as int64s, if they fit
+func (n *numberValue) span() (lo, hi int64, ok bool) {
+	if !n.integral {
+		return 0, 0, false
+	}
+	lo, err := strconv.ParseInt(fmt.Sprint(n.min), 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	hi, err = strconv.ParseInt(fmt.Sprint(n.max), 10, 64)
+	return lo, hi, err == nil
+}
+
+// IntRange is like Int but rejects values outside of [min, max],
+// which are shown in the usage message and offered for completion.
+// IntRange, like the other ranges, panics if min > max or the default is out of range.
+func (c *Command) IntRange(p *int, name string, value, min, max int, usage string, short bool) *Flag {
+	return rangeVar(c, newIntValue(value, p), true, value, min, max, name, usage, short)
+}
+
+// Int64Range is like Int64 but rejects values outside of [min, max].
+func (c *Command) Int64Range(p *int64, name string, value, min, max int64, usage string, short bool) *Flag {
+	return rangeVar(c, newInt64Value(value, p), true, value, min, max, name, usage, short)
+}
+
+// UintRange is like Uint but rejects values outside of [min, max].
+func (c *Command) UintRange(p *uint, name string, value, min, max uint, usage string, short bool) *Flag {
+	return rangeVar(c, newUintValue(value, p), true, value, min, max, name, usage, short)
+}
+
+// Uint64Range is like Uint64 but rejects values outside of [min, max].
+func (c *Command) Uint64Range(p *uint64, name string, value, min, max uint64, usage string, short bool) *Flag {
+	return rangeVar(c, newUint64Value(value, p), true, value, min, max, name, usage, short)
+}
+
+// Float64Range is like Float64 but rejects values outside of [min, max].
+func (c *Command) Float64Range(p *float64, name string, value, min, max float64, usage string, short bool) *Flag {
+	return rangeVar(c, newFloat64Value(value, p), false, value, min, max, name, usage, short)
+}
@@ -0,0 +1,75 @@
+package mandy
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestIntRange(t *testing.T) {
+	var threads int
+	c := NewCommand("ranged", ContinueOnError)
+	flag := c.IntRange(&threads, "threads", 4, 1, 12, "worker threads", false)
+	if threads != 4 {
+		t.Errorf("default not applied: %d", threads)
+	}
+	if err := c.Set("threads", "12"); err != nil || threads != 12 {
+		t.Errorf("in range: %d, %v", threads, err)
+	}
+	for _, arg := range []string{"0", "13", "-1"} {
+		if err := c.Set("threads", arg); !errors.Is(err, errRange) {
+			t.Errorf("%s: expected a range error, got %v", arg, err)
+		}
+	}
+	if threads != 12 {
+		t.Errorf("rejected values should leave the flag alone, got %d", threads)
+	}
+	if !strings.Contains(flag.usage(DefaultInline), "(1 to 12)") {
+		t.Errorf("usage should show the range: %q", flag.usage(DefaultInline))
+	}
+	if got := flag.Completions("1"); !slices.Equal(got, []string{"1", "10", "11", "12"}) {
+		t.Errorf("completions: %v", got)
+	}
+
+	flag.Numbers(DecimalUnits)
+	if err := c.Set("threads", "1k"); !errors.Is(err, errRange) {
+		t.Errorf("units should still be range checked, got %v", err)
+	}
+}
+
+func TestFloat64Range(t *testing.T) {
+	var f float64
+	c := NewCommand("ranged", ContinueOnError)
+	flag := c.Float64Range(&f, "load", 0.5, 0, 1, "target load", false)
+	if err := c.Set("load", "1.5"); !errors.Is(err, errRange) {
+		t.Errorf("expected a range error, got %v", err)
+	}
+	if got := flag.Completions(""); !slices.Equal(got, []string{"0", "1"}) {
+		t.Errorf("completions: %v", got)
+	}
+	if name, _ := UnquoteUsage(flag); name != "float" {
+		t.Errorf("type name: %q", name)
+	}
+}
+
+func TestRangePanics(t *testing.T) {
+	for name, define := range map[string]func(c *Command){
+		"empty":       func(c *Command) { c.IntRange(new(int), "n", 5, 10, 1, "", false) },
+		"default":     func(c *Command) { c.UintRange(new(uint), "n", 0, 1, 10, "", false) },
+		"nan":         func(c *Command) { c.Float64Range(new(float64), "n", 0, math.NaN(), 1, "", false) },
+		"nan default": func(c *Command) { c.Float64Range(new(float64), "n", math.NaN(), 0, 1, "", false) },
+	} {
+		func() {
+			c := NewCommand("ranged", ContinueOnError)
+			c.SetOutput(new(strings.Builder))
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected a panic", name)
+				}
+			}()
+			define(c)
+		}()
+	}
+}
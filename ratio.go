@@ -0,0 +1,88 @@
+package mandy
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// -- ratio Value
+// accepts percentages, decimals, and fractions within [min, max]
+type ratioValue struct {
+	p        *float64
+	min, max float64
+}
+
+func newRatioValue(val float64, p *float64, min, max float64) *ratioValue {
+	*p = val
+	return &ratioValue{p: p, min: min, max: max}
+}
+
+// ParseRatio parses "75%", "0.75", or "3/4" as a float64
+func ParseRatio(s string) (float64, error) {
+	if pct, ok := strings.CutSuffix(s, "%"); ok {
+		v, err := strconv.ParseFloat(strings.TrimSpace(pct), 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: invalid percentage %q", numError(err), s)
+		}
+		return v / 100, nil
+	}
+	if num, den, ok := strings.Cut(s, "/"); ok {
+		n, err := strconv.ParseFloat(strings.TrimSpace(num), 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: invalid numerator in %q", numError(err), s)
+		}
+		d, err := strconv.ParseFloat(strings.TrimSpace(den), 64)
+		if err != nil || d == 0 {
+			return 0, fmt.Errorf("%w: invalid denominator in %q", errParse, s)
+		}
+		return n / d, nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q should look like 75%%, 0.75, or 3/4", numError(err), s)
+	}
+	return v, nil
+}
+
+func (r *ratioValue) Set(s string) error {
+	v, err := ParseRatio(s)
+	if err != nil {
+		return err
+	}
+	if math.IsNaN(v) {
+		return fmt.Errorf("%w: %q is not a number", errParse, s)
+	}
+	if v < r.min || v > r.max {
+		return fmt.Errorf("%w: %s is %s, which is not between %s and %s", errRange, s, formatRatio(v), formatRatio(r.min), formatRatio(r.max))
+	}
+	*r.p = v
+	return nil
+}
+
+// formatRatio renders ratios as percentages
+func formatRatio(v float64) string {
+	return strconv.FormatFloat(v*100, 'g', -1, 64) + "%"
+}
+
+func (r *ratioValue) Get() any { return *r.p }
+func (r *ratioValue) String() string {
+	if r.p == nil {
+		return ""
+	}
+	return formatRatio(*r.p)
+}
+func (r *ratioValue) IsBool() bool { return false }
+
+// Ratio defines a float64 flag with specified name, default value, and usage string.
+// The argument p points to a float64 variable in which to store the value of the flag.
+// The flag accepts percentages, decimals, and fractions, like 75%, 0.75, or 3/4, between 0 and 1.
+func (c *Command) Ratio(p *float64, name string, value float64, usage string, short bool) *Flag {
+	return c.RatioRange(p, name, value, 0, 1, usage, short)
+}
+
+// RatioRange is like Ratio but accepts values between min and max rather than 0 and 1.
+func (c *Command) RatioRange(p *float64, name string, value, min, max float64, usage string, short bool) *Flag {
+	return c.Var(newRatioValue(value, p, min, max), name, usage, short)
+}
@@ -0,0 +1,35 @@
+package mandy
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRatio(t *testing.T) {
+	var rate float64
+	c := NewCommand("test", ContinueOnError)
+	f := c.Ratio(&rate, "sample-rate", 0.5, "fraction of requests to trace", false)
+	if f.DefValue != "50%" {
+		t.Errorf("DefValue = %q", f.DefValue)
+	}
+	for arg, want := range map[string]float64{"75%": 0.75, "0.25": 0.25, "3/4": 0.75, "1": 1} {
+		if err := c.Set("sample-rate", arg); err != nil {
+			t.Errorf("%s: %v", arg, err)
+		} else if rate != want {
+			t.Errorf("%s gave %v, want %v", arg, rate, want)
+		}
+	}
+	if err := c.Set("sample-rate", "150%"); !errors.Is(err, errRange) {
+		t.Errorf("expected a range error, got %v", err)
+	}
+	for _, bad := range []string{"half", "1/0", "x%", "NaN", "nan%", "NaN/2"} {
+		if err := c.Set("sample-rate", bad); !errors.Is(err, errParse) {
+			t.Errorf("%s: expected a parse error, got %v", bad, err)
+		}
+	}
+
+	c.RatioRange(&rate, "quota", 1, 0, 2, "share of quota", false)
+	if err := c.Set("quota", "150%"); err != nil {
+		t.Error(err)
+	}
+}
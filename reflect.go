@@ -0,0 +1,34 @@
+//go:build !tinygo
+
+package mandy
+
+import "reflect"
+
+// Eq checks if a flag has a given value
+func (f *Flag) Eq(arg any) bool {
+	return reflect.ValueOf(f.Value.Get()).Equal(reflect.ValueOf(arg))
+}
+
+// isZeroValue determines whether the string represents the zero
+// value for a flag.
+func isZeroValue(flag *Flag, value string) bool {
+	// Build a zero value of the flag's Value type, and see if the
+	// result of calling its String method equals the value passed in.
+	// This works unless the Value type is itself an interface type.
+	// Numbers are rendered by the value they wrap, so it's that which must be zeroed.
+	if n, ok := flag.Value.(*numberValue); ok {
+		z := *n
+		z.Getter = zeroOf(n.Getter).(Getter)
+		return value == z.String()
+	}
+	return value == zeroOf(flag.Value).(Value).String()
+}
+
+// zeroOf returns a zero value of v's type, pointing to a zero value if v is a pointer
+func zeroOf(v any) any {
+	typ := reflect.TypeOf(v)
+	if typ.Kind() == reflect.Pointer {
+		return reflect.New(typ.Elem()).Interface()
+	}
+	return reflect.Zero(typ).Interface()
+}
@@ -0,0 +1,214 @@
+package mandy
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// HelpFormat names a rendering of a command's help message.
+// It may be given as the value of the help flag, eg "--help=man".
+type HelpFormat string
+
+const (
+	HelpPlain    HelpFormat = "plain" // the command's Usage text
+	HelpMan      HelpFormat = "man"   // a man(7) roff page
+	HelpMarkdown HelpFormat = "md"    // a markdown document
+	HelpJSON     HelpFormat = "json"  // the command's HelpModel as JSON
+)
+
+const helpUsage = "print this message (or render it as plain, man, md, or json)"
+
+type (
+	// helpDoc holds the data from which help messages are rendered
+	helpDoc struct {
+		name     string
+		page     string // the name of the command's man page
+		parent   string // the name of the parent's man page, if there is a parent
+		usage    string
+		summary  string
+		version  string
+		footer   string
+		url      string
+		aliases  []string
+		flags    []flagHelp
+		children []helpDoc
+	}
+)
+
+// helpFormat reports the format requested via the help flag
+// returns an empty string if none was given explicitly
+func (c *Command) helpFormat() HelpFormat {
+	flag, used := c.actual[HelpName]
+	if !used {
+		return ""
+	}
+	if hv, ok := flag.Value.(*helpValue); ok && hv.set {
+		return hv.format
+	}
+	return ""
+}
+
+// doc collects the command's help data
+func (c *Command) doc() helpDoc {
+	d := helpDoc{
+		name:    c.name_(),
+		page:    manPage(c),
+		usage:   strings.TrimSpace(fmt.Sprintf(c.Format, c.name_())),
+		summary: c.Summary,
+		version: c.Version,
+		footer:  c.usageFooter(),
+		url:     c.url(),
+		aliases: c.aliases,
+	}
+	if c.parent != nil {
+		d.parent = manPage(c.parent)
+	}
+	for _, flag := range c.orderFlags(c.formal) {
+		fh := flag.help()
+		if flag.hideDefault {
+			fh.def = ""
+		}
+		d.flags = append(d.flags, fh)
+	}
+	for _, child := range c.children {
+		if child.hidden() {
+			continue
+		}
+		d.children = append(d.children, helpDoc{name: child.name, page: manPage(child), summary: child.Summary})
+	}
+	return d
+}
+
+// WriteHelp writes the command's help message to w in the given format
+func (c *Command) WriteHelp(w io.Writer, format HelpFormat) (err error) {
+	switch format {
+	case HelpPlain:
+		err = c.WriteUsage(w)
+	case HelpMan:
+		_, err = io.WriteString(w, c.doc().man())
+	case HelpMarkdown:
+		_, err = io.WriteString(w, c.doc().markdown())
+	case HelpJSON:
+		err = c.writeHelpJSON(w)
+	default:
+		err = fmt.Errorf("unknown help format: %q", format)
+	}
+	return
+}
+
+// roffEscape escapes text for use in a roff document
+func roffEscape(s string) string {
+	s = strings.NewReplacer(`\`, `\e`, "-", `\-`).Replace(s)
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[i] = `\&` + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// manPage names the command's man page after its path, as in "tool-remote-add"
+func manPage(c *Command) string {
+	return strings.ReplaceAll(c.path(), " ", "-")
+}
+
+// man renders the doc as a man(7) page
+func (d helpDoc) man() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, ".TH %s 1 \"\" %q\n", roffEscape(strings.ToUpper(d.page)), d.version)
+	b.WriteString(".SH NAME\n")
+	b.WriteString(roffEscape(d.name))
+	if d.summary != "" {
+		b.WriteString(` \- ` + roffEscape(d.summary))
+	}
+	b.WriteString("\n.SH SYNOPSIS\n")
+	b.WriteString(roffEscape(d.usage) + "\n")
+	if len(d.aliases) > 0 {
+		b.WriteString(".SH ALIASES\n" + roffEscape(strings.Join(d.aliases, ", ")) + "\n")
+	}
+	if len(d.flags) > 0 {
+		b.WriteString(".SH OPTIONS\n")
+		for _, f := range d.flags {
+			b.WriteString(".TP\n")
+			if f.short {
+				fmt.Fprintf(&b, `\fB\-%c\fR, `, f.name[0])
+			}
+			fmt.Fprintf(&b, "\\fB\\-\\-%s\\fR\n", roffEscape(f.name))
+			b.WriteString(roffEscape(f.desc))
+			if f.def != "" {
+				fmt.Fprintf(&b, " [default: %s]", roffEscape(f.def))
+			}
+			b.WriteString("\n")
+		}
+	}
+	if len(d.children) > 0 {
+		b.WriteString(".SH COMMANDS\n")
+		for _, child := range d.children {
+			fmt.Fprintf(&b, ".TP\n\\fB%s\\fR\n%s\n", roffEscape(child.name), roffEscape(child.summary))
+		}
+	}
+	var refs []string
+	if d.parent != "" {
+		refs = append(refs, `\fB`+roffEscape(d.parent)+`\fR(1)`)
+	}
+	for _, child := range d.children {
+		refs = append(refs, `\fB`+roffEscape(child.page)+`\fR(1)`)
+	}
+	url := d.url
+	if strings.Contains(d.footer, url) {
+		url = "" // the footer already links to it, as DefaultFooter does
+	}
+	if len(refs) > 0 || d.footer != "" || url != "" {
+		b.WriteString(".SH SEE ALSO\n")
+		if len(refs) > 0 {
+			b.WriteString(strings.Join(refs, ", ") + "\n")
+		}
+		if d.footer != "" {
+			b.WriteString(".PP\n" + roffEscape(d.footer) + "\n")
+		}
+		if url != "" {
+			b.WriteString(".UR " + url + "\n.UE\n")
+		}
+	}
+	return b.String()
+}
+
+// markdown renders the doc as a markdown document
+func (d helpDoc) markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", d.name)
+	if d.summary != "" {
+		b.WriteString(d.summary + "\n\n")
+	}
+	fmt.Fprintf(&b, "```\n%s\n```\n", d.usage)
+	if len(d.flags) > 0 {
+		b.WriteString("\n## Options\n\n")
+		for _, f := range d.flags {
+			b.WriteString("- ")
+			if f.short {
+				fmt.Fprintf(&b, "`-%c`, ", f.name[0])
+			}
+			fmt.Fprintf(&b, "`--%s`: %s", f.name, f.desc)
+			if f.def != "" {
+				fmt.Fprintf(&b, " [default: `%s`]", f.def)
+			}
+			b.WriteString("\n")
+		}
+	}
+	if len(d.children) > 0 {
+		b.WriteString("\n## Commands\n\n")
+		for _, child := range d.children {
+			fmt.Fprintf(&b, "- `%s`", child.name)
+			if child.summary != "" {
+				b.WriteString(": " + child.summary)
+			}
+			b.WriteString("\n")
+		}
+	}
+	if d.footer != "" {
+		b.WriteString("\n" + d.footer + "\n")
+	}
+	return b.String()
+}
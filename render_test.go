@@ -0,0 +1,112 @@
+package mandy
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWriteHelp(t *testing.T) {
+	var name string
+	c := NewCommand("tool", ContinueOnError)
+	c.Summary = "does things"
+	c.String(&name, "name", "gopher", "who to greet", true)
+	c.NewChild("greet", "say hello")
+
+	tests := []struct {
+		format HelpFormat
+		want   []string
+	}{
+		{HelpMan, []string{".TH TOOL 1", ".SH NAME\ntool \\- does things", `\fB\-n\fR, \fB\-\-name\fR`, ".SH COMMANDS"}},
+		{HelpMarkdown, []string{"# tool\n", "- `-n`, `--name`: who to greet [default: `gopher`]", "- `greet`: say hello"}},
+		{HelpPlain, []string{"usage:", "commands:"}},
+		{HelpJSON, []string{`"name": "tool"`, `"summary": "does things"`, `"short": "n"`, `"name": "tool greet"`}},
+	}
+	for _, test := range tests {
+		var buf strings.Builder
+		if err := c.WriteHelp(&buf, test.format); err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range test.want {
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("%s help %q does not contain %q", test.format, buf.String(), want)
+			}
+		}
+	}
+
+	if err := c.WriteHelp(new(strings.Builder), "pdf"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
+func TestHelpFlagFormats(t *testing.T) {
+	var out strings.Builder
+	c := NewCommand("tool", ContinueOnError)
+	c.SetStdout(&out)
+	c.Summary = "does things"
+	for _, tc := range []struct {
+		arg  string
+		want string
+	}{
+		{"--help=man", ".TH TOOL 1"},
+		{"--help=md", "# tool\n"},
+		{"--help=json", `"name": "tool"`},
+	} {
+		out.Reset()
+		if err := c.Clone().Parse(tc.arg); !errors.Is(err, ErrHelp) {
+			t.Errorf("%s: err = %v, want ErrHelp", tc.arg, err)
+		}
+		if !strings.Contains(out.String(), tc.want) {
+			t.Errorf("%s wrote %q to the command's stdout, want it to contain %q", tc.arg, out.String(), tc.want)
+		}
+	}
+}
+
+func TestHelpValue(t *testing.T) {
+	hv := newHelpValue()
+	for arg, want := range map[string]HelpFormat{"true": "", "man": HelpMan, "md": HelpMarkdown, "json": HelpJSON} {
+		if err := hv.Set(arg); err != nil {
+			t.Fatal(err)
+		}
+		if hv.format != want || !hv.set {
+			t.Errorf("Set(%q) gave format %q, want %q", arg, hv.format, want)
+		}
+	}
+	if err := hv.Set("pdf"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
+func TestHelpModel(t *testing.T) {
+	var token, format string
+	var verbose bool
+	c := NewCommand("tool", ContinueOnError)
+	c.GlobalOptions = true
+	c.Secret(&token, "token", "hunter2", "the `key` to authenticate with", false).Env("TOOL_TOKEN")
+	c.Bool(&verbose, "verbose", false, "talk more", true)
+	list := c.NewChild("list", "list things")
+	list.Example = "tool list\ntool list --format=json\n"
+	list.Enum(&format, "format", "table", "output format", false, "table", "json")
+	c.NewChild("secret", "").Hidden = true
+
+	m := c.HelpModel()
+	if len(m.Children) != 1 || m.Children[0].Name != "tool list" {
+		t.Fatalf("children = %+v, want tool list alone", m.Children)
+	}
+	want := HelpFlag{Name: "token", Arg: "key", Description: "the key to authenticate with [env: TOOL_TOKEN]", Default: Redacted, Env: "TOOL_TOKEN", Secret: true}
+	for _, f := range m.Flags {
+		if f.Name == "token" && f != want {
+			t.Errorf("token = %+v, want %+v", f, want)
+		}
+		if f.Name == "verbose" && (f.Short != "v" || f.Arg != "") {
+			t.Errorf("verbose = %+v", f)
+		}
+	}
+	sub := m.Children[0]
+	if len(sub.Examples) != 2 || sub.Examples[1] != "tool list --format=json" {
+		t.Errorf("examples = %q", sub.Examples)
+	}
+	if len(sub.Globals) != 2 || len(sub.Flags) != 2 {
+		t.Errorf("list has flags %+v and globals %+v", sub.Flags, sub.Globals)
+	}
+}
@@ -0,0 +1,122 @@
+package mandy
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Required makes Parse fail unless the flag is set, and marks it as required in usage messages
+func (f *Flag) Required() *Flag {
+	f.required = true
+	return f
+}
+
+// IsRequired reports whether the flag was marked by Required
+func (f *Flag) IsRequired() bool {
+	return f.required
+}
+
+// MarkRequired makes the named flags Required. It panics if one of them is not defined.
+func (c *Command) MarkRequired(names ...string) *Command {
+	for _, name := range names {
+		flag := c.formal[name]
+		if flag == nil {
+			panic(c.sprintf("cannot require undefined flag %q", name))
+		}
+		flag.Required()
+	}
+	c.invalidate()
+	return c
+}
+
+// A requirement constrains which of a group of flags must be set together
+type requirement struct {
+	names    []string
+	together bool // all or none of the flags must be set, rather than at least one
+}
+
+// MarkRequiredTogether makes Parse fail if some, but not all, of the named flags are set.
+// It panics if one of them is not defined.
+func (c *Command) MarkRequiredTogether(names ...string) *Command {
+	return c.require(requirement{names: names, together: true})
+}
+
+// MarkOneRequired makes Parse fail unless at least one of the named flags is set.
+// It panics if one of them is not defined.
+func (c *Command) MarkOneRequired(names ...string) *Command {
+	return c.require(requirement{names: names})
+}
+
+func (c *Command) require(r requirement) *Command {
+	for _, name := range r.names {
+		if c.formal[name] == nil {
+			panic(c.sprintf("cannot require undefined flag %q", name))
+		}
+	}
+	c.requirements = append(c.requirements, r)
+	return c
+}
+
+// check returns a description of how the requirement is broken by the flags set on c, or ""
+func (r requirement) check(c *Command) string {
+	var set, unset []string
+	for _, name := range r.names {
+		if _, ok := c.actual[name]; ok {
+			set = append(set, "--"+name)
+		} else {
+			unset = append(unset, "--"+name)
+		}
+	}
+	switch {
+	case r.together && len(set) > 0 && len(unset) > 0:
+		return fmt.Sprintf("%s must be given with %s", strings.Join(unset, ", "), strings.Join(set, ", "))
+	case !r.together && len(set) == 0:
+		return "one of " + strings.Join(unset, ", ") + " is required"
+	}
+	return ""
+}
+
+// checkRequired returns an error, wrapping ErrRequired, listing the command's required flags that were not set
+// and the requirements of its flag groups that were broken; nothing is required of a command whose help flag was used
+func (c *Command) checkRequired() error {
+	if _, help := c.actual[HelpName]; help {
+		return nil
+	}
+	var missing, broken []string
+	c.VisitAll(func(f *Flag) {
+		if _, set := c.actual[f.Name]; f.required && !set {
+			missing = append(missing, "--"+f.Name)
+		}
+	})
+	if len(missing) > 0 {
+		broken = append(broken, strings.Join(missing, ", "))
+	}
+	for _, r := range c.requirements {
+		if msg := r.check(c); msg != "" {
+			broken = append(broken, msg)
+		}
+	}
+	if len(broken) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrRequired, strings.Join(broken, "; "))
+}
+
+// validate makes Parse call check, once the command's flags are resolved, with the command parsed,
+// which may be a clone, so that flag groups can check settings that are only valid together
+func (c *Command) validate(check func(*Command) error) {
+	c.checks = append(c.checks, check)
+}
+
+// runChecks returns the first error of the command's checks; nothing is checked if its help flag was used
+func (c *Command) runChecks() error {
+	if _, help := c.actual[HelpName]; help {
+		return nil
+	}
+	for _, check := range c.checks {
+		if err := check(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
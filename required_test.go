@@ -0,0 +1,78 @@
+package mandy
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestRequired(t *testing.T) {
+	var user, host, mode string
+	c := NewCommand("test", ContinueOnError)
+	c.SetOutput(io.Discard)
+	c.String(&user, "user", "", "login name", false).Required()
+	c.String(&host, "host", "", "server", false)
+	c.String(&mode, "mode", "fast", "speed", false)
+	c.MarkRequired("host")
+	child := c.NewChild("child", "")
+	var force bool
+	child.Bool(&force, "force", false, "", false).Required()
+
+	err := c.Parse("--mode", "slow")
+	if !errors.Is(err, ErrRequired) || !strings.HasSuffix(err.Error(), "--host, --user") {
+		t.Errorf("Parse without required flags = %v, want ErrRequired listing --host and --user", err)
+	}
+	if err := c.Parse("--user", "me", "--host", "example.com"); err != nil {
+		t.Errorf("Parse with required flags = %v", err)
+	}
+	c.forget()
+	if err := c.Parse("child", "--force"); !errors.Is(err, ErrRequired) {
+		t.Errorf("dispatching without the parent's required flags = %v, want ErrRequired", err)
+	}
+	c.forget()
+	if err := c.Parse("--user", "me", "--host", "h", "child"); !errors.Is(err, ErrRequired) || !strings.HasSuffix(err.Error(), "--force") {
+		t.Errorf("Parse without the child's required flag = %v, want ErrRequired listing --force", err)
+	}
+	c.forget()
+	if err := c.Parse("--help"); err != nil {
+		t.Errorf("Parse(--help) = %v, want nothing required", err)
+	}
+	if want := "--user\tlogin name (required)\n"; !strings.Contains(c.Defaults(), want) {
+		t.Errorf("Defaults() = %q, want it to contain %q", c.Defaults(), want)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("MarkRequired of an undefined flag did not panic")
+		}
+	}()
+	c.MarkRequired("missing")
+}
+
+func TestRequirements(t *testing.T) {
+	for _, test := range []struct {
+		args []string
+		want string // the end of the error message, if any
+	}{
+		{[]string{"--token", "t"}, ""},
+		{[]string{"--user", "u", "--password", "p"}, ""},
+		{[]string{"--user", "u", "--token", "t"}, "--password must be given with --user"},
+		{[]string{"--"}, "one of --user, --token is required"},
+		{[]string{"--password", "p"}, "--user must be given with --password; one of --user, --token is required"},
+		{[]string{"--help"}, ""},
+	} {
+		var user, password, token string
+		c := NewCommand("test", ContinueOnError)
+		c.SetOutput(io.Discard)
+		c.String(&user, "user", "", "", false)
+		c.String(&password, "password", "", "", false)
+		c.String(&token, "token", "", "", false)
+		c.MarkRequiredTogether("user", "password").MarkOneRequired("user", "token")
+
+		err := c.Parse(test.args...)
+		if test.want == "" && err != nil || test.want != "" && (!errors.Is(err, ErrRequired) || !strings.HasSuffix(err.Error(), test.want)) {
+			t.Errorf("Parse(%q) = %v, want %q", test.args, err, test.want)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package mandy
+
+import (
+	"slices"
+)
+
+// Reserve sets the given names aside for future use, so that registering a flag or child by one of them panics,
+// and merging a flag by one of them fails or is resolved as if the name were taken.
+// Names already in use are unaffected.
+func (c *Command) Reserve(names ...string) *Command {
+	c.reserved = append(c.reserved, names...)
+	return c
+}
+
+// isReserved reports whether name has been set aside by Reserve
+func (c *Command) isReserved(name string) bool {
+	return slices.Contains(c.reserved, name)
+}
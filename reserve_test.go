@@ -0,0 +1,38 @@
+package mandy
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestReserve(t *testing.T) {
+	c := NewCommand("app", ContinueOnError).Reserve("profile", "plugins")
+	c.SetOutput(io.Discard)
+	expectPanic := func(what string, fn func()) {
+		t.Helper()
+		defer func() {
+			if r := recover(); r == nil || !strings.Contains(r.(string), "reserved") {
+				t.Errorf("%s: expected a reserved name panic, got %v", what, r)
+			}
+		}()
+		fn()
+	}
+	expectPanic("flag", func() { c.String(new(string), "profile", "", "", false) })
+	expectPanic("child", func() { c.NewChild("plugins", "") })
+
+	plugin := NewCommand("plugin", ContinueOnError)
+	plugin.Bool(new(bool), "profile", false, "", false)
+	if err := c.Merge(plugin, ConflictError); !errors.Is(err, ErrConflict) {
+		t.Errorf("expected ErrConflict, got %v", err)
+	}
+	if err := c.Merge(plugin, ConflictPrefix); err != nil || c.Lookup("plugin-profile") == nil {
+		t.Errorf("expected the reserved flag to be renamed, got %v", err)
+	}
+
+	child := c.NewChild("run", "")
+	if err := child.AddAlias("plugins"); err == nil {
+		t.Error("aliases should not claim reserved names")
+	}
+}
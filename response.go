@@ -0,0 +1,52 @@
+package mandy
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ResponseFileDepth is the number of response files that may be nested within each other
+const ResponseFileDepth = 10
+
+// ErrResponseFile is returned by Parse when a response file can't be expanded
+var ErrResponseFile = errors.New("mandy: bad response file")
+
+// responseFiles reports whether the command's root expands response files
+func (c *Command) responseFiles() bool {
+	root := c
+	for root.parent != nil {
+		root = root.parent
+	}
+	return root.ResponseFiles
+}
+
+// expandResponseFile replaces the command's pending "@file" argument with the arguments the file holds,
+// split by SplitLine, which are parsed, and expanded in turn, as if they had been given in its place,
+// to a depth of ResponseFileDepth. A leading byte order mark, as some Windows editors write, is ignored.
+func (c *Command) expandResponseFile() error {
+	root := c
+	for root.parent != nil {
+		root = root.parent
+	}
+	// responseEnds holds, for each file being read, the number of arguments that follow its contents
+	for n := len(root.responseEnds); n > 0 && len(c.args) <= root.responseEnds[n-1]; n-- {
+		root.responseEnds = root.responseEnds[:n-1]
+	}
+	name, rest := c.args[0][1:], c.args[1:]
+	if len(root.responseEnds) == ResponseFileDepth {
+		return fmt.Errorf("%w: %s: nested more than %d deep", ErrResponseFile, name, ResponseFileDepth)
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrResponseFile, err)
+	}
+	held, err := SplitLine(strings.TrimPrefix(string(data), "\ufeff"))
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrResponseFile, name, err)
+	}
+	root.responseEnds = append(root.responseEnds, len(rest))
+	c.args = append(held, rest...)
+	return nil
+}
@@ -0,0 +1,84 @@
+package mandy
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestResponseFiles(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	inner := write("inner.txt", "--tag c\n")
+	outer := write("args.txt", "\ufeff--name 'a b'\n--tag=b @"+inner+"\n")
+	ended := write("ended.txt", "--tag d -- @"+inner+"\n")
+	loop := write("loop.txt", "")
+	write("loop.txt", "@"+loop)
+
+	var (
+		name string
+		tags []string
+	)
+	c := NewCommand("tool", ContinueOnError)
+	c.ResponseFiles = true
+	c.String(&name, "name", "", "", false)
+	c.StringSlice(&tags, "tag", nil, "", false)
+	c.SetOutput(io.Discard)
+	var args []string
+	c.Main = func(self *Command) error {
+		args = self.Args()
+		return nil
+	}
+	if err := c.Execute("@"+outer, "--tag", "e", "@"); err != nil {
+		t.Fatal(err)
+	}
+	if name != "a b" || !reflect.DeepEqual(tags, []string{"b", "c", "e"}) || !reflect.DeepEqual(args, []string{"@"}) {
+		t.Errorf("name, tags, args = %q, %q, %q, want a b, [b c e], and [@]", name, tags, args)
+	}
+	for _, tc := range []struct {
+		args []string
+		name string
+		tags []string
+		rest []string
+	}{
+		{[]string{"--", "@" + outer}, "", nil, []string{"@" + outer}},
+		{[]string{"@" + ended, "@" + inner}, "", []string{"d"}, []string{"@" + inner, "@" + inner}},
+		{[]string{"--name", "@" + inner, "--tag=@" + inner}, "@" + inner, []string{"@" + inner}, []string{}},
+		{[]string{"@@" + inner, "@" + inner}, "", nil, []string{"@" + inner, "@" + inner}},
+		{[]string{"-", "@" + inner}, "", nil, []string{"-", "@" + inner}},
+	} {
+		name, tags, args = "", nil, nil
+		if err := c.Execute(tc.args...); err != nil {
+			t.Fatal(err)
+		}
+		if name != tc.name || !reflect.DeepEqual(tags, tc.tags) || !reflect.DeepEqual(args, tc.rest) {
+			t.Errorf("%q: name, tags, args = %q, %q, %q, want %q, %q, %q", tc.args, name, tags, args, tc.name, tc.tags, tc.rest)
+		}
+	}
+
+	var handled []string
+	prefixed := c.Clone()
+	prefixed.HandlePrefix("@", func(rest string) error {
+		handled = append(handled, rest)
+		return nil
+	})
+	if err := prefixed.Execute("@" + inner); err != nil || !reflect.DeepEqual(handled, []string{inner}) {
+		t.Errorf("HandlePrefix(\"@\") got %q, %v; it should take precedence over response files", handled, err)
+	}
+
+	for _, arg := range []string{"@" + loop, "@" + filepath.Join(dir, "missing.txt")} {
+		if err := c.Clone().Execute(arg); !errors.Is(err, ErrResponseFile) {
+			t.Errorf("Execute(%q) = %v, want ErrResponseFile", arg, err)
+		}
+	}
+}
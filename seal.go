@@ -0,0 +1,44 @@
+package mandy
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrSealed is returned, or panicked with, when a flag, child, or alias is added to a sealed command
+var ErrSealed = errors.New("mandy: command is sealed")
+
+// Seal stops flags, children, and aliases from being added to the command and its descendants,
+// except those passed as open, and their descendants, so that a host can hand its tree to plugins
+// while designating where they may register. Thereafter Var, and the flag constructors, and NewChild
+// panic with an error wrapping ErrSealed, while AddAlias, Mount, and Merge return one.
+func (c *Command) Seal(open ...*Command) {
+	for _, o := range open {
+		if o == c {
+			return
+		}
+	}
+	c.sealed = true
+	for _, child := range c.children {
+		child.Seal(open...)
+	}
+}
+
+// Sealed reports whether the command has been sealed
+func (c *Command) Sealed() bool { return c.sealed }
+
+// errSealed describes an attempt to add something to the sealed command, or returns nil if it isn't sealed
+func (c *Command) errSealed(kind, name string) error {
+	if !c.sealed {
+		return nil
+	}
+	return fmt.Errorf("%w: cannot add %s %q to %s", ErrSealed, kind, name, c.name)
+}
+
+// panicSealed panics, after writing its message to the command's output, if errSealed reports an error
+func (c *Command) panicSealed(kind, name string) {
+	if err := c.errSealed(kind, name); err != nil {
+		c.sprintf("%v", err)
+		panic(err)
+	}
+}
@@ -0,0 +1,51 @@
+package mandy
+
+import (
+	"errors"
+	"io"
+	"testing"
+)
+
+// sealedPanic reports the error fn panicked with, if any
+func sealedPanic(fn func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err, _ = r.(error)
+		}
+	}()
+	fn()
+	return nil
+}
+
+func TestSeal(t *testing.T) {
+	root := NewCommand("host", ContinueOnError)
+	root.SetOutput(io.Discard)
+	builtin := root.NewChild("status", "")
+	plugins := root.NewChild("plugins", "commands added by plugins")
+	root.Seal(plugins)
+
+	if !root.Sealed() || !builtin.Sealed() || plugins.Sealed() {
+		t.Fatalf("sealed: root %v, builtin %v, plugins %v", root.Sealed(), builtin.Sealed(), plugins.Sealed())
+	}
+	if err := sealedPanic(func() { root.Bool(new(bool), "debug", false, "", false) }); !errors.Is(err, ErrSealed) {
+		t.Errorf("adding a flag to the root: %v", err)
+	}
+	if err := sealedPanic(func() { builtin.NewChild("hijack", "") }); !errors.Is(err, ErrSealed) {
+		t.Errorf("adding a child to a sealed descendant: %v", err)
+	}
+	if err := plugins.AddAlias("p"); !errors.Is(err, ErrSealed) {
+		t.Errorf("aliasing a child of a sealed command: %v", err)
+	}
+	if err := root.Mount("http", &HTTPClient{}); !errors.Is(err, ErrSealed) {
+		t.Errorf("mounting on the root: %v", err)
+	}
+	if err := root.Merge(NewCommand("other", ContinueOnError), ConflictError); !errors.Is(err, ErrSealed) {
+		t.Errorf("merging into the root: %v", err)
+	}
+
+	plugin := plugins.NewChild("deploy", "")
+	plugin.String(new(string), "target", "", "where to deploy", false)
+	if err := root.Parse("plugins", "deploy", "--target=prod"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,117 @@
+package mandy
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"text/tabwriter"
+)
+
+// A HelpMatch is a command, or one of its flags, found by SearchHelp
+type HelpMatch struct {
+	Path    string // the path of the command, as in "tool serve"
+	Flag    string // the name of the flag, if the match is one
+	Summary string // the command's summary or the flag's usage
+	Score   int    // how well it matched; higher is better
+}
+
+func (m HelpMatch) String() string {
+	if m.Flag != "" {
+		return m.Path + " --" + m.Flag
+	}
+	return m.Path
+}
+
+// the weights of the fields SearchHelp looks in
+const (
+	weightName    = 4 // command and flag names, and aliases
+	weightSummary = 2 // command summaries and flag usages
+	weightExample = 1 // command examples
+)
+
+// SearchHelp returns the commands and flags, among the command's and its visible descendants', whose names,
+// aliases, summaries, examples, or usages contain any of the query's words, ignoring case, best first.
+// Names count for more than summaries and usages, which count for more than examples,
+// and a name equal to a word for more than one merely containing it.
+func (c *Command) SearchHelp(query string) (matches []HelpMatch) {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return nil
+	}
+	score := func(names []string, fields ...string) (n int) {
+		for _, term := range terms {
+			for _, name := range names {
+				if name = strings.ToLower(name); name == term {
+					n += 2 * weightName
+				} else if strings.Contains(name, term) {
+					n += weightName
+				}
+			}
+			for i, field := range fields {
+				if strings.Contains(strings.ToLower(field), term) {
+					n += []int{weightSummary, weightExample}[i]
+				}
+			}
+		}
+		return n
+	}
+	var walk func(*Command)
+	walk = func(cmd *Command) {
+		if n := score(append([]string{cmd.name}, cmd.aliases...), cmd.Summary, cmd.Example); n > 0 {
+			matches = append(matches, HelpMatch{Path: cmd.path(), Summary: cmd.Summary, Score: n})
+		}
+		for flag := range cmd.Flags() {
+			if flag.Name == HelpName {
+				continue
+			}
+			_, usage := UnquoteDescription(flag)
+			if n := score([]string{flag.Name}, usage); n > 0 {
+				matches = append(matches, HelpMatch{Path: cmd.path(), Flag: flag.Name, Summary: usage, Score: n})
+			}
+		}
+		for _, child := range cmd.children {
+			if !child.hidden() {
+				walk(child)
+			}
+		}
+	}
+	walk(c)
+	slices.SortStableFunc(matches, func(a, b HelpMatch) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+	return matches
+}
+
+// HelpCommand adds a child, named HelpName, that prints the usage of the command its arguments name,
+// as in "tool help serve", or of its parent if they name none. Given --search, it instead prints the
+// matches SearchHelp finds for the search and the arguments, as in "tool help --search log level".
+func (c *Command) HelpCommand() *Command {
+	child := c.NewChild(HelpName, "print the usage of a command, or search them all")
+	var search string
+	child.String(&search, "search", "", "print the commands and flags matching these `words`", false)
+	child.Main = func(self *Command) error {
+		if search := self.formal["search"].Value.String(); search != "" {
+			query := strings.Join(append([]string{search}, self.Args()...), " ")
+			matches := self.parent.SearchHelp(query)
+			if len(matches) == 0 {
+				return fmt.Errorf("no commands or flags match %q", query)
+			}
+			w := tabwriter.NewWriter(self.Stdout(), 0, 4, 2, ' ', 0)
+			for _, m := range matches {
+				fmt.Fprintf(w, "%s\t%s\n", m, m.Summary)
+			}
+			return w.Flush()
+		}
+		cmd := self.parent
+		for _, name := range self.Args() {
+			next := cmd.child(name)
+			if next == nil {
+				return fmt.Errorf("%s has no command %q", cmd.name_(), name)
+			}
+			cmd = next
+		}
+		return cmd.WriteUsage(self.Stdout())
+	}
+	return child
+}
@@ -0,0 +1,62 @@
+package mandy
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestSearchHelp(t *testing.T) {
+	var (
+		level, addr string
+		port        int
+	)
+	c := NewCommand("tool", ContinueOnError)
+	c.String(&level, "log-level", "info", "how much to log", false)
+	serve := c.NewChild("serve", "run the server")
+	serve.Example = "tool serve --port 80 --log-level debug"
+	serve.Int(&port, "port", 80, "`port` to listen on", false)
+	serve.String(&addr, "addr", "", "address to bind the server to", false)
+	logs := c.NewChild("logs", "print the server's logs")
+	logs.AddAlias("log")
+	c.NewChild("debug", "log everything").Hidden = true
+	c.HelpCommand()
+
+	var got []string
+	for _, m := range c.SearchHelp("LOG") {
+		got = append(got, m.String())
+	}
+	if want := []string{"tool logs", "tool --log-level", "tool serve"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SearchHelp(LOG) = %q, want %q", got, want)
+	}
+	if m := c.SearchHelp("listen"); len(m) != 1 || m[0] != (HelpMatch{Path: "tool serve", Flag: "port", Summary: "port to listen on", Score: weightSummary}) {
+		t.Errorf("SearchHelp(listen) = %+v", m)
+	}
+	if m := c.SearchHelp("  "); m != nil {
+		t.Errorf("SearchHelp of nothing = %+v, want nil", m)
+	}
+
+	var out bytes.Buffer
+	c.SetStdout(&out)
+	c.SetOutput(io.Discard)
+	if err := c.Clone().Execute(HelpName, "--search", "bind", "listen"); err != nil {
+		t.Fatal(err)
+	}
+	if want := "tool serve --addr  address to bind the server to\ntool serve --port  port to listen on\n"; out.String() != want {
+		t.Errorf("help --search printed %q, want %q", out.String(), want)
+	}
+	out.Reset()
+	if err := c.Clone().Execute(HelpName, "serve"); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != serve.UsageString() {
+		t.Errorf("help serve printed %q, want serve's usage", out.String())
+	}
+	if err := c.Clone().Execute(HelpName, "--search", "nothing-like-it"); err == nil {
+		t.Error("a search without matches succeeded")
+	}
+	if err := c.Clone().Execute(HelpName, "missing"); err == nil {
+		t.Error("help for a missing command succeeded")
+	}
+}
@@ -0,0 +1,180 @@
+package mandy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// A CredentialResolver looks up the secrets referenced by the arguments of Secret flags
+// Arguments of the form "scheme:ref" are passed, as ref, to the resolver registered for scheme.
+type CredentialResolver interface {
+	Resolve(ref string) (string, error)
+}
+
+// CredentialResolverFunc adapts a function to the CredentialResolver interface
+type CredentialResolverFunc func(ref string) (string, error)
+
+func (fn CredentialResolverFunc) Resolve(ref string) (string, error) { return fn(ref) }
+
+// ErrNoCredential is returned when a credential resolver cannot find the secret it was asked for
+var ErrNoCredential = errors.New("mandy: credential not found")
+
+// LiteralScheme prefixes the arguments of Secret flags that are to be taken as they are,
+// so that "literal:env:x" stands for "env:x", rather than the variable x
+const LiteralScheme = "literal"
+
+// credentialResolvers maps schemes to the resolvers used by Secret flags
+var credentialResolvers = map[string]CredentialResolver{
+	"env":     envResolver{},
+	"keyring": CredentialResolverFunc(resolveKeyring),
+}
+
+// optionalResolvers maps schemes to the built in resolvers that must be enabled by EnableCredentialResolver
+var optionalResolvers = map[string]CredentialResolver{
+	"cmd": CredentialResolverFunc(resolveCmd),
+}
+
+// RegisterCredentialResolver makes Secret flags resolve arguments prefixed by "scheme:" with r.
+// The built in schemes are env and keyring, and cmd once it's enabled. A nil resolver unregisters the scheme.
+func RegisterCredentialResolver(scheme string, r CredentialResolver) {
+	if r == nil {
+		delete(credentialResolvers, scheme)
+		return
+	}
+	credentialResolvers[scheme] = r
+}
+
+// EnableCredentialResolver registers the built in resolver for a scheme that isn't registered by default.
+// The only one is cmd, which runs its reference as a shell command, so enable it only if the values
+// it may be given are trusted as much as the program is.
+func EnableCredentialResolver(scheme string) error {
+	r, ok := optionalResolvers[scheme]
+	if !ok {
+		return fmt.Errorf("mandy: no optional credential resolver for %q", scheme)
+	}
+	RegisterCredentialResolver(scheme, r)
+	return nil
+}
+
+// envResolver reads secrets from the named environment variables
+// Secret flags use the environment their command's SetEnviron gives; Resolve uses the process's.
+type envResolver struct{}
+
+func (envResolver) Resolve(name string) (string, error) { return resolveEnv(name, os.LookupEnv) }
+
+// resolveEnv reads the secret from the named variable of the environment lookup reads
+func resolveEnv(name string, lookup func(string) (string, bool)) (string, error) {
+	v, ok := lookup(name)
+	if !ok {
+		return "", fmt.Errorf("%w: $%s is unset", ErrNoCredential, name)
+	}
+	return v, nil
+}
+
+// resolveCmd reads the secret from the standard output of a shell command
+func resolveCmd(command string) (string, error) {
+	shell, flag := "sh", "-c"
+	if runtime.GOOS == "windows" {
+		shell, flag = "cmd", "/C"
+	}
+	return runCredentialHelper(shell, flag, command)
+}
+
+// resolveKeyring reads the secret "service/account" from the operating system's keychain:
+// security on macOS, secret-tool elsewhere on unix-likes, and the Credential Manager on windows
+func resolveKeyring(ref string) (string, error) {
+	service, account, ok := strings.Cut(ref, "/")
+	if !ok || service == "" || account == "" {
+		return "", fmt.Errorf("%w: keyring reference %q should look like service/account", errParse, ref)
+	}
+	switch runtime.GOOS {
+	case "darwin":
+		return runCredentialHelper("security", "find-generic-password", "-s", service, "-a", account, "-w")
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return runCredentialHelper("secret-tool", "lookup", "service", service, "account", account)
+	case "windows":
+		return credRead(service + "/" + account)
+	}
+	return "", fmt.Errorf("%w: no keyring support on %s, see RegisterCredentialResolver", ErrNoCredential, runtime.GOOS)
+}
+
+// runCredentialHelper runs a program and returns its output, minus the trailing newline
+func runCredentialHelper(name string, args ...string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command(name, args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w: %s: %v: %s", ErrNoCredential, name, err, msg)
+		}
+		return "", fmt.Errorf("%w: %s: %v", ErrNoCredential, name, err)
+	}
+	return strings.TrimRight(string(out), "\r\n"), nil
+}
+
+// -- secret Value
+// a string whose contents are redacted in usage messages, dumps, and invocation records
+// arguments naming a registered credential scheme are resolved when set from a trusted origin
+type secretValue struct {
+	p      *string
+	source string // the "scheme:ref" the value was resolved from, if any
+}
+
+func newSecretValue(val string, p *string) *secretValue {
+	*p = val
+	return &secretValue{p: p}
+}
+
+// Set takes val as it is, less any LiteralScheme prefix; references to credentials are only resolved by resolve
+func (s *secretValue) Set(val string) error {
+	*s.p, s.source = strings.TrimPrefix(val, LiteralScheme+":"), ""
+	return nil
+}
+
+// resolve sets the value from val, or from the credential it refers to if it names a registered scheme;
+// environment variables are read with lookup
+func (s *secretValue) resolve(val string, lookup func(string) (string, bool)) error {
+	scheme, ref, ok := strings.Cut(val, ":")
+	r, registered := credentialResolvers[scheme]
+	if !ok || !registered {
+		return s.Set(val)
+	}
+	var v string
+	var err error
+	if _, ok := r.(envResolver); ok {
+		v, err = resolveEnv(ref, lookup)
+	} else {
+		v, err = r.Resolve(ref)
+	}
+	if err != nil {
+		return err
+	}
+	*s.p, s.source = v, val
+	return nil
+}
+
+func (s *secretValue) Get() any { return *s.p }
+func (s *secretValue) String() string {
+	if s.p == nil {
+		return ""
+	}
+	return *s.p
+}
+func (s *secretValue) IsBool() bool { return false }
+func (s *secretValue) redacted()    {}
+
+// Provenance reports the credential reference the value was resolved from, if any
+func (s *secretValue) Provenance() string { return s.source }
+
+// resolvingValue is implemented by values that may refer to values kept elsewhere, such as credentials;
+// resolve sets the value from val, following the reference if it is one. Command.setFrom only calls it
+// for values whose Origin is trusted, and calls Set, which takes values as they are, for the rest.
+type resolvingValue interface {
+	resolve(val string, lookup func(string) (string, bool)) error
+}
@@ -0,0 +1,121 @@
+package mandy
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSecretResolvers(t *testing.T) {
+	t.Setenv("MANDY_TEST_TOKEN", "hunter2")
+	RegisterCredentialResolver("test", CredentialResolverFunc(func(ref string) (string, error) {
+		return "resolved " + ref, nil
+	}))
+	defer RegisterCredentialResolver("test", nil)
+
+	var token string
+	c := NewCommand("test", ContinueOnError)
+	c.Secret(&token, "token", "", "api token", false)
+
+	for arg, want := range map[string]string{
+		"env:MANDY_TEST_TOKEN": "hunter2",
+		"test:ref":             "resolved ref",
+		"plain:text":           "plain:text",
+		"literal":              "literal",
+	} {
+		if err := c.Set("token", arg); err != nil {
+			t.Errorf("%s: %v", arg, err)
+		} else if token != want {
+			t.Errorf("%s gave %q, want %q", arg, token, want)
+		}
+	}
+	if err := c.Set("token", "env:MANDY_TEST_UNSET"); !errors.Is(err, ErrNoCredential) {
+		t.Errorf("expected ErrNoCredential, got %v", err)
+	}
+
+	if err := c.Set("token", "env:MANDY_TEST_TOKEN"); err != nil {
+		t.Fatal(err)
+	}
+	dump := c.Dump()
+	if strings.Contains(dump, "hunter2") || !strings.Contains(dump, "token=***\t(set from env:MANDY_TEST_TOKEN)") {
+		t.Errorf("unexpected dump %q", dump)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	var token string
+	var port int
+	c := NewCommand("test", ContinueOnError)
+	c.Secret(&token, "token", "", "api token", false).Redact(RevealLast(4))
+	c.Int(&port, "port", 0, "port to serve on", false).Redact(func(string) string { return "<port>" })
+
+	for value, want := range map[string]string{"": "", "abc": Redacted, "sk-live-12345678": Redacted + "5678"} {
+		if got := c.Lookup("token").redact(value); got != want {
+			t.Errorf("redact(%q) = %q, want %q", value, got, want)
+		}
+	}
+	if err := c.Parse("--token", "sk-live-12345678", "--port=8080"); err != nil {
+		t.Fatal(err)
+	}
+	dump := c.Dump()
+	if strings.Contains(dump, "sk-live") || !strings.Contains(dump, "token=***5678\t") || !strings.Contains(dump, "port=<port>\t") {
+		t.Errorf("unexpected dump %q", dump)
+	}
+	for _, f := range c.Invocation().Commands[0].Flags {
+		if f.Value == "sk-live-12345678" || f.Value == "8080" {
+			t.Errorf("invocation records --%s unredacted", f.Name)
+		}
+	}
+	err := c.Parse("--port", "80a80")
+	if err == nil || strings.Contains(err.Error(), "80a80") || !strings.Contains(err.Error(), "<port>") {
+		t.Errorf("error for a bad --port: %v", err)
+	}
+}
+
+func TestCredentialTrust(t *testing.T) {
+	t.Setenv("MANDY_TEST_TOKEN", "hunter2")
+	if _, ok := credentialResolvers["cmd"]; ok {
+		t.Fatal("cmd resolver is registered by default")
+	}
+	if err := EnableCredentialResolver("cmd"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := credentialResolvers["cmd"]; !ok {
+		t.Error("EnableCredentialResolver(cmd) left it unregistered")
+	}
+	RegisterCredentialResolver("cmd", nil)
+	if err := EnableCredentialResolver("nope"); err == nil {
+		t.Error("enabled an unknown resolver")
+	}
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"token": "env:MANDY_TEST_TOKEN"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		args   []string
+		config bool
+		want   string
+	}{
+		{[]string{"--token", "env:MANDY_TEST_TOKEN"}, false, "hunter2"},
+		{[]string{"--token", "literal:env:MANDY_TEST_TOKEN"}, false, "env:MANDY_TEST_TOKEN"},
+		{[]string{"--"}, true, "hunter2"}, // config files the program chooses are trusted
+		{[]string{"--token", "literal:x"}, true, "x"},
+	} {
+		var token string
+		c := NewCommand("test", ContinueOnError)
+		c.Secret(&token, "token", "", "api token", false)
+		if tc.config {
+			if err := c.LoadConfig(path); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := c.Parse(tc.args...); err != nil {
+			t.Fatal(err)
+		} else if token != tc.want {
+			t.Errorf("%q (config %v) gave %q, want %q", tc.args, tc.config, token, tc.want)
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package mandy
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kendfss/iters/slices"
+)
+
+// A SemVer is a semantic version, as described at semver.org
+type SemVer struct {
+	Major, Minor, Patch uint64
+	Pre                 string // dot separated pre-release identifiers
+	Build               string // build metadata, ignored when comparing
+}
+
+// ParseSemVer parses versions like "1.2.3", "v1.2.3-rc.1+build.5", or partial versions like "1.2"
+func ParseSemVer(s string) (SemVer, error) {
+	v, _, err := parseSemVer(s)
+	return v, err
+}
+
+// parseSemVer also reports how many numeric components were given
+func parseSemVer(s string) (v SemVer, parts int, err error) {
+	orig := s
+	s = strings.TrimPrefix(s, "v")
+	s, v.Build, _ = strings.Cut(s, "+")
+	s, v.Pre, _ = strings.Cut(s, "-")
+	nums := strings.Split(s, ".")
+	if len(nums) > 3 {
+		return SemVer{}, 0, fmt.Errorf("%w: version %q has too many components", errParse, orig)
+	}
+	dst := []*uint64{&v.Major, &v.Minor, &v.Patch}
+	for i, num := range nums {
+		if num == "" || (len(num) > 1 && num[0] == '0') {
+			return SemVer{}, 0, fmt.Errorf("%w: malformed version %q", errParse, orig)
+		}
+		if *dst[i], err = strconv.ParseUint(num, 10, 64); err != nil {
+			return SemVer{}, 0, fmt.Errorf("%w: malformed version %q", numError(err), orig)
+		}
+	}
+	return v, len(nums), nil
+}
+
+// String formats the version without a "v" prefix
+func (v SemVer) String() string {
+	out := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Pre != "" {
+		out += "-" + v.Pre
+	}
+	if v.Build != "" {
+		out += "+" + v.Build
+	}
+	return out
+}
+
+// Compare returns -1, 0, or +1 depending on whether v precedes, equals, or follows w
+func (v SemVer) Compare(w SemVer) int {
+	for _, d := range [][2]uint64{{v.Major, w.Major}, {v.Minor, w.Minor}, {v.Patch, w.Patch}} {
+		if d[0] != d[1] {
+			return cmpOrdered(d[0], d[1])
+		}
+	}
+	switch {
+	case v.Pre == w.Pre:
+		return 0
+	case v.Pre == "":
+		return 1
+	case w.Pre == "":
+		return -1
+	}
+	vs, ws := strings.Split(v.Pre, "."), strings.Split(w.Pre, ".")
+	for i := 0; i < len(vs) && i < len(ws); i++ {
+		if vs[i] == ws[i] {
+			continue
+		}
+		vn, verr := strconv.ParseUint(vs[i], 10, 64)
+		wn, werr := strconv.ParseUint(ws[i], 10, 64)
+		switch {
+		case verr == nil && werr == nil:
+			return cmpOrdered(vn, wn)
+		case verr == nil:
+			return -1
+		case werr == nil:
+			return 1
+		}
+		return strings.Compare(vs[i], ws[i])
+	}
+	return cmpOrdered(len(vs), len(ws))
+}
+
+func cmpOrdered[T uint64 | int](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// A Constraint is a set of version requirements, like ">=1.2 <2 || ^3.1"
+// Space separated comparisons must all hold, while "||" separates alternatives.
+// The operators are =, !=, >, >=, <, <=, ~ (same minor version), and ^ (same major version).
+type Constraint struct {
+	text string
+	any  [][]comparison
+}
+
+type comparison struct {
+	op  string
+	ver SemVer
+	max SemVer // exclusive upper bound for ~ and ^
+}
+
+// ParseConstraint parses a version constraint
+func ParseConstraint(s string) (Constraint, error) {
+	c := Constraint{text: strings.Join(strings.Fields(s), " ")}
+	for _, alt := range strings.Split(s, "||") {
+		var all []comparison
+		for _, term := range strings.Fields(alt) {
+			cmp, err := parseComparison(term)
+			if err != nil {
+				return Constraint{}, fmt.Errorf("%w in constraint %q", err, s)
+			}
+			all = append(all, cmp)
+		}
+		if len(all) == 0 {
+			return Constraint{}, fmt.Errorf("%w: constraint %q has an empty alternative", errParse, s)
+		}
+		c.any = append(c.any, all)
+	}
+	return c, nil
+}
+
+func parseComparison(term string) (comparison, error) {
+	op := term[:len(term)-len(strings.TrimLeft(term, "=!<>~^"))]
+	if op != "" && !slices.Contains([]string{"=", "!=", ">", ">=", "<", "<=", "~", "^"}, op) {
+		return comparison{}, fmt.Errorf("%w: unknown operator %q", errParse, op)
+	}
+	ver, parts, err := parseSemVer(term[len(op):])
+	if err != nil {
+		return comparison{}, err
+	}
+	cmp := comparison{op: op, ver: ver}
+	switch op {
+	case "", "=":
+		cmp.op = "="
+		// partial versions match everything they leave unspecified
+		if parts < 3 {
+			cmp.op, cmp.max = "~", bump(ver, parts-1)
+		}
+	case "~":
+		cmp.max = bump(ver, min(parts-1, 1))
+	case "^":
+		switch {
+		case ver.Major > 0 || parts == 1:
+			cmp.max = bump(ver, 0)
+		case ver.Minor > 0 || parts == 2:
+			cmp.max = bump(ver, 1)
+		default:
+			cmp.max = bump(ver, 2)
+		}
+	}
+	return cmp, nil
+}
+
+// bump increments the version's ith component, zeroing those after it
+func bump(v SemVer, i int) SemVer {
+	switch i {
+	case 0:
+		return SemVer{Major: v.Major + 1}
+	case 1:
+		return SemVer{Major: v.Major, Minor: v.Minor + 1}
+	}
+	return SemVer{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}
+}
+
+func (cmp comparison) check(v SemVer) bool {
+	c := v.Compare(cmp.ver)
+	switch cmp.op {
+	case "=":
+		return c == 0
+	case "!=":
+		return c != 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	}
+	// ~ and ^
+	return c >= 0 && v.Compare(cmp.max) < 0
+}
+
+// Check reports whether v satisfies the constraint.
+// Pre-release versions only satisfy alternatives which mention
+// a pre-release of the same major, minor, and patch version.
+func (c Constraint) Check(v SemVer) bool {
+	for _, all := range c.any {
+		ok := v.Pre == ""
+		for _, cmp := range all {
+			if cmp.ver.Pre != "" && cmp.ver.Major == v.Major && cmp.ver.Minor == v.Minor && cmp.ver.Patch == v.Patch {
+				ok = true
+			}
+		}
+		for _, cmp := range all {
+			ok = ok && cmp.check(v)
+		}
+		if ok {
+			return true
+		}
+	}
+	return len(c.any) == 0
+}
+
+// String returns the constraint as it was written, with normalized spacing
+func (c Constraint) String() string {
+	return c.text
+}
+
+// -- SemVer Value
+type semVerValue SemVer
+
+func newSemVerValue(val SemVer, p *SemVer) *semVerValue {
+	*p = val
+	return (*semVerValue)(p)
+}
+
+func (v *semVerValue) Set(s string) error {
+	ver, err := ParseSemVer(s)
+	if err != nil {
+		return err
+	}
+	*v = semVerValue(ver)
+	return nil
+}
+
+func (v *semVerValue) Get() any       { return SemVer(*v) }
+func (v *semVerValue) String() string { return SemVer(*v).String() }
+func (v *semVerValue) IsBool() bool   { return false }
+
+// -- Constraint Value
+type constraintValue Constraint
+
+func newConstraintValue(val Constraint, p *Constraint) *constraintValue {
+	*p = val
+	return (*constraintValue)(p)
+}
+
+func (v *constraintValue) Set(s string) error {
+	c, err := ParseConstraint(s)
+	if err != nil {
+		return err
+	}
+	*v = constraintValue(c)
+	return nil
+}
+
+func (v *constraintValue) Get() any       { return Constraint(*v) }
+func (v *constraintValue) String() string { return Constraint(*v).String() }
+func (v *constraintValue) IsBool() bool   { return false }
+
+// SemVer defines a SemVer flag with specified name, default value, and usage string.
+// The argument p points to a SemVer variable in which to store the value of the flag.
+// The flag accepts a value acceptable to ParseSemVer.
+func (c *Command) SemVer(p *SemVer, name string, value SemVer, usage string, short bool) *Flag {
+	return c.Var(newSemVerValue(value, p), name, usage, short)
+}
+
+// Constraint defines a version Constraint flag with specified name, default value, and usage string.
+// The argument p points to a Constraint variable in which to store the value of the flag.
+// The flag accepts a value acceptable to ParseConstraint.
+func (c *Command) Constraint(p *Constraint, name string, value Constraint, usage string, short bool) *Flag {
+	return c.Var(newConstraintValue(value, p), name, usage, short)
+}
@@ -0,0 +1,76 @@
+package mandy
+
+import "testing"
+
+func TestSemVerCompare(t *testing.T) {
+	// in ascending order, per semver.org
+	order := []string{"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.1.0", "2.0.0"}
+	for i := 1; i < len(order); i++ {
+		a, err := ParseSemVer(order[i-1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := ParseSemVer(order[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a.Compare(b) != -1 || b.Compare(a) != 1 {
+			t.Errorf("expected %s < %s", a, b)
+		}
+	}
+	for _, bad := range []string{"", "1.", "01.2.3", "1.2.3.4", "a.b.c"} {
+		if _, err := ParseSemVer(bad); err == nil {
+			t.Errorf("ParseSemVer(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestConstraint(t *testing.T) {
+	tests := []struct {
+		constraint string
+		match      []string
+		miss       []string
+	}{
+		{">=1.2 <2", []string{"1.2.0", "1.9.9"}, []string{"1.1.9", "2.0.0", "2.0.0-rc.1"}},
+		{"^1.2.3", []string{"1.2.3", "1.9.0"}, []string{"1.2.2", "2.0.0"}},
+		{"^0.2.3", []string{"0.2.3", "0.2.9"}, []string{"0.3.0"}},
+		{"~1.2", []string{"1.2.0", "1.2.9"}, []string{"1.3.0"}},
+		{"1.2", []string{"1.2.5"}, []string{"1.3.0"}},
+		{"<1 || >=3", []string{"0.9.0", "3.1.0"}, []string{"1.0.0", "2.5.0"}},
+		{"!=1.0.0", []string{"1.0.1"}, []string{"1.0.0"}},
+	}
+	for _, test := range tests {
+		c, err := ParseConstraint(test.constraint)
+		if err != nil {
+			t.Errorf("ParseConstraint(%q): %v", test.constraint, err)
+			continue
+		}
+		for _, s := range test.match {
+			if v, _ := ParseSemVer(s); !c.Check(v) {
+				t.Errorf("%q should match %s", test.constraint, s)
+			}
+		}
+		for _, s := range test.miss {
+			if v, _ := ParseSemVer(s); c.Check(v) {
+				t.Errorf("%q should not match %s", test.constraint, s)
+			}
+		}
+	}
+	for _, bad := range []string{"", "=>1", "1.2 ||", "<x"} {
+		if _, err := ParseConstraint(bad); err == nil {
+			t.Errorf("ParseConstraint(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestConstraintPreRelease(t *testing.T) {
+	c, err := ParseConstraint(">=2.0.0-rc.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for s, want := range map[string]bool{"2.0.0-rc.2": true, "2.0.0": true, "2.1.0-beta": false} {
+		if v, _ := ParseSemVer(s); c.Check(v) != want {
+			t.Errorf("Check(%s) = %t, want %t", s, !want, want)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package mandy
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ParseSignal parses a signal's name, with or without its "SIG" prefix and in any case, or its number.
+// Names are those known on the platform the program was built for.
+func ParseSignal(s string) (os.Signal, error) {
+	if n, err := strconv.Atoi(s); err == nil {
+		if sig, ok := signalNumber(n); ok {
+			return sig, nil
+		}
+		return nil, fmt.Errorf("%w: signal %d is not supported on this platform", errRange, n)
+	}
+	name := strings.ToUpper(s)
+	if !strings.HasPrefix(name, "SIG") {
+		name = "SIG" + name
+	}
+	if sig, ok := signals[name]; ok {
+		return sig, nil
+	}
+	return nil, fmt.Errorf("%w: unknown signal %q", errParse, s)
+}
+
+// SignalName returns the conventional name of sig, such as "SIGTERM", or its string form if it has none
+func SignalName(sig os.Signal) string {
+	for name, known := range signals {
+		if known == sig {
+			return name
+		}
+	}
+	if sig == nil {
+		return ""
+	}
+	return sig.String()
+}
+
+// signalNames returns the names of the platform's signals, sorted
+func signalNames() []string {
+	names := keys(signals)
+	slices.Sort(names)
+	return names
+}
+
+// -- os.Signal Value
+type signalValue struct {
+	p *os.Signal
+}
+
+func newSignalValue(val os.Signal, p *os.Signal) *signalValue {
+	*p = val
+	return &signalValue{p: p}
+}
+
+func (s *signalValue) Set(arg string) error {
+	sig, err := ParseSignal(arg)
+	if err != nil {
+		return err
+	}
+	*s.p = sig
+	return nil
+}
+
+func (s *signalValue) Get() any { return *s.p }
+
+func (s *signalValue) String() string {
+	if s.p == nil {
+		return ""
+	}
+	return SignalName(*s.p)
+}
+
+func (s *signalValue) IsBool() bool { return false }
+
+func (s *signalValue) clone() Getter {
+	p := *s.p
+	return &signalValue{p: &p}
+}
+
+// Complete suggests the names of the platform's signals
+func (s *signalValue) Complete(prefix string) (out []string) {
+	upper := strings.ToUpper(prefix)
+	for _, name := range signalNames() {
+		if strings.HasPrefix(name, upper) || strings.HasPrefix(name[len("SIG"):], upper) {
+			out = append(out, name)
+		}
+	}
+	return
+}
+
+// Signal defines an os.Signal flag with specified name, default value, and usage string.
+// The argument p points to an os.Signal variable in which to store the value of the flag.
+// The flag accepts a value acceptable to ParseSignal.
+func (c *Command) Signal(p *os.Signal, name string, value os.Signal, usage string, short bool) *Flag {
+	return c.Var(newSignalValue(value, p), name, usage, short)
+}
@@ -0,0 +1,18 @@
+//go:build !unix && !windows
+
+package mandy
+
+import (
+	"os"
+)
+
+// signals maps the names of the signals portable to every platform to their values
+var signals = map[string]os.Signal{
+	"SIGINT":  os.Interrupt,
+	"SIGKILL": os.Kill,
+}
+
+// signalNumber reports false; signals have no numbers on this platform
+func signalNumber(n int) (os.Signal, bool) {
+	return nil, false
+}
@@ -0,0 +1,36 @@
+package mandy
+
+import (
+	"os"
+	"slices"
+	"testing"
+)
+
+func TestParseSignal(t *testing.T) {
+	for _, in := range []string{"INT", "SIGINT", "sigint", "int"} {
+		if sig, err := ParseSignal(in); err != nil || sig != signals["SIGINT"] {
+			t.Errorf("%q: got %v, %v", in, sig, err)
+		}
+	}
+	if _, err := ParseSignal("SIGNOPE"); err == nil {
+		t.Error("unknown signals should not parse")
+	}
+	if SignalName(signals["SIGKILL"]) != "SIGKILL" {
+		t.Errorf("SignalName: %q", SignalName(signals["SIGKILL"]))
+	}
+}
+
+func TestSignalFlag(t *testing.T) {
+	var sig os.Signal
+	c := NewCommand("kill", ContinueOnError)
+	flag := c.Signal(&sig, "signal", os.Interrupt, "signal to send", true)
+	if flag.DefValue != "SIGINT" {
+		t.Errorf("default renders as %q", flag.DefValue)
+	}
+	if err := c.Parse("-s", "kill"); err != nil || sig != os.Kill {
+		t.Errorf("got %v, %v", sig, err)
+	}
+	if got := flag.Completions("ki"); !slices.Equal(got, []string{"SIGKILL"}) {
+		t.Errorf("completions: %v", got)
+	}
+}
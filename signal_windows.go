@@ -0,0 +1,35 @@
+//go:build windows
+
+package mandy
+
+import (
+	"os"
+	"syscall"
+)
+
+// signals maps the names of the signals the syscall package emulates on windows to their values
+var signals = map[string]os.Signal{
+	"SIGABRT": syscall.SIGABRT,
+	"SIGALRM": syscall.SIGALRM,
+	"SIGBUS":  syscall.SIGBUS,
+	"SIGFPE":  syscall.SIGFPE,
+	"SIGHUP":  syscall.SIGHUP,
+	"SIGILL":  syscall.SIGILL,
+	"SIGINT":  syscall.SIGINT,
+	"SIGKILL": syscall.SIGKILL,
+	"SIGPIPE": syscall.SIGPIPE,
+	"SIGQUIT": syscall.SIGQUIT,
+	"SIGSEGV": syscall.SIGSEGV,
+	"SIGTERM": syscall.SIGTERM,
+	"SIGTRAP": syscall.SIGTRAP,
+}
+
+// signalNumber returns the known signal numbered n
+func signalNumber(n int) (os.Signal, bool) {
+	for _, sig := range signals {
+		if int(sig.(syscall.Signal)) == n {
+			return sig, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,248 @@
+package mandy
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultSeparator splits the arguments of slice and map flags into elements
+const DefaultSeparator = ","
+
+// separatedValue is implemented by Values which split each argument into several elements
+type separatedValue interface {
+	Getter
+	separator() string
+	setSeparator(sep string)
+}
+
+// repeatedValue is a separatedValue that, without a separator, takes one element per occurrence
+type repeatedValue interface {
+	separatedValue
+	occurrences() []string
+}
+
+// Separator sets the string splitting each of the flag's arguments into elements.
+// An empty separator disables splitting, so that each occurrence of the flag adds
+// exactly one element. A separator may be escaped with a backslash to keep it in
+// an element, as may a backslash itself.
+// Separator panics if the flag's value does not hold several elements.
+func (f *Flag) Separator(sep string) *Flag {
+	sv, ok := f.Value.(separatedValue)
+	if !ok {
+		panic(fmt.Sprintf("flag %q does not accept a separator", f.Name))
+	}
+	sv.setSeparator(sep)
+	return f
+}
+
+// convention describes how the flag's arguments are split, if they are
+func (f Flag) convention() string {
+	sv, ok := f.Value.(separatedValue)
+	if !ok {
+		return ""
+	}
+	elems := elements(f.Value)
+	switch {
+	case sv.separator() == "" && elems == "":
+		return "(repeatable)"
+	case sv.separator() == "":
+		return "(" + elems + ", repeatable)"
+	case elems == "":
+		return fmt.Sprintf("(%q separated, repeatable)", sv.separator())
+	default:
+		return fmt.Sprintf("(%q separated %s, repeatable)", sv.separator(), elems)
+	}
+}
+
+// elements names the type of the elements of the built in slice values
+// returns an empty string for other values
+func elements(v Getter) string {
+	switch unwrap(v).(type) {
+	case *sliceValue[string]:
+		return "strings"
+	case *sliceValue[int]:
+		return "ints"
+	case *sliceValue[uint]:
+		return "uints"
+	case *sliceValue[float64]:
+		return "floats"
+	case *sliceValue[time.Duration]:
+		return "durations"
+	}
+	return ""
+}
+
+// splitEscaped splits s around unescaped instances of sep
+// backslashes escape sep and themselves, while other backslashes are kept as is
+func splitEscaped(s, sep string) (out []string) {
+	if sep == "" {
+		return []string{s}
+	}
+	var elem strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && strings.HasPrefix(s[i+1:], sep):
+			elem.WriteString(sep)
+			i += len(sep)
+		case s[i] == '\\' && i+1 < len(s) && s[i+1] == '\\':
+			elem.WriteByte('\\')
+			i++
+		case strings.HasPrefix(s[i:], sep):
+			out = append(out, elem.String())
+			elem.Reset()
+			i += len(sep) - 1
+		default:
+			elem.WriteByte(s[i])
+		}
+	}
+	return append(out, elem.String())
+}
+
+// escapeSeparator escapes s so that splitEscaped keeps it as a single element
+func escapeSeparator(s, sep string) string {
+	if sep == "" {
+		return s
+	}
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return strings.ReplaceAll(s, sep, `\`+sep)
+}
+
+// -- slice Value
+// the first argument replaces the default, later ones append to it
+type sliceValue[T any] struct {
+	p       *[]T
+	parse   func(string) (T, error)
+	format  func(T) string
+	sep     string
+	changed bool
+}
+
+func newSliceValue[T any](val []T, p *[]T, parse func(string) (T, error), format func(T) string) *sliceValue[T] {
+	*p = append([]T(nil), val...)
+	return &sliceValue[T]{p: p, parse: parse, format: format, sep: DefaultSeparator}
+}
+
+func (s *sliceValue[T]) Set(arg string) error {
+	var elems []T
+	for _, part := range splitEscaped(arg, s.sep) {
+		elem, err := s.parse(part)
+		if err != nil {
+			return err
+		}
+		elems = append(elems, elem)
+	}
+	if !s.changed {
+		*s.p = nil
+		s.changed = true
+	}
+	*s.p = append(*s.p, elems...)
+	return nil
+}
+
+func (s *sliceValue[T]) settle() { s.changed = false }
+
+// String joins the elements with the separator, escaping it in them, or, if there is none,
+// with DefaultSeparator as they are, for display only; see occurrences
+func (s *sliceValue[T]) String() string {
+	if s.p == nil {
+		return ""
+	}
+	if s.sep == "" {
+		return strings.Join(s.occurrences(), DefaultSeparator)
+	}
+	parts := make([]string, len(*s.p))
+	for i, elem := range *s.p {
+		parts[i] = escapeSeparator(s.format(elem), s.sep)
+	}
+	return strings.Join(parts, s.sep)
+}
+
+// occurrences returns the arguments of the flag occurrences, one per element, that would set the value
+func (s *sliceValue[T]) occurrences() []string {
+	parts := make([]string, len(*s.p))
+	for i, elem := range *s.p {
+		parts[i] = s.format(elem)
+	}
+	return parts
+}
+
+func (s *sliceValue[T]) Get() any                { return append([]T(nil), *s.p...) }
+func (s *sliceValue[T]) IsBool() bool            { return false }
+func (s *sliceValue[T]) separator() string       { return s.sep }
+func (s *sliceValue[T]) setSeparator(sep string) { s.sep = sep }
+
+// StringSlice defines a []string flag with specified name, default value, and usage string.
+// The argument p points to a []string variable in which to store the value of the flag.
+// Each occurrence of the flag accepts a comma separated list of elements; see Flag.Separator.
+func (c *Command) StringSlice(p *[]string, name string, value []string, usage string, short bool) *Flag {
+	return c.Var(newSliceValue(value, p, parseString, formatString), name, usage, short)
+}
+
+// StringArray defines a []string flag with specified name, default value, and usage string.
+// The argument p points to a []string variable in which to store the value of the flag.
+// Unlike StringSlice's, its arguments are never split, so each occurrence adds exactly one element,
+// commas and all, as with --header "Accept: a,b".
+func (c *Command) StringArray(p *[]string, name string, value []string, usage string, short bool) *Flag {
+	v := newSliceValue(value, p, parseString, formatString)
+	v.sep = ""
+	return c.Var(v, name, usage, short)
+}
+
+// IntSlice defines a []int flag with specified name, default value, and usage string.
+// The argument p points to a []int variable in which to store the value of the flag.
+// Each occurrence of the flag accepts a comma separated list of integers.
+func (c *Command) IntSlice(p *[]int, name string, value []int, usage string, short bool) *Flag {
+	return c.Var(newSliceValue(value, p, parseInt, strconv.Itoa), name, usage, short)
+}
+
+// UintSlice defines a []uint flag with specified name, default value, and usage string.
+// The argument p points to a []uint variable in which to store the value of the flag.
+// Each occurrence of the flag accepts a comma separated list of unsigned integers.
+func (c *Command) UintSlice(p *[]uint, name string, value []uint, usage string, short bool) *Flag {
+	return c.Var(newSliceValue(value, p, parseUint, formatUint), name, usage, short)
+}
+
+// Float64Slice defines a []float64 flag with specified name, default value, and usage string.
+// The argument p points to a []float64 variable in which to store the value of the flag.
+// Each occurrence of the flag accepts a comma separated list of numbers.
+func (c *Command) Float64Slice(p *[]float64, name string, value []float64, usage string, short bool) *Flag {
+	return c.Var(newSliceValue(value, p, parseFloat64, formatFloat64), name, usage, short)
+}
+
+func parseString(s string) (string, error) { return s, nil }
+func formatString(s string) string         { return s }
+
+// parseInt parses integers as Int flags do
+func parseInt(s string) (int, error) {
+	v, err := strconv.ParseInt(s, 0, strconv.IntSize)
+	return int(v), numError(err)
+}
+
+// parseUint parses unsigned integers as Uint flags do
+func parseUint(s string) (uint, error) {
+	v, err := strconv.ParseUint(s, 0, strconv.IntSize)
+	return uint(v), numError(err)
+}
+
+// parseFloat64 parses numbers as Float64 flags do
+func parseFloat64(s string) (float64, error) {
+	v, err := strconv.ParseFloat(s, 64)
+	return v, numError(err)
+}
+
+func formatUint(u uint) string       { return strconv.FormatUint(uint64(u), 10) }
+func formatFloat64(f float64) string { return strconv.FormatFloat(f, 'g', -1, 64) }
+
+// SliceVar defines a slice flag, whose elements may be strings, bools, integers, floats, durations,
+// or of a type whose pointer implements Value, bound to p.
+// Each occurrence of the flag accepts a comma separated list of elements; see Flag.Separator.
+// SliceVar panics if the type of the elements is unsupported.
+func SliceVar[T any](c *Command, p *[]T, name string, value []T, usage string, short bool) *Flag {
+	elem, ok := codecFor[T]()
+	if !ok {
+		panic(c.sprintf("flag %q has unsupported element type %T", name, *new(T)))
+	}
+	return c.Var(newSliceValue(value, p, elem.parse, elem.format), name, usage, short)
+}
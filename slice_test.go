@@ -0,0 +1,159 @@
+package mandy
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSplitEscaped(t *testing.T) {
+	tests := []struct {
+		in, sep string
+		want    []string
+	}{
+		{"a,b,c", ",", []string{"a", "b", "c"}},
+		{`a\,b,c`, ",", []string{"a,b", "c"}},
+		{`a\\,b`, ",", []string{`a\`, "b"}},
+		{`a\b`, ",", []string{`a\b`}},
+		{"a::b", "::", []string{"a", "b"}},
+		{"a,b", "", []string{"a,b"}},
+	}
+	for _, test := range tests {
+		if got := splitEscaped(test.in, test.sep); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("splitEscaped(%q, %q) = %q, want %q", test.in, test.sep, got, test.want)
+		}
+	}
+}
+
+func TestSliceValue(t *testing.T) {
+	var got []int
+	v := newSliceValue([]int{7}, &got, strconv.Atoi, strconv.Itoa)
+	c := NewCommand("test", ContinueOnError)
+	f := c.Var(v, "num", "numbers", false)
+
+	if err := c.Parse("--num=1,2", "--num", "3"); err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if f.DefValue != "7" {
+		t.Errorf("DefValue = %q, want 7", f.DefValue)
+	}
+	if want := `numbers (",;" separated ints, repeatable)`; f.Separator(",;").description() != want {
+		t.Errorf("description = %q, want %q", f.description(), want)
+	}
+	if want := "numbers (ints, repeatable)"; f.Separator("").description() != want {
+		t.Errorf("description = %q, want %q", f.description(), want)
+	}
+}
+
+func TestSliceFlags(t *testing.T) {
+	var (
+		tags   []string
+		ports  []int
+		ids    []uint
+		ratios []float64
+	)
+	c := NewCommand("test", ContinueOnError)
+	c.StringSlice(&tags, "tag", []string{"default"}, "tags to apply", false)
+	c.IntSlice(&ports, "port", nil, "ports to open", false)
+	c.UintSlice(&ids, "id", nil, "ids to fetch", false).Separator(";")
+	weights := c.Float64Slice(&ratios, "ratio", []float64{0.5, 1}, "weights", false)
+
+	if err := c.Parse("--tag", "a,b", "--tag=c", "--port=80,0x1bb", "--id", "1;2", "--", "x"); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(tags, []string{"a", "b", "c"}) || !reflect.DeepEqual(ports, []int{80, 443}) || !reflect.DeepEqual(ids, []uint{1, 2}) {
+		t.Errorf("tags, ports, ids = %q, %v, %v", tags, ports, ids)
+	}
+	if !reflect.DeepEqual(ratios, []float64{0.5, 1}) {
+		t.Errorf("ratios = %v, want the default", ratios)
+	}
+	if want := `--ratio	weights ("," separated floats, repeatable) [default: 0.5,1]`; weights.usage(DefaultInline) != want {
+		t.Errorf("usage = %q, want %q", weights.usage(DefaultInline), want)
+	}
+	if err := c.Parse("--port", "eighty"); !errors.Is(err, errParse) {
+		t.Errorf("invalid element: err = %v, want errParse", err)
+	}
+	if name, _ := UnquoteUsage(c.Lookup("id")); name != "uints" {
+		t.Errorf("UnquoteUsage name = %q, want uints", name)
+	}
+}
+
+func TestStringArray(t *testing.T) {
+	var headers []string
+	c := NewCommand("test", ContinueOnError)
+	f := c.StringArray(&headers, "header", []string{"User-Agent: mandy"}, "headers to send", false)
+
+	if err := c.Parse("--header", "Accept: a,b", `--header=X-Path: a\b`); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"Accept: a,b", `X-Path: a\b`}; !reflect.DeepEqual(headers, want) {
+		t.Errorf("headers = %q, want %q", headers, want)
+	}
+	if want := "headers to send (strings, repeatable)"; f.description() != want {
+		t.Errorf("description = %q, want %q", f.description(), want)
+	}
+	if want := `Accept: a,b,X-Path: a\b`; f.Value.String() != want {
+		t.Errorf("String() = %q, want %q", f.Value.String(), want)
+	}
+	want := `test '--header=Accept: a,b' '--header=X-Path: a\b'`
+	if got := c.Invocation().Quoted(PosixShell); got != want {
+		t.Errorf("invocation = %s, want %s", got, want)
+	}
+}
+
+// level is a Value for testing collections of user defined elements
+type level int
+
+func (l *level) Set(s string) error {
+	switch s {
+	case "low":
+		*l = 0
+	case "high":
+		*l = 1
+	default:
+		return errParse
+	}
+	return nil
+}
+
+func (l *level) String() string { return [...]string{"low", "high"}[*l] }
+func (l *level) IsBool() bool   { return false }
+
+func TestSliceVar(t *testing.T) {
+	var (
+		waits  []time.Duration
+		flags  []bool
+		levels []level
+	)
+	c := NewCommand("test", ContinueOnError)
+	SliceVar(c, &waits, "wait", []time.Duration{time.Second}, "waits", false)
+	SliceVar(c, &flags, "flag", nil, "switches", false)
+	f := SliceVar(c, &levels, "level", []level{1}, "levels", false)
+
+	if f.DefValue != "high" {
+		t.Errorf("DefValue = %q, want high", f.DefValue)
+	}
+	if err := c.Parse("--wait=1m,2s", "--flag", "true,false", "--level", "low,high"); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(waits, []time.Duration{time.Minute, 2 * time.Second}) || !reflect.DeepEqual(flags, []bool{true, false}) || !reflect.DeepEqual(levels, []level{0, 1}) {
+		t.Errorf("waits, flags, levels = %v, %v, %v", waits, flags, levels)
+	}
+	if err := c.Parse("--level", "medium"); !errors.Is(err, errParse) {
+		t.Errorf("invalid level: err = %v, want errParse", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("SliceVar accepted an unsupported element type")
+		}
+	}()
+	c.SetOutput(io.Discard)
+	SliceVar(c, new([]complex128), "complex", nil, "", false)
+}
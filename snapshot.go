@@ -0,0 +1,97 @@
+package mandy
+
+import (
+	"maps"
+	"reflect"
+)
+
+// FlagState is a record of the values of a command's flags, and which of them were set, taken by Snapshot
+type FlagState struct {
+	restores []func()
+	actual   map[*Command]map[string]*Flag
+}
+
+// snapshotter is implemented by values whose storage can't be captured by reflection alone;
+// snapshot returns a function restoring the value's current contents
+type snapshotter interface {
+	snapshot() func()
+}
+
+// snapshotValue returns a function restoring v's current contents.
+// Values of types foreign to this package are restored only if they are pointers to plain data.
+func snapshotValue(v Getter) func() {
+	if s, ok := v.(snapshotter); ok {
+		return s.snapshot()
+	}
+	return snapshotPointer(v)
+}
+
+// snapshotPointer returns a function restoring the data p points to, if it is a pointer
+func snapshotPointer(p any) func() {
+	rv := reflect.ValueOf(p)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return func() {}
+	}
+	saved := reflect.New(rv.Elem().Type()).Elem()
+	saved.Set(rv.Elem())
+	return func() { rv.Elem().Set(saved) }
+}
+
+// Snapshot captures the current values of the flags of the command and its descendants,
+// along with which of them have been set, so that they can be put back with Restore.
+func (c *Command) Snapshot() FlagState {
+	state := FlagState{actual: make(map[*Command]map[string]*Flag)}
+	var walk func(*Command)
+	walk = func(cmd *Command) {
+		for _, flag := range cmd.formal {
+			state.restores = append(state.restores, snapshotValue(flag.Value), snapshotPointer(&flag.origin))
+		}
+		state.actual[cmd] = maps.Clone(cmd.actual)
+		for _, child := range cmd.children {
+			walk(child)
+		}
+	}
+	walk(c)
+	return state
+}
+
+// Restore puts back the flag values, and the record of which flags were set, captured by Snapshot.
+// Flags defined after the snapshot was taken are left as they are.
+func (c *Command) Restore(state FlagState) {
+	for _, restore := range state.restores {
+		restore()
+	}
+	for cmd, actual := range state.actual {
+		cmd.actual = maps.Clone(actual)
+	}
+}
+
+func (b *bigValue[T, P]) snapshot() func() {
+	saved := P(new(T)).Set(b.p)
+	return func() { b.p.Set(saved) }
+}
+
+func (s *sliceValue[T]) snapshot() func() {
+	saved, changed := append([]T(nil), *s.p...), s.changed
+	return func() { *s.p, s.changed = saved, changed }
+}
+
+func (s *secretValue) snapshot() func() {
+	saved, source := *s.p, s.source
+	return func() { *s.p, s.source = saved, source }
+}
+
+func (t *templateValue) snapshot() func() {
+	saved, text := *t.p, t.text
+	return func() { *t.p, t.text = saved, text }
+}
+
+func (n *numberValue) snapshot() func()      { return snapshotValue(n.Getter) }
+func (j *jsonValue) snapshot() func()        { return snapshotPointer(j.p) }
+func (j *jsonIntoValue[T]) snapshot() func() { return snapshotPointer(j.p) }
+func (l *locationValue) snapshot() func()    { return snapshotPointer(l.p) }
+func (r *ratioValue) snapshot() func()       { return snapshotPointer(r.p) }
+func (s *signalValue) snapshot() func()      { return snapshotPointer(s.p) }
+func (v *pemFileValue) snapshot() func()     { return snapshotPointer(v.p) }
+func (o *outputValue) snapshot() func()      { return snapshotPointer(&o.p.Path) }
+func (i *inputValue) snapshot() func()       { return snapshotPointer(&i.p.Path) }
@@ -0,0 +1,42 @@
+package mandy
+
+import (
+	"math/big"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSnapshot(t *testing.T) {
+	var (
+		n     int
+		waits []time.Duration
+		count = new(big.Int)
+		child string
+	)
+	c := NewCommand("repl", ContinueOnError)
+	c.Int(&n, "n", 1, "", false)
+	c.DurationSlice(&waits, "wait", []time.Duration{time.Second}, "", false)
+	c.BigInt(count, "count", big.NewInt(10), "", false)
+	sub := c.NewChild("sub", "")
+	sub.String(&child, "name", "default", "", false)
+
+	state := c.Snapshot()
+	if err := c.Parse("--n", "5", "--wait", "2s", "--count", "12345678901234567890", "sub", "--name", "changed"); err != nil {
+		t.Fatal(err)
+	}
+	c.Restore(state)
+
+	if n != 1 || !slices.Equal(waits, []time.Duration{time.Second}) || count.Int64() != 10 || child != "default" {
+		t.Errorf("not restored: n=%d waits=%v count=%v name=%q", n, waits, count, child)
+	}
+	if c.NFlag() != 0 || sub.NFlag() != 0 {
+		t.Error("the record of set flags was not restored")
+	}
+	if err := c.Parse("--wait", "3s"); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(waits, []time.Duration{3 * time.Second}) {
+		t.Errorf("a restored slice should be replaced by its next Set, got %v", waits)
+	}
+}
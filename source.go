@@ -0,0 +1,67 @@
+package mandy
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrSource is returned when a flag is assigned from a source its Restrict call excluded
+var ErrSource = errors.New("mandy: flag may not be set from this source")
+
+// A Source is a place a flag's value can come from.
+// Sources are bits, so several can be combined into a set for Flag.Restrict.
+type Source uint8
+
+const (
+	SourceCommandLine Source = 1 << iota // the command's arguments
+	SourceEnv                            // an environment variable
+	SourceConfig                         // a configuration file
+	SourceProgram                        // a call to Command.Set
+
+	AnySource = SourceCommandLine | SourceEnv | SourceConfig | SourceProgram
+)
+
+var sourceNames = []struct {
+	src  Source
+	name string
+}{
+	{SourceCommandLine, "the command line"},
+	{SourceEnv, "the environment"},
+	{SourceConfig, "a config file"},
+	{SourceProgram, "the program"},
+}
+
+func (s Source) String() string {
+	var names []string
+	for _, n := range sourceNames {
+		if s&n.src != 0 {
+			names = append(names, n.name)
+		}
+	}
+	if len(names) == 0 {
+		return "nowhere"
+	}
+	return strings.Join(names, " or ")
+}
+
+// Restrict limits the sources the flag may be set from, so that, for example,
+// a password can be kept off the command line and out of ps(1).
+// Assignments from any other source fail with ErrSource.
+func (f *Flag) Restrict(allowed Source) *Flag {
+	f.sources = allowed
+	return f
+}
+
+// allows reports whether the flag may be set from src
+func (f *Flag) allows(src Source) bool {
+	return f.sources == 0 || f.sources&src != 0
+}
+
+// checkSource returns an error unless the flag may be set from src
+func (f *Flag) checkSource(src Source) error {
+	if f.allows(src) {
+		return nil
+	}
+	return fmt.Errorf("%w: --%s may not be set from %s, only from %s", ErrSource, f.Name, src, f.sources)
+}
@@ -0,0 +1,45 @@
+package mandy
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRestrict(t *testing.T) {
+	var password, user string
+	c := NewCommand("test", ContinueOnError)
+	pw := c.Secret(&password, "password", "", "the account's password", false).Restrict(SourceEnv | SourceConfig)
+	c.String(&user, "user", "", "the account's name", false)
+
+	if err := c.Parse("--password", "hunter2"); !errors.Is(err, ErrSource) {
+		t.Errorf("--password on the command line: err = %v, want ErrSource", err)
+	}
+	if err := c.Set("password", "hunter2"); !errors.Is(err, ErrSource) {
+		t.Errorf("Set(password): err = %v, want ErrSource", err)
+	}
+	if password != "" {
+		t.Errorf("password = %q after rejected assignments", password)
+	}
+	if err := c.setFrom(pw, "hunter2", Origin{Source: SourceEnv}); err != nil {
+		t.Errorf("password from the environment: %v", err)
+	}
+	if err := c.Parse("--user", "gopher"); err != nil {
+		t.Errorf("unrestricted flag: %v", err)
+	}
+}
+
+func TestSourceString(t *testing.T) {
+	tests := []struct {
+		src  Source
+		want string
+	}{
+		{0, "nowhere"},
+		{SourceEnv, "the environment"},
+		{SourceEnv | SourceConfig, "the environment or a config file"},
+	}
+	for _, test := range tests {
+		if got := test.src.String(); got != test.want {
+			t.Errorf("Source(%d).String() = %q, want %q", test.src, got, test.want)
+		}
+	}
+}
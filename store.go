@@ -0,0 +1,232 @@
+package mandy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// A Store persists small pieces of state, such as tokens cached from credential helpers, between runs
+type Store interface {
+	Get(key string) (string, error) // returns ErrNoCredential for missing keys
+	Set(key, value string) error
+	Delete(key string) error
+}
+
+// ErrNoKeychain is returned by NewStore when the operating system's keychain is unavailable
+// and a plaintext fallback was not allowed
+var ErrNoKeychain = errors.New("mandy: no keychain is available")
+
+// StateDir returns the directory in which the named application should keep its state:
+// $XDG_STATE_HOME/app, ~/.local/state/app on unix-likes, or the user config directory elsewhere.
+func StateDir(app string) (string, error) {
+	return stateDir(app, os.LookupEnv)
+}
+
+// stateDir is StateDir reading $XDG_STATE_HOME, and $HOME on unix-likes, with lookup
+func stateDir(app string, lookup func(string) (string, bool)) (string, error) {
+	if dir, _ := lookup("XDG_STATE_HOME"); dir != "" {
+		return filepath.Join(dir, app), nil
+	}
+	if runtime.GOOS != "windows" && runtime.GOOS != "darwin" && runtime.GOOS != "plan9" {
+		if home, _ := lookup("HOME"); home != "" {
+			return filepath.Join(home, ".local", "state", app), nil
+		}
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, ".local", "state", app), nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, app), nil
+}
+
+// NewStore returns a Store for the named application backed by the operating system's keychain.
+// If there is no keychain, a plaintext file in the app's StateDir is used instead,
+// but only if allowPlaintext is set; otherwise ErrNoKeychain is returned.
+func NewStore(app string, allowPlaintext bool) (Store, error) {
+	if ks, ok := newKeychainStore(app); ok {
+		return ks, nil
+	}
+	if !allowPlaintext {
+		return nil, ErrNoKeychain
+	}
+	dir, err := StateDir(app)
+	if err != nil {
+		return nil, err
+	}
+	return NewFileStore(filepath.Join(dir, "secrets.json")), nil
+}
+
+// keychainStore keeps entries in the keychain as passwords for the app's service
+// It uses the Credential Manager, in place of a tool, on windows.
+type keychainStore struct {
+	service string
+	tool    string
+}
+
+// newKeychainStore reports false if the platform's keychain tool is not installed
+func newKeychainStore(app string) (*keychainStore, bool) {
+	var tool string
+	switch runtime.GOOS {
+	case "darwin":
+		tool = "security"
+	case "linux", "freebsd", "openbsd", "netbsd":
+		tool = "secret-tool"
+	case "windows":
+		return &keychainStore{service: app}, true // the Credential Manager is part of the system
+	default:
+		return nil, false
+	}
+	if _, err := exec.LookPath(tool); err != nil {
+		return nil, false
+	}
+	return &keychainStore{service: app, tool: tool}, true
+}
+
+func (k *keychainStore) Get(key string) (string, error) {
+	return resolveKeyring(k.service + "/" + key)
+}
+
+func (k *keychainStore) Set(key, value string) error {
+	var cmd *exec.Cmd
+	switch k.tool {
+	case "":
+		return credWrite(k.service+"/"+key, key, value)
+	case "security":
+		// security reads commands from stdin in interactive mode, which keeps the value out of argv
+		line, err := securityCommand("add-generic-password", "-U", "-s", k.service, "-a", key, "-w", value)
+		if err != nil {
+			return err
+		}
+		cmd = exec.Command("security", "-i")
+		cmd.Stdin = strings.NewReader(line)
+	default:
+		cmd = exec.Command("secret-tool", "store", "--label="+k.service+" "+key, "service", k.service, "account", key)
+		cmd.Stdin = strings.NewReader(value)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %v: %s", k.tool, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func (k *keychainStore) Delete(key string) error {
+	var err error
+	switch k.tool {
+	case "":
+		err = credDelete(k.service + "/" + key)
+	case "security":
+		_, err = runCredentialHelper("security", "delete-generic-password", "-s", k.service, "-a", key)
+	default:
+		_, err = runCredentialHelper("secret-tool", "clear", "service", k.service, "account", key)
+	}
+	return err
+}
+
+// securityCommand formats a line for security's interactive mode, which splits words on spaces
+// and reads double quoted words with backslash escapes, but can't carry line breaks
+func securityCommand(words ...string) (string, error) {
+	var b strings.Builder
+	for i, w := range words {
+		if strings.ContainsAny(w, "\r\n\x00") {
+			return "", fmt.Errorf("%w: keychain entries can't contain line breaks or NUL", errParse)
+		}
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteByte('"')
+		b.WriteString(strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(w))
+		b.WriteByte('"')
+	}
+	b.WriteByte('\n')
+	return b.String(), nil
+}
+
+// FileStore keeps entries, unencrypted, in a json file readable only by its owner
+type FileStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileStore returns a plaintext Store kept at path, which is created when first written to
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// load reads the store's entries, treating a missing file as an empty store
+func (f *FileStore) load() (map[string]string, error) {
+	entries := make(map[string]string)
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return entries, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("%s: %w", f.path, err)
+	}
+	return entries, nil
+}
+
+func (f *FileStore) save(entries map[string]string) error {
+	data, err := json.MarshalIndent(entries, "", "\t")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o700); err != nil {
+		return err
+	}
+	tmp := f.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.path)
+}
+
+func (f *FileStore) Get(key string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	entries, err := f.load()
+	if err != nil {
+		return "", err
+	}
+	v, ok := entries[key]
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrNoCredential, key)
+	}
+	return v, nil
+}
+
+func (f *FileStore) Set(key, value string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	entries, err := f.load()
+	if err != nil {
+		return err
+	}
+	entries[key] = value
+	return f.save(entries)
+}
+
+func (f *FileStore) Delete(key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	entries, err := f.load()
+	if err != nil {
+		return err
+	}
+	delete(entries, key)
+	return f.save(entries)
+}
@@ -0,0 +1,54 @@
+package mandy
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "secrets.json")
+	s := NewFileStore(path)
+	if _, err := s.Get("token"); !errors.Is(err, ErrNoCredential) {
+		t.Errorf("expected ErrNoCredential, got %v", err)
+	}
+	if err := s.Set("token", "hunter2"); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := NewFileStore(path).Get("token"); err != nil || v != "hunter2" {
+		t.Errorf("got %q, %v", v, err)
+	}
+	if info, err := os.Stat(path); err != nil {
+		t.Fatal(err)
+	} else if runtime.GOOS != "windows" && info.Mode().Perm() != 0o600 {
+		t.Errorf("store is readable by others: %v", info.Mode())
+	}
+	if err := s.Delete("token"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Get("token"); !errors.Is(err, ErrNoCredential) {
+		t.Errorf("expected ErrNoCredential after Delete, got %v", err)
+	}
+}
+
+func TestStateDir(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", "/tmp/state")
+	if dir, err := StateDir("tool"); err != nil || dir != filepath.Join("/tmp/state", "tool") {
+		t.Errorf("got %q, %v", dir, err)
+	}
+}
+
+func TestSecurityCommand(t *testing.T) {
+	got, err := securityCommand("add-generic-password", "-s", "my tool", "-w", `it's "a\b"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `"add-generic-password" "-s" "my tool" "-w" "it's \"a\\b\""` + "\n"; got != want {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+	if _, err := securityCommand("-w", "two\nlines"); err == nil {
+		t.Error("expected an error for a value with a line break")
+	}
+}
@@ -0,0 +1,47 @@
+package mandy
+
+import (
+	"fmt"
+	"text/template"
+)
+
+// -- text/template Value
+// arguments are compiled at Set time
+type templateValue struct {
+	p    **template.Template
+	name string
+	text string
+}
+
+func newTemplateValue(name, val string, p **template.Template) *templateValue {
+	t := &templateValue{p: p, name: name}
+	if err := t.Set(val); err != nil {
+		panic(fmt.Sprintf("flag %q has an invalid default template: %v", name, err))
+	}
+	return t
+}
+
+func (t *templateValue) Set(s string) error {
+	tmpl, err := template.New(t.name).Parse(s)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errParse, err)
+	}
+	*t.p, t.text = tmpl, s
+	return nil
+}
+
+func (t *templateValue) Get() any {
+	if t.p == nil {
+		return (*template.Template)(nil)
+	}
+	return *t.p
+}
+func (t *templateValue) String() string { return t.text }
+func (t *templateValue) IsBool() bool   { return false }
+
+// Template defines a text/template flag with specified name, default value, and usage string.
+// The argument p points to a *template.Template variable in which to store the compiled value of the flag.
+// The default is compiled immediately, and Template panics if it is invalid.
+func (c *Command) Template(p **template.Template, name string, value string, usage string, short bool) *Flag {
+	return c.Var(newTemplateValue(name, value, p), name, usage, short)
+}
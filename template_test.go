@@ -0,0 +1,31 @@
+package mandy
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestTemplate(t *testing.T) {
+	var tmpl *template.Template
+	c := NewCommand("test", ContinueOnError)
+	f := c.Template(&tmpl, "format", "{{.Name}}", "output format", false)
+	if err := c.Parse("--format", "{{.Name}}\t{{.Size}}"); err != nil {
+		t.Fatal(err)
+	}
+	var buf strings.Builder
+	f.Value.Get().(*template.Template).Execute(&buf, struct {
+		Name string
+		Size int
+	}{"a.txt", 3})
+	if buf.String() != "a.txt\t3" {
+		t.Errorf("got %q", buf.String())
+	}
+	if f.DefValue != "{{.Name}}" {
+		t.Errorf("DefValue = %q", f.DefValue)
+	}
+	if err := c.Set("format", "{{.Name"); !errors.Is(err, errParse) {
+		t.Errorf("expected a parse error, got %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+package mandy
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// An ExitError is returned by Execute, under js, when the command would have exited
+// without an exit function set by SetExit
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string { return fmt.Sprintf("mandy: exit status %d", e.Code) }
+
+// catchExit stores an *ExitError that unwound the stack in *err, and lets other panics continue
+func catchExit(err *error) {
+	if r := recover(); r != nil {
+		e, ok := r.(*ExitError)
+		if !ok {
+			panic(r)
+		}
+		*err = e
+	}
+}
+
+// interactive reports whether r is a terminal, from which input should not be awaited.
+// Where files can't be inspected, as under js, they are assumed to be terminals.
+func interactive(r io.Reader) bool {
+	f, ok := r.(*os.File)
+	if !ok {
+		return false
+	}
+	stat, err := f.Stat()
+	if err != nil {
+		return !canStat
+	}
+	return stat.Mode()&os.ModeCharDevice != 0
+}
+
+// pending reports whether r is a file with unread contents
+// It reports false if that can't be determined.
+func pending(r io.Reader) bool {
+	f, ok := r.(*os.File)
+	if !ok {
+		return false
+	}
+	stat, err := f.Stat()
+	return err == nil && stat.Size() > 0
+}
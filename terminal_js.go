@@ -0,0 +1,11 @@
+//go:build js
+
+package mandy
+
+// canStat is false because the js file system shim can't be relied on to describe the standard files
+const canStat = false
+
+// defaultExit unwinds to Execute, which returns an *ExitError, because os.Exit would stop
+// the whole wasm instance, not just the command.
+// Pass a function to SetExit to react to exits otherwise, for instance by resetting a playground.
+func defaultExit(code int) { panic(&ExitError{Code: code}) }
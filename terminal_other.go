@@ -0,0 +1,11 @@
+//go:build !js
+
+package mandy
+
+import "os"
+
+// canStat is true because files can be inspected on this platform
+const canStat = true
+
+// defaultExit is the exit function of commands for which SetExit hasn't been called
+func defaultExit(code int) { os.Exit(code) }
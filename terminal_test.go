@@ -0,0 +1,58 @@
+package mandy
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPending(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "input")
+	if err := os.WriteFile(path, []byte("data\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if !pending(f) || interactive(f) {
+		t.Errorf("regular file: pending = %v, interactive = %v, want true, false", pending(f), interactive(f))
+	}
+	if r := strings.NewReader("data"); pending(r) || interactive(r) {
+		t.Error("readers other than files should be neither pending nor interactive")
+	}
+
+	c := NewCommand("test", ContinueOnError)
+	c.SetInput(f)
+	if !c.Receiving() {
+		t.Error("Receiving = false with a non-empty file as input")
+	}
+}
+
+func TestExitError(t *testing.T) {
+	c := NewCommand("test", ContinueOnError)
+	c.SetOutput(io.Discard)
+	c.SetExit(defaultExitUnwinding)
+	ran := false
+	c.Main = func(c *Command) error {
+		c.Exit("fatal", 3)
+		ran = true
+		return nil
+	}
+
+	var e *ExitError
+	if err := c.Execute("--"); !errors.As(err, &e) || e.Code != 3 {
+		t.Fatalf("Execute = %v, want exit status 3", err)
+	}
+	if ran {
+		t.Error("Main continued after exiting")
+	}
+}
+
+// defaultExitUnwinding is the default exit function under js
+func defaultExitUnwinding(code int) { panic(&ExitError{Code: code}) }
@@ -0,0 +1,178 @@
+package mandy
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A TimeWindow is a daily span of wall-clock time, optionally restricted to some weekdays.
+// Windows whose End precedes their Start run past midnight.
+type TimeWindow struct {
+	Days  Weekdays      // the days the window opens on; none means every day
+	Start time.Duration // offset from midnight at which the window opens
+	End   time.Duration // offset from midnight at which the window closes
+}
+
+// Weekdays is a set of days of the week, with bit i set for time.Weekday(i)
+type Weekdays uint8
+
+// Has reports whether the set includes day
+func (w Weekdays) Has(day time.Weekday) bool {
+	return w == 0 || w&(1<<day) != 0
+}
+
+var weekdayNames = [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}
+
+// ParseTimeWindow parses windows such as "09:00-17:00", "Mon-Fri 9-17", or "Sat,Sun 10:30-14".
+// Days may be given as comma separated names or ranges, starting on Monday; hours as "15" or "15:04".
+func ParseTimeWindow(s string) (w TimeWindow, err error) {
+	fields := strings.Fields(s)
+	switch len(fields) {
+	case 1:
+	case 2:
+		if w.Days, err = parseWeekdays(fields[0]); err != nil {
+			return TimeWindow{}, err
+		}
+	default:
+		return TimeWindow{}, fmt.Errorf("%w: time window %q should look like \"Mon-Fri 09:00-17:00\"", errParse, s)
+	}
+	start, end, ok := strings.Cut(fields[len(fields)-1], "-")
+	if !ok {
+		return TimeWindow{}, fmt.Errorf("%w: time window %q has no end", errParse, s)
+	}
+	if w.Start, err = parseClock(start); err != nil {
+		return TimeWindow{}, err
+	}
+	if w.End, err = parseClock(end); err != nil {
+		return TimeWindow{}, err
+	}
+	return w, nil
+}
+
+// parseClock parses "15" or "15:04" as an offset from midnight, allowing "24:00"
+func parseClock(s string) (time.Duration, error) {
+	hh, mm, hasMinutes := strings.Cut(s, ":")
+	h, err := strconv.Atoi(hh)
+	if err != nil || h < 0 || h > 24 {
+		return 0, fmt.Errorf("%w: invalid hour in %q", errParse, s)
+	}
+	m := 0
+	if hasMinutes {
+		if m, err = strconv.Atoi(mm); err != nil || len(mm) != 2 || m < 0 || m > 59 {
+			return 0, fmt.Errorf("%w: invalid minute in %q", errParse, s)
+		}
+	}
+	if h == 24 && m != 0 {
+		return 0, fmt.Errorf("%w: %q is past midnight", errRange, s)
+	}
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute, nil
+}
+
+// parseWeekdays parses lists of days and ranges like "Mon-Wed,Fri"
+func parseWeekdays(s string) (out Weekdays, err error) {
+	for _, part := range strings.Split(s, ",") {
+		first, last, isRange := strings.Cut(part, "-")
+		from, err := parseWeekday(first)
+		if err != nil {
+			return 0, err
+		}
+		to := from
+		if isRange {
+			if to, err = parseWeekday(last); err != nil {
+				return 0, err
+			}
+		}
+		for day := from; ; day = (day + 1) % 7 {
+			out |= 1 << day
+			if day == to {
+				break
+			}
+		}
+	}
+	return out, nil
+}
+
+func parseWeekday(s string) (time.Weekday, error) {
+	for i := range weekdayNames {
+		if len(s) >= 3 && strings.HasPrefix(strings.ToLower(time.Weekday(i).String()), strings.ToLower(s)) {
+			return time.Weekday(i), nil
+		}
+	}
+	return 0, fmt.Errorf("%w: unknown weekday %q", errParse, s)
+}
+
+// Contains reports whether t falls within the window
+func (w TimeWindow) Contains(t time.Time) bool {
+	offset := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+	if w.Start <= w.End {
+		return w.Days.Has(t.Weekday()) && w.Start <= offset && offset < w.End
+	}
+	// the window runs past midnight, so the early hours belong to the previous day's window
+	if offset >= w.Start {
+		return w.Days.Has(t.Weekday())
+	}
+	return offset < w.End && w.Days.Has((t.Weekday()+6)%7)
+}
+
+// Within reports whether the window contains the current time on the command's clock, see SetClock
+func (c *Command) Within(w TimeWindow) bool {
+	return w.Contains(c.Now())
+}
+
+// String formats the window in its normal form, eg "Mon-Fri 09:00-17:00"
+func (w TimeWindow) String() string {
+	span := formatClock(w.Start) + "-" + formatClock(w.End)
+	if w.Days == 0 || w.Days == 1<<7-1 {
+		return span
+	}
+	return w.Days.String() + " " + span
+}
+
+// String lists the days, starting on Monday, collapsing consecutive days into ranges
+func (w Weekdays) String() string {
+	var parts []string
+	for i := 0; i < 7; i++ {
+		day := time.Weekday((i + 1) % 7)
+		if w&(1<<day) == 0 {
+			continue
+		}
+		j := i
+		for j+1 < 7 && w&(1<<((j+2)%7)) != 0 {
+			j++
+		}
+		if j == i {
+			parts = append(parts, weekdayNames[day])
+		} else {
+			parts = append(parts, weekdayNames[day]+"-"+weekdayNames[(j+1)%7])
+		}
+		i = j
+	}
+	return strings.Join(parts, ",")
+}
+
+func formatClock(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d/time.Hour), int(d%time.Hour/time.Minute))
+}
+
+// -- TimeWindow Value
+type timeWindowValue TimeWindow
+
+func newTimeWindowValue(val TimeWindow, p *TimeWindow) *timeWindowValue {
+	*p = val
+	return (*timeWindowValue)(p)
+}
+
+func (w *timeWindowValue) Set(s string) error {
+	v, err := ParseTimeWindow(s)
+	if err != nil {
+		return err
+	}
+	*w = timeWindowValue(v)
+	return nil
+}
+
+func (w *timeWindowValue) Get() any       { return TimeWindow(*w) }
+func (w *timeWindowValue) String() string { return TimeWindow(*w).String() }
+func (w *timeWindowValue) IsBool() bool   { return false }
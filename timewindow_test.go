@@ -0,0 +1,58 @@
+package mandy
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseTimeWindow(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"09:00-17:00", "09:00-17:00"},
+		{"Mon-Fri 9-17", "Mon-Fri 09:00-17:00"},
+		{"sat,sunday 10:30-14", "Sat-Sun 10:30-14:00"},
+		{"Mon,Wed,Fri 22-06", "Mon,Wed,Fri 22:00-06:00"},
+		{"Mon-Sun 0-24", "00:00-24:00"},
+	}
+	for _, test := range tests {
+		w, err := ParseTimeWindow(test.in)
+		if err != nil {
+			t.Errorf("ParseTimeWindow(%q): %v", test.in, err)
+			continue
+		}
+		if got := w.String(); got != test.want {
+			t.Errorf("ParseTimeWindow(%q) = %q, want %q", test.in, got, test.want)
+		}
+	}
+	for _, bad := range []string{"", "9", "25-26", "9:5-10", "Funday 9-17", "Mon Tue 9-17", "24:30-1"} {
+		if _, err := ParseTimeWindow(bad); err == nil {
+			t.Errorf("ParseTimeWindow(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestTimeWindowContains(t *testing.T) {
+	night, _ := ParseTimeWindow("Fri 22-06")
+	friday := time.Date(2024, 5, 3, 23, 0, 0, 0, time.UTC)
+	if !night.Contains(friday) || !night.Contains(friday.Add(4*time.Hour)) {
+		t.Error("overnight window should span friday night")
+	}
+	if night.Contains(friday.Add(-24 * time.Hour)) {
+		t.Error("window should not open on thursday")
+	}
+}
+
+func TestDurationSlice(t *testing.T) {
+	var got []time.Duration
+	c := NewCommand("test", ContinueOnError)
+	c.DurationSlice(&got, "backoff", []time.Duration{time.Second}, "retry delays", false)
+	if err := c.Parse("--backoff=1s,2m", "--backoff", "1h"); err != nil {
+		t.Fatal(err)
+	}
+	if want := []time.Duration{time.Second, 2 * time.Minute, time.Hour}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if err := c.Set("backoff", "soon"); err == nil {
+		t.Error("expected a parse error")
+	}
+}
@@ -0,0 +1,39 @@
+//go:build tinygo
+
+// Under TinyGo, whose reflection support is partial, mandy swaps its reflection-heavy paths for
+// these reduced ones. Flag parsing, usage messages, and the value types work as usual, but:
+//   - Flag.Eq compares the flag's value and arg as text, so Eq(1) holds for an Int64 flag set to 1
+//   - usage messages treat "", "0", "0s", "false", and "[]" as the zero defaults DefaultNonZero omits
+//   - Commands and AddCommands, which bind struct methods and fields, panic with errors.ErrUnsupported
+
+package mandy
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Eq checks if a flag has a given value, by comparing their text
+func (f *Flag) Eq(arg any) bool {
+	return f.Value.String() == fmt.Sprint(arg)
+}
+
+// isZeroValue determines whether the string represents the zero
+// value for a flag, judging by the usual renderings of zero values
+func isZeroValue(flag *Flag, value string) bool {
+	switch value {
+	case "", "0", "0b0", "0o0", "0x0", "0s", "false", "[]":
+		return true
+	}
+	return false
+}
+
+// Commands is unsupported under TinyGo, and panics
+func Commands(receiver any) *Command {
+	panic(fmt.Errorf("mandy.Commands: %w under tinygo", errors.ErrUnsupported))
+}
+
+// AddCommands is unsupported under TinyGo, and panics
+func (c *Command) AddCommands(receiver any) []*Command {
+	panic(c.sprintf("AddCommands: %v under tinygo", errors.ErrUnsupported))
+}
@@ -0,0 +1,171 @@
+package mandy
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// TLS is a FlagGroup of TLS settings: --cert, --key, --ca, --server-name, and --min-version,
+// conventionally mounted under the "tls" prefix. Files are checked when the flags are parsed,
+// as is that the certificate and key are given together and match.
+type TLS struct {
+	Cert       string // path to a PEM encoded certificate chain
+	Key        string // path to the PEM encoded private key of Cert
+	CA         string // path to PEM encoded certificate authorities to trust, instead of the system's
+	ServerName string // name to verify the peer's certificate against
+	MinVersion uint16 // one of the tls.VersionTLS constants, tls.VersionTLS12 if zero
+}
+
+// Register defines the group's flags on c
+func (t *TLS) Register(c *Command, prefix string) {
+	cert, key := Prefixed(prefix, "cert"), Prefixed(prefix, "key")
+	c.Var(newPEMFileValue(t.Cert, &t.Cert, "CERTIFICATE"), cert, "PEM encoded certificate chain `file`", false)
+	c.Var(newPEMFileValue(t.Key, &t.Key, "PRIVATE KEY"), key, "PEM encoded private key `file` of the certificate", false)
+	c.Var(newPEMFileValue(t.CA, &t.CA, "CERTIFICATE"), Prefixed(prefix, "ca"), "PEM encoded certificate authorities `file` to trust", false)
+	c.String(&t.ServerName, Prefixed(prefix, "server-name"), t.ServerName, "`name` to verify the peer's certificate against", false)
+	if t.MinVersion == 0 {
+		t.MinVersion = tls.VersionTLS12
+	}
+	c.Var(newTLSVersionValue(t.MinVersion, &t.MinVersion), Prefixed(prefix, "min-version"), "lowest TLS `version` to negotiate", false)
+	c.validate(func(cmd *Command) error {
+		if _, err := loadKeyPair(cmd.formal[cert].Value.String(), cmd.formal[key].Value.String()); err != nil {
+			return fmt.Errorf("--%s and --%s: %w", cert, key, err)
+		}
+		return nil
+	})
+}
+
+// loadKeyPair loads the certificate and key at the given paths, or nothing if neither is given
+func loadKeyPair(cert, key string) ([]tls.Certificate, error) {
+	if (cert == "") != (key == "") {
+		return nil, fmt.Errorf("%w: a TLS certificate and key must be given together", errParse)
+	}
+	if cert == "" {
+		return nil, nil
+	}
+	pair, err := tls.LoadX509KeyPair(cert, key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errParse, err)
+	}
+	return []tls.Certificate{pair}, nil
+}
+
+// TLSConfig returns a *tls.Config configured by the group's settings.
+// The CA pool is used to verify servers and, if the caller sets ClientAuth, clients.
+func (t *TLS) TLSConfig() (*tls.Config, error) {
+	config := &tls.Config{ServerName: t.ServerName, MinVersion: t.MinVersion}
+	certs, err := loadKeyPair(t.Cert, t.Key)
+	if err != nil {
+		return nil, err
+	}
+	config.Certificates = certs
+	if t.CA != "" {
+		data, err := os.ReadFile(t.CA)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("%w: no certificates found in %s", errParse, t.CA)
+		}
+		config.RootCAs, config.ClientCAs = pool, pool
+	}
+	return config, nil
+}
+
+// -- PEM file Value
+// holds the path to a file containing at least one PEM block whose type ends in kind
+type pemFileValue struct {
+	p    *string
+	kind string
+}
+
+func newPEMFileValue(val string, p *string, kind string) *pemFileValue {
+	*p = val
+	return &pemFileValue{p: p, kind: kind}
+}
+
+func (v *pemFileValue) Set(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	for {
+		var block *pem.Block
+		if block, data = pem.Decode(data); block == nil {
+			return fmt.Errorf("%w: %s holds no PEM encoded %s", errParse, path, strings.ToLower(v.kind))
+		}
+		if strings.HasSuffix(block.Type, v.kind) {
+			*v.p = path
+			return nil
+		}
+	}
+}
+
+func (v *pemFileValue) Get() any { return *v.p }
+
+func (v *pemFileValue) String() string {
+	if v.p == nil {
+		return ""
+	}
+	return *v.p
+}
+
+func (v *pemFileValue) IsBool() bool { return false }
+
+func (v *pemFileValue) clone() Getter {
+	p := *v.p
+	return &pemFileValue{p: &p, kind: v.kind}
+}
+
+// tlsVersions maps the names accepted by TLS version flags to their values
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// -- TLS version Value
+type tlsVersionValue uint16
+
+func newTLSVersionValue(val uint16, p *uint16) *tlsVersionValue {
+	*p = val
+	return (*tlsVersionValue)(p)
+}
+
+func (v *tlsVersionValue) Set(s string) error {
+	version, ok := tlsVersions[strings.TrimPrefix(strings.ToLower(s), "tls")]
+	if !ok {
+		return fmt.Errorf("%w: unknown TLS version %q, expected one of 1.0, 1.1, 1.2, or 1.3", errParse, s)
+	}
+	*v = tlsVersionValue(version)
+	return nil
+}
+
+func (v *tlsVersionValue) Get() any { return uint16(*v) }
+
+func (v *tlsVersionValue) String() string {
+	for name, version := range tlsVersions {
+		if version == uint16(*v) {
+			return name
+		}
+	}
+	return ""
+}
+
+func (v *tlsVersionValue) IsBool() bool { return false }
+
+// Complete suggests the TLS versions
+func (v *tlsVersionValue) Complete(prefix string) (out []string) {
+	for _, name := range []string{"1.0", "1.1", "1.2", "1.3"} {
+		if strings.HasPrefix(name, prefix) {
+			out = append(out, name)
+		}
+	}
+	return
+}
@@ -0,0 +1,110 @@
+package mandy
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeTestCert writes a self-signed certificate and its key to dir
+func writeTestCert(t *testing.T, dir string) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "mandy.test"},
+		DNSNames:              []string{"mandy.test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile, keyFile = filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600)
+	os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600)
+	return
+}
+
+func TestTLS(t *testing.T) {
+	dir := t.TempDir()
+	cert, key := writeTestCert(t, dir)
+
+	var settings TLS
+	c := NewCommand("serve", ContinueOnError)
+	if err := c.Mount("tls", &settings); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Lookup("tls-min-version").DefValue; got != "1.2" {
+		t.Errorf("default min version: %q", got)
+	}
+	if err := c.Parse("--tls-cert", cert, "--tls-key", key, "--tls-ca", cert, "--tls-server-name", "mandy.test", "--tls-min-version", "1.3"); err != nil {
+		t.Fatal(err)
+	}
+	config, err := settings.TLSConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Certificates) != 1 || config.RootCAs == nil || config.ServerName != "mandy.test" || config.MinVersion != tls.VersionTLS13 {
+		t.Errorf("unexpected config: %+v", config)
+	}
+
+	for flag, arg := range map[string]string{
+		"tls-cert":        key,
+		"tls-key":         cert,
+		"tls-ca":          filepath.Join(dir, "missing.pem"),
+		"tls-min-version": "1.4",
+	} {
+		if err := c.Set(flag, arg); err == nil {
+			t.Errorf("--%s=%s should be rejected", flag, arg)
+		}
+	}
+
+	if _, err := (&TLS{Cert: cert}).TLSConfig(); err == nil {
+		t.Error("a certificate without a key should be rejected")
+	}
+}
+
+func TestTLSPair(t *testing.T) {
+	cert, key := writeTestCert(t, t.TempDir())
+	_, other := writeTestCert(t, t.TempDir())
+	c := NewCommand("serve", ContinueOnError)
+	c.SetOutput(io.Discard)
+	if err := c.Mount("tls", new(TLS)); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		args []string
+		ok   bool
+	}{
+		{nil, true},
+		{[]string{"--tls-cert", cert, "--tls-key", key}, true},
+		{[]string{"--tls-cert", cert}, false},
+		{[]string{"--tls-key", key}, false},
+		{[]string{"--tls-cert", cert, "--tls-key", other}, false},
+	} {
+		if err := c.Clone().Parse(append([]string{"--tls-server-name", "mandy.test"}, tc.args...)...); (err == nil) != tc.ok {
+			t.Errorf("%q: got %v, want success %t", tc.args, err, tc.ok)
+		}
+	}
+}
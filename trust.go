@@ -0,0 +1,181 @@
+package mandy
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// TrustFile is the file, in the StateDir named after the command, recording the hashes of trusted config files
+const TrustFile = "trusted.json"
+
+// A TrustPrompt reports whether the project-local config file at path should be loaded. It is asked
+// when the file is new or, if changed is set, when its contents differ from those last trusted.
+type TrustPrompt func(c *Command, path string, changed bool) (bool, error)
+
+// RequireTrust makes DiscoverConfig load project-local config files, and the EnvFile, only once they are
+// trusted, so that a repository can't silently set flags, much as with direnv. The hash of each trusted
+// file's contents is recorded in TrustFile; prompt, or AskTrust if it is nil, decides whether to trust files
+// that are new or have changed since. Untrusted files are skipped with a warning.
+//
+// Without RequireTrust, such files are loaded whether or not they're trusted, but the values of those that
+// aren't, by Trust or an earlier prompt, are taken as they are: credential references and FromFile paths
+// in them aren't followed, lest a cloned repository read the user's secrets.
+func (c *Command) RequireTrust(prompt TrustPrompt) *Command {
+	if prompt == nil {
+		prompt = AskTrust
+	}
+	c.trustPrompt = prompt
+	return c
+}
+
+// AskTrust is the default TrustPrompt: it asks for a yes or no on the command's Output, and reads the answer
+// from its Input. Files are not trusted, and nothing is asked, if the input is not a terminal.
+func AskTrust(c *Command, path string, changed bool) (bool, error) {
+	state := "new"
+	if changed {
+		state = "changed"
+	}
+	return c.confirm(fmt.Sprintf("%s: %s config file %s; trust it?", c.name, state, path))
+}
+
+// confirm asks the question on the command's Output and reports whether the answer, read from its Input,
+// is yes. Nothing is asked, and false reported, if the input is not a terminal.
+func (c *Command) confirm(question string) (bool, error) {
+	if !interactive(c.Input()) {
+		return false, nil
+	}
+	fmt.Fprintf(c.Output(), "%s [y/N] ", question)
+	answer, err := bufio.NewReader(c.Input()).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, err
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
+
+// Trust records the current contents of the config file at path as trusted, without prompting
+func (c *Command) Trust(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return c.recordTrust(path, data)
+}
+
+// loadTrusted loads the project-local config file at path if it exists and, should RequireTrust have been called,
+// is trusted; otherwise, untrusted files are loaded, but marked so that references in their values aren't followed
+func (c *Command) loadTrusted(path string, format ConfigFormat) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	ok, err := c.trusted(path, data)
+	if err != nil {
+		return err
+	}
+	if !ok && c.trustPrompt != nil {
+		fmt.Fprintf(c.Output(), "%s: ignoring untrusted config file %s\n", c.name, path)
+		return nil
+	}
+	if ok {
+		delete(c.untrusted, path)
+	} else {
+		if c.untrusted == nil {
+			c.untrusted = make(map[string]bool)
+		}
+		c.untrusted[path] = true
+	}
+	return c.loadConfigData(path, format, data)
+}
+
+// trusted reports whether data, read from path, is trusted, prompting for and recording trust if it is not yet
+// and RequireTrust has been called. Without it, files are untrusted if the TrustFile can't be read.
+func (c *Command) trusted(path string, data []byte) (bool, error) {
+	hashes, file, err := c.trustHashes()
+	if err != nil {
+		if c.trustPrompt == nil {
+			return false, nil
+		}
+		return false, err
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false, err
+	}
+	prev, known := hashes[abs]
+	if known && prev == hashOf(data) {
+		return true, nil
+	}
+	if c.trustPrompt == nil {
+		return false, nil
+	}
+	ok, err := c.trustPrompt(c, path, known)
+	if err != nil || !ok {
+		return false, err
+	}
+	hashes[abs] = hashOf(data)
+	return true, writeTrust(file, hashes)
+}
+
+// recordTrust records data, the contents of the file at path, as trusted
+func (c *Command) recordTrust(path string, data []byte) error {
+	hashes, file, err := c.trustHashes()
+	if err != nil {
+		return err
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	hashes[abs] = hashOf(data)
+	return writeTrust(file, hashes)
+}
+
+// trustHashes reads the hashes of trusted files, by absolute path, from the command's TrustFile,
+// and returns them with the file's path
+func (c *Command) trustHashes() (map[string]string, string, error) {
+	dir, err := stateDir(c.name, c.lookupEnv)
+	if err != nil {
+		return nil, "", err
+	}
+	file := filepath.Join(dir, TrustFile)
+	hashes := make(map[string]string)
+	data, err := os.ReadFile(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return hashes, file, nil
+	} else if err != nil {
+		return nil, "", err
+	}
+	if err := json.Unmarshal(data, &hashes); err != nil {
+		return nil, "", fmt.Errorf("%s: %w", file, err)
+	}
+	return hashes, file, nil
+}
+
+// writeTrust replaces the TrustFile at file with the given hashes
+func writeTrust(file string, hashes map[string]string) error {
+	data, err := json.MarshalIndent(hashes, "", "\t")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(file, append(data, '\n'), 0o600)
+}
+
+func hashOf(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
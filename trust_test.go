@@ -0,0 +1,149 @@
+package mandy
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRequireTrust(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", filepath.Join(root, "state"))
+	project := filepath.Join(root, "project")
+	if err := os.Mkdir(project, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	start, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(project); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(start)
+	defer func(dir string) { systemConfigDir = dir }(systemConfigDir)
+	systemConfigDir = filepath.Join(root, "etc")
+	write := func(name, data string) {
+		t.Helper()
+		if err := os.WriteFile(name, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(".toolrc", `{"name": "project"}`)
+	write(".env", "TOOL_PORT=81\n")
+
+	type ask struct {
+		path    string
+		changed bool
+	}
+	var (
+		asked  []ask
+		answer bool
+		out    bytes.Buffer
+	)
+	discover := func() (name string, port int) {
+		t.Helper()
+		c := NewCommand("tool", ContinueOnError)
+		c.SetOutput(&out)
+		c.SetEnviron(MapEnviron(map[string]string{
+			"XDG_CONFIG_HOME":  filepath.Join(root, "config"),
+			"XDG_STATE_HOME":   filepath.Join(root, "state"),
+			"MANDY_TEST_TOKEN": "hunter2",
+		}))
+		c.EnvFile = ".env"
+		c.RequireTrust(func(c *Command, path string, changed bool) (bool, error) {
+			asked = append(asked, ask{path, changed})
+			return answer, nil
+		})
+		c.String(&name, "name", "default", "", false)
+		c.Int(&port, "port", 80, "", false).Env("TOOL_PORT")
+		if err := c.DiscoverConfig(); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.Parse("--"); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	answer = true
+	if name, port := discover(); name != "project" || port != 81 || len(asked) != 2 {
+		t.Errorf("trusting new files: name, port = %q, %d after %d prompts, want project and 81 after 2", name, port, len(asked))
+	}
+	asked = nil
+	if name, port := discover(); name != "project" || port != 81 || len(asked) != 0 {
+		t.Errorf("rediscovering trusted files: name, port = %q, %d after %d prompts, want project and 81 after none", name, port, len(asked))
+	}
+
+	write(".toolrc", `{"name": "changed"}`)
+	answer = false
+	if name, port := discover(); name != "default" || port != 81 || len(asked) != 1 || asked[0] != (ask{".toolrc", true}) {
+		t.Errorf("declining a changed file: name, port = %q, %d after prompts %v, want default and 81 after one for a changed .toolrc", name, port, asked)
+	}
+	if !strings.Contains(out.String(), "ignoring untrusted config file .toolrc") {
+		t.Errorf("declining a file warned %q", out.String())
+	}
+
+	c := NewCommand("tool", ContinueOnError)
+	if err := c.Trust(".toolrc"); err != nil {
+		t.Fatal(err)
+	}
+	asked = nil
+	if name, _ := discover(); name != "changed" || len(asked) != 0 {
+		t.Errorf("after Trust: name = %q after %d prompts, want changed after none", name, len(asked))
+	}
+
+	c.SetInput(strings.NewReader("y\n"))
+	if ok, err := AskTrust(c, ".toolrc", false); ok || err != nil {
+		t.Errorf("AskTrust without a terminal = %t, %v, want false", ok, err)
+	}
+}
+
+func TestUntrustedConfig(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", filepath.Join(root, "state"))
+	t.Setenv("MANDY_TEST_TOKEN", "hunter2")
+	start, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(root); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(start)
+	defer func(dir string) { systemConfigDir = dir }(systemConfigDir)
+	systemConfigDir = filepath.Join(root, "etc")
+	if err := os.WriteFile(".toolrc", []byte(`{"token": "env:MANDY_TEST_TOKEN"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	discover := func() (string, Origin) {
+		t.Helper()
+		var token string
+		c := NewCommand("tool", ContinueOnError)
+		c.SetEnviron(MapEnviron(map[string]string{
+			"XDG_CONFIG_HOME":  filepath.Join(root, "config"),
+			"XDG_STATE_HOME":   filepath.Join(root, "state"),
+			"MANDY_TEST_TOKEN": "hunter2",
+		}))
+		c.Secret(&token, "token", "", "", false)
+		if err := c.DiscoverConfig(); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.Parse("--"); err != nil {
+			t.Fatal(err)
+		}
+		return token, c.Lookup("token").Source()
+	}
+	if token, origin := discover(); token != "env:MANDY_TEST_TOKEN" || !origin.Untrusted {
+		t.Errorf("untrusted file gave %q from %+v, want the reference unresolved", token, origin)
+	}
+	if err := NewCommand("tool", ContinueOnError).Trust(".toolrc"); err != nil {
+		t.Fatal(err)
+	}
+	if token, origin := discover(); token != "hunter2" || origin.Untrusted {
+		t.Errorf("trusted file gave %q from %+v, want hunter2", token, origin)
+	}
+}
@@ -0,0 +1,85 @@
+package mandy
+
+import (
+	"log/slog"
+	"os"
+	"time"
+)
+
+// A TypedFlag is a Flag whose value can be read without a type assertion
+type TypedFlag[T any] struct {
+	*Flag
+	p *T
+}
+
+// Get returns the flag's current value
+func (f *TypedFlag[T]) Get() T { return *f.p }
+
+// Add defines a flag of type T with the specified name, default value, and usage string, returning a TypedFlag.
+// T may be any of the types with a constructor method on Command, such as int, string, or time.Duration,
+// or a type whose pointer implements Getter. Add panics for other types.
+func Add[T any](c *Command, name string, value T, usage string, short bool) *TypedFlag[T] {
+	p := new(T)
+	*p = value
+	flag := c.pointerVar(p, name, usage, short)
+	if flag == nil {
+		panic(c.sprintf("flag %q has unsupported type %T", name, value))
+	}
+	return &TypedFlag[T]{Flag: flag, p: p}
+}
+
+// pointerVar defines a flag storing its value at p, which points to a type with a constructor method on Command,
+// or implements Getter, using p's current value as the default. It returns nil for pointers to other types.
+func (c *Command) pointerVar(p any, name, usage string, short bool) *Flag {
+	switch p := p.(type) {
+	case Getter:
+		return c.Var(p, name, usage, short)
+	case *bool:
+		return c.Bool(p, name, *p, usage, short)
+	case *int:
+		return c.Int(p, name, *p, usage, short)
+	case *int64:
+		return c.Int64(p, name, *p, usage, short)
+	case *uint:
+		return c.Uint(p, name, *p, usage, short)
+	case *uint64:
+		return c.Uint64(p, name, *p, usage, short)
+	case *float64:
+		return c.Float64(p, name, *p, usage, short)
+	case *string:
+		return c.String(p, name, *p, usage, short)
+	case *time.Duration:
+		return c.Duration(p, name, *p, usage, short)
+	case *[]time.Duration:
+		return c.DurationSlice(p, name, *p, usage, short)
+	case *[]string:
+		return c.StringSlice(p, name, *p, usage, short)
+	case *[]int:
+		return c.IntSlice(p, name, *p, usage, short)
+	case *[]uint:
+		return c.UintSlice(p, name, *p, usage, short)
+	case *[]float64:
+		return c.Float64Slice(p, name, *p, usage, short)
+	case *TimeWindow:
+		return c.TimeWindow(p, name, *p, usage, short)
+	case *os.FileMode:
+		return c.FileMode(p, name, *p, usage, short)
+	case *os.Signal:
+		return c.Signal(p, name, *p, usage, short)
+	case *slog.Level:
+		return c.LogLevel(p, name, *p, usage, short)
+	case *[]byte:
+		return c.Bytes(p, name, *p, usage, short)
+	case *Color:
+		return c.Color(p, name, *p, usage, short)
+	case *UUID:
+		return c.UUID(p, name, *p, usage, short)
+	case *ULID:
+		return c.ULID(p, name, *p, usage, short)
+	case *SemVer:
+		return c.SemVer(p, name, *p, usage, short)
+	case *Constraint:
+		return c.Constraint(p, name, *p, usage, short)
+	}
+	return nil
+}
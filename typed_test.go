@@ -0,0 +1,40 @@
+package mandy
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAdd(t *testing.T) {
+	c := NewCommand("test", ContinueOnError)
+	n := Add(c, "n", 3, "a number", true)
+	name := Add(c, "name", "gopher", "a name", false)
+	wait := Add(c, "wait", time.Second, "how long to wait", false)
+	color := Add(c, "color", Color{}, "a color", false)
+
+	if n.Get() != 3 || name.Get() != "gopher" {
+		t.Errorf("defaults = %d, %q", n.Get(), name.Get())
+	}
+	if err := c.Parse("-n", "5", "--wait=1m", "--color", "#ff0000"); err != nil {
+		t.Fatal(err)
+	}
+	if n.Get() != 5 || wait.Get() != time.Minute || color.Get().String() != "#ff0000" {
+		t.Errorf("parsed = %d, %v, %v", n.Get(), wait.Get(), color.Get())
+	}
+	if c.Lookup("n") != n.Flag || n.Value.Get() != 5 {
+		t.Error("the typed flag isn't the registered flag")
+	}
+	if usage := c.UsageString(); !strings.Contains(usage, "--name\ta name [default: gopher]") {
+		t.Errorf("usage lacks name:\n%s", usage)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Add accepted an unsupported type")
+		}
+	}()
+	c.SetOutput(io.Discard)
+	Add(c, "chan", make(chan int), "a channel", false)
+}
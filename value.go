@@ -216,6 +216,15 @@ func (d *durationValue) Set(s string) error {
 	return err
 }
 
+// parseDuration wraps time.ParseDuration, reporting failures as errParse
+func parseDuration(s string) (time.Duration, error) {
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, errParse
+	}
+	return v, nil
+}
+
 func (d *durationValue) Get() any       { return time.Duration(*d) }
 func (d *durationValue) String() string { return (*time.Duration)(d).String() }
 func (b *durationValue) IsBool() bool   { return false }
@@ -227,3 +236,37 @@ func (f funcValue) Set(s string) error { return f(s) }
 func (f funcValue) String() string     { return "" }
 func (f funcValue) Get() any           { return f }
 func (b funcValue) IsBool() bool       { return false }
+
+// -- help Value
+// a boolean flag which may also name the format help should be rendered in
+type helpValue struct {
+	set    bool
+	format HelpFormat
+}
+
+func newHelpValue() *helpValue {
+	return new(helpValue)
+}
+
+func (h *helpValue) Set(s string) error {
+	switch format := HelpFormat(s); format {
+	case HelpPlain, HelpMan, HelpMarkdown, HelpJSON:
+		h.set, h.format = true, format
+		return nil
+	}
+	v, err := strconv.ParseBool(s)
+	if err != nil {
+		return errParse
+	}
+	h.set, h.format = v, ""
+	return nil
+}
+
+func (h *helpValue) Get() any { return h.set }
+func (h *helpValue) String() string {
+	if h.format != "" {
+		return string(h.format)
+	}
+	return strconv.FormatBool(h.set)
+}
+func (h *helpValue) IsBool() bool { return true }
@@ -0,0 +1,39 @@
+package mandy
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ValueFilePrefix marks the values of FromFile flags that name the file holding the value
+const ValueFilePrefix = "@"
+
+// FromFile lets the flag's value be read from a file, named by a value such as "@path",
+// so that secrets and long payloads can be kept off the command line. One trailing newline is dropped.
+// A value beginning with "@@" stands for itself, less the first "@". It applies to values from the
+// command line, the environment, the program, and config files, but not to those of untrusted project-local
+// config files (see RequireTrust), which are taken as they are, lest a cloned repository read the user's files.
+// Use Restrict to narrow the sources further. Response files (see Command.ResponseFiles) never expand
+// flag values, so "--flag @path" and "--flag=@path" both read the value from path.
+func (f *Flag) FromFile() *Flag {
+	f.fromFile = true
+	return f
+}
+
+// indirect returns the value of a FromFile flag that the given value, from origin, refers to
+func (f *Flag) indirect(value string, origin Origin) (string, error) {
+	if !f.fromFile || !origin.trusted() || !strings.HasPrefix(value, ValueFilePrefix) {
+		return value, nil
+	}
+	path := value[len(ValueFilePrefix):]
+	if strings.HasPrefix(path, ValueFilePrefix) {
+		return path, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("%w: reading value from file: %v", errParse, err)
+	}
+	text := strings.TrimSuffix(string(data), "\n")
+	return strings.TrimSuffix(text, "\r"), nil
+}
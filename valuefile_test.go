@@ -0,0 +1,55 @@
+package mandy
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFromFile(t *testing.T) {
+	dir := t.TempDir()
+	token := filepath.Join(dir, "token")
+	if err := os.WriteFile(token, []byte("s3cret\r\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	port := filepath.Join(dir, "port")
+	if err := os.WriteFile(port, []byte("8080\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var (
+		secret, handle, plain string
+		n                     int
+	)
+	c := NewCommand("tool", ContinueOnError)
+	c.SetEnviron(MapEnviron(map[string]string{"TOOL_PORT": "@" + port}))
+	c.String(&secret, "token", "", "api token", false).FromFile()
+	c.String(&handle, "handle", "", "", false).FromFile()
+	c.String(&plain, "plain", "", "", false)
+	c.Int(&n, "port", 0, "", false).FromFile().Env("TOOL_PORT")
+	if err := c.Parse("--token=@"+token, "--handle", "@@me", "--plain", "@"+token); err != nil {
+		t.Fatal(err)
+	}
+	if secret != "s3cret" || handle != "@me" || plain != "@"+token || n != 8080 {
+		t.Errorf("token, handle, plain, port = %q, %q, %q, %d, want s3cret, @me, the path itself, and 8080", secret, handle, plain, n)
+	}
+	if !strings.Contains(c.Lookup("token").description(), "(or @file)") {
+		t.Errorf("description %q doesn't mention @file", c.Lookup("token").description())
+	}
+
+	err := c.Clone().Parse("--port=@" + token)
+	if !errors.Is(err, errParse) {
+		t.Errorf("reading an int from a file holding text = %v, want errParse", err)
+	}
+	if err := c.Clone().Parse("--token=@" + filepath.Join(dir, "missing")); !errors.Is(err, errParse) {
+		t.Errorf("reading a missing file = %v, want errParse", err)
+	}
+
+	untrusted := Origin{Source: SourceConfig, Path: ".toolrc", Untrusted: true}
+	for origin, want := range map[Origin]string{untrusted: "@" + token, {Source: SourceConfig, Path: "config"}: "s3cret"} {
+		if got, err := c.Lookup("token").indirect("@"+token, origin); err != nil || got != want {
+			t.Errorf("value from %+v = %q, %v, want %q", origin, got, err, want)
+		}
+	}
+}